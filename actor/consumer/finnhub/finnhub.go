@@ -1,20 +1,25 @@
 package finnhub
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
+	"sync"
+	"time"
 
 	//"os"
-	"github.com/Scrimzay/stockspider/actor/symbol"
+	symbolActor "github.com/Scrimzay/stockspider/actor/symbol"
+	"github.com/Scrimzay/stockspider/bus"
 	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
 	"strings"
 
-	"github.com/Scrimzay/loglogger"
 	"github.com/anthdm/hollywood/actor"
 	"github.com/gorilla/websocket"
-	"github.com/joho/godotenv"
 	"github.com/valyala/fastjson"
 )
 
@@ -27,44 +32,107 @@ const wsEndpoint = "wss://ws.finnhub.io?token="
 var symbols = []string{
 	"",
 }
-var log *logger.Logger
 
-func init() {
-	var err error
-	log, err = logger.New("finnhubPackage.txt")
-	if err != nil {
-		log.Fatalf("Error starting logger in Finnhub package: %v", err)
-	}
+// Mailbox is the depth of the finnhub actor's mailbox, it only gets
+// symbol changes off the bus
+var Mailbox = metrics.NewMailbox("finnhub", 0)
 
-	err = godotenv.Load(".env")
-	if err != nil {
-		log.Fatalf("Error loading .env file")
-	}
+// Policy restarts a crashed finnhub actor, the restart dials a fresh
+// websocket and subscribes the symbol from the snapshot again
+var Policy = supervise.Policy{
+	MaxRestarts: 10,
+	Delay:       2 * time.Second,
+}
+
+// what a restarted actor subscribes again
+type subscriptions struct {
+	symbol string
+	watched []string
 }
 
+var snapshots = supervise.NewSnapshots[subscriptions]()
+
+// what writes get while the first dial hasn't gone through
+var errNotConnected = errors.New("not connected to finnhub websocket")
+
+// redial backoff when the websocket drops
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
 type FinnhubClient struct {
+	mu sync.Mutex // guards ws writes and swaps, currentSymbol and watched
 	ws *websocket.Conn
 	symbols map[string]*actor.PID
-	c *actor.Context
-	tradeCh chan event.StockTrade
+	engine *actor.Engine
 	currentSymbol string
+	watched map[string]bool // symbols streamed besides the current one, trades only
+	log *slog.Logger
+
+	// cancelled when the actor stops, reconnect gives up on it instead of
+	// dialing a connection nothing would close
+	ctx context.Context
+	cancel context.CancelFunc
 }
 
 func (f *FinnhubClient) Receive(c *actor.Context) {
 	switch msg :=  c.Message().(type) {
 	case actor.Started:
+		f.engine = c.Engine()
+		c.Send(bus.PID(c.Engine()), bus.Subscribe{
+			Pattern: string(bus.Selected),
+			PID: c.PID(),
+			Filter: bus.Filter[event.Selected](nil),
+			Mailbox: Mailbox,
+		})
+		c.Send(bus.PID(c.Engine()), bus.Subscribe{
+			Pattern: string(bus.Watched),
+			PID: c.PID(),
+			Filter: bus.Filter[event.Watched](nil),
+			Mailbox: Mailbox,
+		})
 		f.start(c)
-		f.c = c
-	case string: // handle symbol change messages
-		f.ChangeSymbol(msg)
+	case actor.Stopped:
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{PID: c.PID()})
+		// before the lock, so a reconnect that gets it after us sees it
+		f.cancel()
+		// ends wsLoop, a restart dials its own connection
+		f.mu.Lock()
+		if f.ws != nil {
+			f.ws.Close()
+		}
+		saved := subscriptions{symbol: f.currentSymbol}
+		for symbol := range f.watched {
+			saved.watched = append(saved.watched, symbol)
+		}
+		snapshots.Save("finnhub", saved)
+		f.mu.Unlock()
+	case event.Selected: // handle symbol change messages
+		Mailbox.Handled()
+		f.ChangeSymbol(msg.Pair.Symbol)
+	case event.Watched:
+		Mailbox.Handled()
+		symbols := make([]string, len(msg.Pairs))
+		for i, pair := range msg.Pairs {
+			symbols[i] = pair.Symbol
+		}
+		f.Watch(symbols)
 	}
 }
 
-func New(tradeCh chan event.StockTrade) actor.Producer {
+// New streams the websocket onto the bus, trades and stats on bus.Trades
+// and bus.Stats, headlines on bus.WSNews. it follows bus.Selected and
+// streams the trades of bus.Watched as well
+func New(log *slog.Logger) actor.Producer {
 	return func() actor.Receiver {
+		ctx, cancel := context.WithCancel(context.Background())
 		return &FinnhubClient{
 			symbols: make(map[string]*actor.PID),
-			tradeCh: tradeCh,
+			watched: make(map[string]bool),
+			log: log,
+			ctx: ctx,
+			cancel: cancel,
 		}
 	}
 }
@@ -76,15 +144,33 @@ func (f *FinnhubClient) start(c *actor.Context) {
 			Exchange: "finnhub",
 			Symbol: strings.ToLower(sym),
 		}
-		pid := c.SpawnChild(symbol.New(pair), "symbol")
+		pid := c.SpawnChild(symbolActor.New(pair, f.log), "symbol", symbolActor.Policy.Opts()...)
 		f.symbols[pair.Symbol] = pid
 	}
 	ws, _, err := websocket.DefaultDialer.Dial(createWsEndpoint(), nil)
 	if err != nil {
-		log.Fatal(err)
+		// not fatal, wsLoop keeps dialing with the reconnect backoff
+		f.log.Error("dialing finnhub websocket", "err", err)
+	} else {
+		f.mu.Lock()
+		f.ws = ws
+		f.mu.Unlock()
+		metrics.SetConnected(true)
+		f.subscribeSymbols()
+		f.log.Info("connected to finnhub websocket")
+	}
+	if saved, ok := snapshots.Load("finnhub"); ok {
+		f.Watch(saved.watched)
+		if saved.symbol != "" {
+			f.ChangeSymbol(saved.symbol)
+		}
 	}
-	f.ws = ws
 
+    go f.wsLoop()
+}
+
+// subscribeSymbols subscribes the trades of symbols on a fresh connection
+func (f *FinnhubClient) subscribeSymbols() {
     // Make sure you're subscribing to trades
     for _, sym := range symbols {
 		msg := struct {
@@ -95,58 +181,176 @@ func (f *FinnhubClient) start(c *actor.Context) {
 			Symbol: sym,
 		}
 		//log.Printf("Subscribing to symbol: %s", msg.Symbol)
-		if err := f.ws.WriteJSON(msg); err != nil {
-			log.Printf("Subscribe error for %s: %v", sym, err)
+		if err := f.write(msg); err != nil {
+			f.log.Error("subscribing", "symbol", sym, "err", err)
 		}
 	}
-    log.Printf("Subscribed to symbol %s for trades", f.currentSymbol)
-
-    go f.wsLoop()
 }
 
 func (f *FinnhubClient) wsLoop() {
 	var lastPrices = make(map[string]float64)
 
+	// the first dial failed, keep at it before reading anything
+	if !f.connected() {
+		if !f.reconnect() {
+			return
+		}
+		f.subscribeSymbols()
+	}
+
 	for {
 		_, msg, err := f.ws.ReadMessage()
 		if err != nil {
-			if errors.Is(err, net.ErrClosed) {
+			metrics.SetConnected(false)
+			if errors.Is(err, net.ErrClosed) || f.ctx.Err() != nil {
+				break
+			}
+			// a read error leaves the connection dead, every read after
+			// it fails the same way so dial a new one
+			f.log.Error("reading from websocket", "err", err)
+			if !f.reconnect() {
 				break
 			}
-			log.Printf("Error reading from ws connection: %v", err)
 			continue
 		}
 
 		parser := fastjson.Parser{}
 		v, err := parser.ParseBytes(msg)
 		if err != nil {
-			log.Printf("Failed to parse msg: %v", err)
+			f.log.Warn("parsing websocket message", "err", err)
 			continue
 		}
 
 		// Handle different types of messages
 		msgType := string(v.GetStringBytes("type"))
-		log.Printf("Message type: %s", msgType)
+		f.log.Debug("websocket message", "type", msgType)
+		metrics.WSMessage(msgType)
 		switch msgType {
 		case "trade":
 			f.handleTrades(v.Get("data"), lastPrices)
+		case "news":
+			f.handleNews(v.Get("data"))
 		case "ping":
 			// response to keep-alive
-			f.ws.WriteJSON(struct {
+			f.write(struct {
 				Type string `json:"type"`
 			}{
 				Type: "pong",
 			})
 		default:
-			log.Printf("Unknown message type: %s", msgType)
+			f.log.Warn("unknown websocket message type", "type", msgType)
 		}
 	}
 }
 
+// write sends msg on the current connection, wsLoop can swap it underneath
+// us when it reconnects
+func (f *FinnhubClient) write(msg any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.ws == nil {
+		return errNotConnected
+	}
+	return f.ws.WriteJSON(msg)
+}
+
+// connected reports whether a dial ever went through, a dropped connection
+// still counts until reconnect swaps it
+func (f *FinnhubClient) connected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.ws != nil
+}
+
+// reconnect dials until it gets a connection back, then resubscribes the
+// current symbol. it reports false when the actor stopped first, wsLoop
+// has nothing left to read then
+func (f *FinnhubClient) reconnect() bool {
+	f.mu.Lock()
+	if f.ws != nil {
+		f.ws.Close()
+	}
+	f.mu.Unlock()
+
+	delay := minReconnectDelay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-f.ctx.Done():
+			return false
+		}
+		ws, _, err := websocket.DefaultDialer.DialContext(f.ctx, createWsEndpoint(), nil)
+		if err != nil {
+			if f.ctx.Err() != nil {
+				return false
+			}
+			f.log.Warn("reconnect failed", "retry", delay, "err", err)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+
+		f.mu.Lock()
+		// stopped while dialing, Stopped already closed what it could see
+		if f.ctx.Err() != nil {
+			f.mu.Unlock()
+			ws.Close()
+			return false
+		}
+		f.ws = ws
+		symbol := f.currentSymbol
+		watched := make([]string, 0, len(f.watched))
+		for s := range f.watched {
+			if s != symbol {
+				watched = append(watched, s)
+			}
+		}
+		f.mu.Unlock()
+		metrics.SetConnected(true)
+		metrics.Reconnected()
+		f.log.Info("reconnected to finnhub websocket")
+
+		if symbol != "" {
+			for _, msgType := range []string{"subscribe", "subscribe-news"} {
+				err := f.write(struct {
+					Type string `json:"type"`
+					Symbol string `json:"symbol"`
+				}{
+					Type: msgType,
+					Symbol: symbol,
+				})
+				if err != nil {
+					f.log.Error("resubscribing", "symbol", symbol, "type", msgType, "err", err)
+				}
+			}
+		}
+		for _, s := range watched {
+			if err := f.send("subscribe", s); err != nil {
+				f.log.Error("resubscribing watched symbol", "symbol", s, "err", err)
+			}
+		}
+		return true
+	}
+}
+
 func (f *FinnhubClient) ChangeSymbol(newSymbol string) {
-	log.Printf("Changing symbol from %s to %s", f.currentSymbol, newSymbol)
+	f.log.Info("changing symbol", "from", f.currentSymbol, "to", newSymbol)
+
+	// nothing to unsubscribe yet, reconnect subscribes it once it dials
+	if !f.connected() {
+		f.mu.Lock()
+		f.currentSymbol = newSymbol
+		f.mu.Unlock()
+		return
+	}
+
+	f.mu.Lock()
+	stillWatched := f.watched[f.currentSymbol]
+	f.mu.Unlock()
 
-	// unsub from current symbol
+	// unsub from current symbol, its trades stay when something else
+	// still shows it
 	if f.currentSymbol != "" {
 		unsubMsg := struct {
 			Type string `json:"type"`
@@ -155,8 +359,14 @@ func (f *FinnhubClient) ChangeSymbol(newSymbol string) {
 			Type: "unsubscribe",
 			Symbol: f.currentSymbol,
 		}
-		if err := f.ws.WriteJSON(unsubMsg); err != nil {
-            log.Printf("Error unsubscribing: %v", err)
+		if !stillWatched {
+			if err := f.write(unsubMsg); err != nil {
+				f.log.Error("unsubscribing", "symbol", f.currentSymbol, "err", err)
+			}
+		}
+		unsubMsg.Type = "unsubscribe-news"
+		if err := f.write(unsubMsg); err != nil {
+            f.log.Error("unsubscribing from news", "symbol", f.currentSymbol, "err", err)
         }
 	}
 
@@ -168,12 +378,66 @@ func (f *FinnhubClient) ChangeSymbol(newSymbol string) {
         Type: "subscribe",
         Symbol: newSymbol,
     }
-    if err := f.ws.WriteJSON(subTradeMsg); err != nil {
-        log.Printf("Error subscribing: %v", err)
+    if err := f.write(subTradeMsg); err != nil {
+        f.log.Error("subscribing", "symbol", newSymbol, "err", err)
 		return
     }
 
+	// news comes in over the same socket, the news consumer picks it up
+	subTradeMsg.Type = "subscribe-news"
+	if err := f.write(subTradeMsg); err != nil {
+		f.log.Error("subscribing to news", "symbol", newSymbol, "err", err)
+	}
+
+	f.mu.Lock()
     f.currentSymbol = newSymbol
+	f.mu.Unlock()
+}
+
+// Watch makes the trades of symbols stream besides the current symbol's,
+// symbols that aren't in it anymore are unsubscribed
+func (f *FinnhubClient) Watch(symbols []string) {
+	watched := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		if s != "" {
+			watched[s] = true
+		}
+	}
+	f.mu.Lock()
+	old := f.watched
+	f.watched = watched
+	current := f.currentSymbol
+	f.mu.Unlock()
+
+	// reconnect subscribes the lot once it dials
+	if !f.connected() {
+		return
+	}
+	for s := range watched {
+		if !old[s] && s != current {
+			if err := f.send("subscribe", s); err != nil {
+				f.log.Error("subscribing watched symbol", "symbol", s, "err", err)
+			}
+		}
+	}
+	for s := range old {
+		if !watched[s] && s != current {
+			if err := f.send("unsubscribe", s); err != nil {
+				f.log.Error("unsubscribing watched symbol", "symbol", s, "err", err)
+			}
+		}
+	}
+}
+
+// send writes a subscribe or unsubscribe message for symbol
+func (f *FinnhubClient) send(msgType, symbol string) error {
+	return f.write(struct {
+		Type string `json:"type"`
+		Symbol string `json:"symbol"`
+	}{
+		Type: msgType,
+		Symbol: symbol,
+	})
 }
 
 func (f *FinnhubClient) handleTrades(data *fastjson.Value, lastPrices map[string]float64) {
@@ -184,7 +448,7 @@ func (f *FinnhubClient) handleTrades(data *fastjson.Value, lastPrices map[string
 	// get the array of trades
 	trades, err := data.Array()
 	if err != nil {
-		log.Printf("Error getting trades array: %v", err)
+		f.log.Warn("reading trades array", "err", err)
 		return
 	}
 
@@ -198,7 +462,7 @@ func (f *FinnhubClient) handleTrades(data *fastjson.Value, lastPrices map[string
         qtyVal := trade.Get("v")
         
         if priceVal == nil || qtyVal == nil {
-            log.Printf("Missing price or quantity data in trade: %s", trade.String())
+            f.log.Warn("trade missing price or quantity", "trade", trade.String())
             continue
         }
 
@@ -225,22 +489,60 @@ func (f *FinnhubClient) handleTrades(data *fastjson.Value, lastPrices map[string
 			},
 		}
 
-		f.tradeCh <- stockTrade
+		// a full bus drops it rather than holding up the reader
+		bus.Publish(f.engine, bus.Trades(stockTrade.Pair), stockTrade)
+
+		// also create and send a stat event
+		// Note: finnhub doesnt provide funding rate, so we set to 0
+		bus.Publish(f.engine, bus.Stats(stockTrade.Pair), event.Stat{
+			Pair: stockTrade.Pair,
+			MarkPrice: price,
+			Funding: 0,
+			Unix: stockTrade.Unix,
+		})
+	}
+}
+
+// handleNews publishes websocket headlines on bus.WSNews, the news
+// consumer de-dups them against what it already polled
+func (f *FinnhubClient) handleNews(data *fastjson.Value) {
+	if data == nil {
+		return
+	}
+
+	items, err := data.Array()
+	if err != nil {
+		f.log.Warn("reading news array", "err", err)
+		return
+	}
 
-		// forward to symbol actor if it exists
-		if symbolPID, ok := f.symbols[symbol]; ok {
-			f.c.Send(symbolPID, stockTrade)
+	f.mu.Lock()
+	current := f.currentSymbol
+	f.mu.Unlock()
 
-			// also create and send a stat event
-			// Note: finnhub doesnt provide funding rate, so we set to 0
-			stat := event.Stat{
-				Pair: stockTrade.Pair,
-				MarkPrice: price,
-				Funding: 0,
-				Unix: stockTrade.Unix,
-			}
-			f.c.Send(symbolPID, stat)
+	for _, item := range items {
+		related := string(item.GetStringBytes("related"))
+		symbol := current
+		if related != "" && !strings.EqualFold(related, current) {
+			symbol = related
+		}
+
+		pair := event.Pair{
+			Exchange: "finnhub",
+			Symbol: symbol,
 		}
+		bus.Publish(f.engine, bus.WSNews(pair), event.News{
+			Pair: pair,
+			ID: item.GetInt64("id"),
+			Category: string(item.GetStringBytes("category")),
+			Headline: string(item.GetStringBytes("headline")),
+			Summary: string(item.GetStringBytes("summary")),
+			Source: string(item.GetStringBytes("source")),
+			URL: string(item.GetStringBytes("url")),
+			Image: string(item.GetStringBytes("image")),
+			Related: related,
+			Unix: item.GetInt64("datetime"),
+		})
 	}
 }
 
@@ -0,0 +1,278 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+	"github.com/anthdm/hollywood/actor"
+)
+
+// Mailbox is the depth of the news actor's mailbox
+var Mailbox = metrics.NewMailbox("news", 0)
+
+// Policy restarts a crashed news consumer, what it was watching and had
+// seen comes back from the snapshot so nothing is published twice
+var Policy = supervise.Policy{
+	MaxRestarts: 5,
+	Delay:       5 * time.Second,
+}
+
+// what a restarted consumer picks up again
+type state struct {
+	watching string
+	seen     map[string]*seenSet
+}
+
+var snapshots = supervise.NewSnapshots[state]()
+
+// how often company and market news get polled, finnhub only
+// updates them every few minutes anyway
+const pollInterval = time.Minute
+
+// how far back company news is requested
+const lookback = 7 * 24 * time.Hour
+
+// how many headline keys are remembered per symbol before the
+// oldest get forgotten
+const maxSeen = 500
+
+type poll struct{}
+
+// Consumer polls finnhub company and market news and publishes every
+// headline it hasn't seen before for that symbol on bus.News. websocket
+// news from bus.WSNews goes through the same de-dup. company news is
+// polled for whatever symbol bus.Selected last said
+type Consumer struct {
+	client   *FH.DefaultApiService
+	engine   *actor.Engine
+	watching string
+	seen     map[string]*seenSet // keyed by full symbol, "" is market news
+	repeater actor.SendRepeater
+	log      *slog.Logger
+}
+
+func New(client *FH.DefaultApiService, log *slog.Logger) actor.Producer {
+	return func() actor.Receiver {
+		return &Consumer{
+			client: client,
+			seen:   make(map[string]*seenSet),
+			log:    log,
+		}
+	}
+}
+
+func (n *Consumer) Receive(c *actor.Context) {
+	switch msg := c.Message().(type) {
+	case actor.Started:
+		n.engine = c.Engine()
+		if st, ok := snapshots.Load("news"); ok {
+			n.watching, n.seen = st.watching, st.seen
+		}
+		for _, sub := range []bus.Subscribe{
+			{Pattern: string(bus.Selected), Filter: bus.Filter[event.Selected](nil)},
+			{Pattern: "wsnews.*", Filter: bus.Filter[event.News](nil)},
+		} {
+			sub.PID = c.PID()
+			sub.Mailbox = Mailbox
+			c.Send(bus.PID(c.Engine()), sub)
+		}
+		n.repeater = c.SendRepeat(c.PID(), poll{}, pollInterval)
+		n.poll()
+	case actor.Stopped:
+		n.repeater.Stop()
+		// the crashed receiver is thrown away, its seen sets can be handed on as is
+		snapshots.Save("news", state{watching: n.watching, seen: n.seen})
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{PID: c.PID()})
+	case event.Selected:
+		Mailbox.Handled()
+		if msg.Pair.Symbol == n.watching {
+			return
+		}
+		n.watching = msg.Pair.Symbol
+		n.pollSymbol()
+	case poll:
+		n.poll()
+	case event.News:
+		Mailbox.Handled()
+		n.publish(msg)
+	}
+}
+
+func (n *Consumer) poll() {
+	n.pollMarket()
+	n.pollSymbol()
+}
+
+func (n *Consumer) pollMarket() {
+	items, err := n.market("general", "")
+	if err != nil {
+		n.log.Error("fetching market news", "err", err)
+		return
+	}
+	n.publish(items...)
+}
+
+// pollSymbol fetches news for the watched symbol. finnhub has no company
+// news for crypto so crypto symbols get the crypto market news instead
+func (n *Consumer) pollSymbol() {
+	if n.watching == "" {
+		return
+	}
+	pair := event.Pair{Exchange: "finnhub", Symbol: n.watching}
+
+	var items []event.News
+	var err error
+	if calendar.For(pair) == calendar.Crypto {
+		items, err = n.market("crypto", n.watching)
+	} else {
+		items, err = n.company(n.watching, time.Now())
+	}
+	if err != nil {
+		n.log.Error("fetching news", "symbol", n.watching, "err", err)
+		return
+	}
+	n.publish(items...)
+}
+
+func (n *Consumer) company(symbol string, now time.Time) ([]event.News, error) {
+	res, _, err := n.client.CompanyNews(context.Background()).
+		Symbol(symbol).
+		From(now.Add(-lookback).Format("2006-01-02")).
+		To(now.Format("2006-01-02")).
+		Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]event.News, 0, len(res))
+	for i := range res {
+		items = append(items, toNews(&res[i], symbol))
+	}
+	return items, nil
+}
+
+// market fetches a market news category and files it under symbol
+func (n *Consumer) market(category, symbol string) ([]event.News, error) {
+	res, _, err := n.client.MarketNews(context.Background()).Category(category).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]event.News, 0, len(res))
+	for i := range res {
+		items = append(items, toNews(&res[i], symbol))
+	}
+	return items, nil
+}
+
+// the generated company and market news models share these getters
+type finnhubNews interface {
+	GetId() int64
+	GetCategory() string
+	GetHeadline() string
+	GetSummary() string
+	GetSource() string
+	GetUrl() string
+	GetImage() string
+	GetRelated() string
+	GetDatetime() int64
+}
+
+func toNews(r finnhubNews, symbol string) event.News {
+	return event.News{
+		Pair:     event.Pair{Exchange: "finnhub", Symbol: symbol},
+		ID:       r.GetId(),
+		Category: r.GetCategory(),
+		Headline: r.GetHeadline(),
+		Summary:  r.GetSummary(),
+		Source:   r.GetSource(),
+		URL:      r.GetUrl(),
+		Image:    r.GetImage(),
+		Related:  r.GetRelated(),
+		Unix:     r.GetDatetime(),
+	}
+}
+
+// publish sends on the headlines not seen yet for their symbol, oldest first
+func (n *Consumer) publish(items ...event.News) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Unix < items[j].Unix
+	})
+
+	for _, item := range items {
+		if item.Headline == "" {
+			continue
+		}
+		seen, ok := n.seen[item.Pair.Symbol]
+		if !ok {
+			seen = newSeenSet(maxSeen)
+			n.seen[item.Pair.Symbol] = seen
+		}
+		key := Key(item)
+		if seen.has(key) {
+			continue
+		}
+		// never blocks, a bus too far behind drops it and counts it in the
+		// bus's mailbox drops. it stays unseen so the next poll tries again
+		if !bus.Publish(n.engine, bus.News(item.Pair), item) {
+			n.log.Warn("bus full, headline dropped", "symbol", item.Pair.Symbol, "key", key)
+			continue
+		}
+		seen.add(key)
+	}
+}
+
+// Key identifies a headline for de-duplication. finnhub ids are stable
+// between the rest endpoints and the websocket, the url covers items
+// that come without one
+func Key(item event.News) string {
+	if item.ID != 0 {
+		return fmt.Sprintf("id:%d", item.ID)
+	}
+	if item.URL != "" {
+		return "url:" + item.URL
+	}
+	return "headline:" + item.Headline
+}
+
+// seenSet remembers the last max keys it was given
+type seenSet struct {
+	keys  map[string]bool
+	order []string
+	max   int
+}
+
+func newSeenSet(max int) *seenSet {
+	return &seenSet{
+		keys: make(map[string]bool),
+		max:  max,
+	}
+}
+
+func (s *seenSet) has(key string) bool {
+	return s.keys[key]
+}
+
+// add reports whether key is new
+func (s *seenSet) add(key string) bool {
+	if s.keys[key] {
+		return false
+	}
+	s.keys[key] = true
+	s.order = append(s.order, key)
+	if len(s.order) > s.max {
+		delete(s.keys, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true
+}
@@ -1,21 +1,54 @@
 package stat
 
 import (
-	"fmt"
-	"github.com/Scrimzay/stockspider/event"
+	"log/slog"
 	"time"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
 
 	"github.com/anthdm/hollywood/actor"
 )
 
+// how many stats the stat actors may have waiting between them
+const mailboxSize = 10000
+
+// how many mark prices the indicator window spans
+const windowSize = 200
+
+// the window is snapshotted every this many stats, and when the actor stops
+const snapshotEvery = 50
+
+// Mailbox is the depth of the stat actors' mailboxes, Reserve before
+// sending
+var Mailbox = metrics.NewMailbox("stat", mailboxSize)
+
+// Policy restarts a crashed stat actor quickly, its window comes back from
+// the last snapshot. past the limit the symbol actor spawns a new one
+var Policy = supervise.Policy{
+	MaxRestarts:  10,
+	Delay:        100 * time.Millisecond,
+	Respawns:     3,
+	RespawnDelay: 5 * time.Second,
+}
+
+// windows outlive the actors, keyed by symbol
+var snapshots = supervise.NewSnapshots[Window]()
+
 type Stat struct {
 	pair event.Pair
+	log *slog.Logger
+	latest event.Stat
+	window Window
 }
 
-func New(pair event.Pair) actor.Producer {
+// log already carries the pair, it comes from the parent symbol actor
+func New(pair event.Pair, log *slog.Logger) actor.Producer {
 	return func () actor.Receiver {
 		return &Stat{
 			pair: pair,
+			log: log,
+			window: NewWindow(windowSize),
 		}
 	}
 }
@@ -23,9 +56,21 @@ func New(pair event.Pair) actor.Producer {
 func (s *Stat) Receive(c *actor.Context) {
 	switch v := c.Message().(type) {
 	case actor.Started:
-		fmt.Printf("Stat started: %v\n", s.pair)
+		if window, ok := snapshots.Load(s.pair.Symbol); ok {
+			s.window = window
+			s.log.Info("stat window restored", "prices", len(window.Prices), "count", window.Count)
+		}
+		s.log.Debug("stat started")
+	case actor.Stopped:
+		// hollywood stops a crashed actor before restarting it too
+		snapshots.Save(s.pair.Symbol, s.window.Clone())
 	case event.Stat:
-		fmt.Printf("Stat: %v\n", v)
-		time.Sleep(2 * time.Second)
+		Mailbox.Handled()
+		s.latest = v
+		s.window.Add(v.MarkPrice)
+		if s.window.Count%snapshotEvery == 0 {
+			snapshots.Save(s.pair.Symbol, s.window.Clone())
+		}
+		s.log.Debug("stat", "stat", v, "ema", s.window.EMA)
 	}
-}
\ No newline at end of file
+}
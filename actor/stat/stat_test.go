@@ -0,0 +1,110 @@
+package stat
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/logging"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// boom makes a crashing stat actor panic
+type boom struct{}
+
+// crashing is a stat actor that panics on boom. the first stat holds it up
+// until gate closes, so everything sent meanwhile lands in the same batch
+// as the boom and has to be replayed after the restart
+type crashing struct {
+	*Stat
+	gate     chan struct{}
+	gated    *atomic.Bool
+	booms    *atomic.Int32
+	restored chan Window // the window of each receiver once it started
+}
+
+func (s *crashing) Receive(c *actor.Context) {
+	switch c.Message().(type) {
+	case boom:
+		s.booms.Add(1)
+		panic("boom")
+	case event.Stat:
+		if s.gated.CompareAndSwap(false, true) {
+			<-s.gate
+		}
+	}
+	s.Stat.Receive(c)
+	if _, ok := c.Message().(actor.Started); ok {
+		s.restored <- s.window.Clone()
+	}
+}
+
+func TestRestartRestoresWindow(t *testing.T) {
+	e, err := actor.NewEngine(actor.NewEngineConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair := event.Pair{Exchange: "test", Symbol: "TEST:RESTART"}
+	snapshots.Delete(pair.Symbol)
+
+	c := &crashing{
+		gate:     make(chan struct{}),
+		gated:    new(atomic.Bool),
+		booms:    new(atomic.Int32),
+		restored: make(chan Window, 4),
+	}
+	pid := e.Spawn(func() actor.Receiver {
+		// a fresh Stat for every restart, like New gives hollywood
+		s := *c
+		s.Stat = New(pair, logging.Discard())().(*Stat)
+		return &s
+	}, "stat", Policy.Opts()...)
+	if w := waitWindow(t, c.restored); w.Count != 0 {
+		t.Fatalf("first start has %d prices, want none", w.Count)
+	}
+
+	// half the window, the crash, then the other half
+	for i := 1; i <= 10; i++ {
+		e.Send(pid, event.Stat{Pair: pair, MarkPrice: float64(i)})
+	}
+	e.Send(pid, boom{})
+	for i := 11; i <= 20; i++ {
+		e.Send(pid, event.Stat{Pair: pair, MarkPrice: float64(i)})
+	}
+	close(c.gate)
+
+	w := waitWindow(t, c.restored)
+	if w.Count != 10 || len(w.Prices) != 10 || w.Prices[9] != 10 {
+		t.Fatalf("restarted with %d prices %v, want the 10 from before the crash", w.Count, w.Prices)
+	}
+
+	e.Poison(pid).Wait()
+	if n := c.booms.Load(); n != 1 {
+		t.Fatalf("the message that crashed it was handled %d times, want it dropped after once", n)
+	}
+	final, ok := snapshots.Load(pair.Symbol)
+	if !ok {
+		t.Fatal("no snapshot after stopping")
+	}
+	if final.Count != 20 || len(final.Prices) != 20 {
+		t.Fatalf("ended with %d prices, want all 20 stats", final.Count)
+	}
+	for i, price := range final.Prices {
+		if price != float64(i+1) {
+			t.Fatalf("prices %v, want 1 to 20 in order, the ones after the crash replayed", final.Prices)
+		}
+	}
+}
+
+func waitWindow(t *testing.T, ch chan Window) Window {
+	t.Helper()
+	select {
+	case w := <-ch:
+		return w
+	case <-time.After(5 * time.Second):
+		t.Fatal("actor didn't start")
+	}
+	return Window{}
+}
@@ -0,0 +1,63 @@
+package stat
+
+import "math"
+
+// Window is a stat actor's indicator state: the last Size mark prices and
+// an EMA over the same span
+type Window struct {
+	Size   int
+	Prices []float64 // oldest first
+	EMA    float64
+	Count  int64 // prices ever added
+}
+
+func NewWindow(size int) Window {
+	return Window{Size: size, Prices: make([]float64, 0, size)}
+}
+
+func (w *Window) Add(price float64) {
+	if w.Count == 0 {
+		w.EMA = price
+	} else {
+		alpha := 2 / float64(w.Size+1)
+		w.EMA += alpha * (price - w.EMA)
+	}
+	w.Count++
+
+	if len(w.Prices) == w.Size {
+		copy(w.Prices, w.Prices[1:])
+		w.Prices = w.Prices[:w.Size-1]
+	}
+	w.Prices = append(w.Prices, price)
+}
+
+// Mean is the simple average of the window, 0 when empty
+func (w Window) Mean() float64 {
+	if len(w.Prices) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range w.Prices {
+		sum += p
+	}
+	return sum / float64(len(w.Prices))
+}
+
+// StdDev is the population standard deviation of the window
+func (w Window) StdDev() float64 {
+	if len(w.Prices) < 2 {
+		return 0
+	}
+	mean := w.Mean()
+	var sum float64
+	for _, p := range w.Prices {
+		sum += (p - mean) * (p - mean)
+	}
+	return math.Sqrt(sum / float64(len(w.Prices)))
+}
+
+// Clone is a copy that doesn't share Prices with w
+func (w Window) Clone() Window {
+	w.Prices = append(make([]float64, 0, w.Size), w.Prices...)
+	return w
+}
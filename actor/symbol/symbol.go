@@ -1,23 +1,50 @@
 package symbol
 
 import (
-	"fmt"
+	"log/slog"
+	"time"
 	"github.com/Scrimzay/stockspider/actor/stat"
+	"github.com/Scrimzay/stockspider/bus"
 	"github.com/Scrimzay/stockspider/event"
-	"time"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
 
 	"github.com/anthdm/hollywood/actor"
 )
 
+// how many messages the symbol actors may have waiting between them
+// before the bus starts dropping trades for them
+const mailboxSize = 10000
+
+// Mailbox is the depth of the symbol actors' mailboxes, the bus Reserves
+// before forwarding so a busy symbol can't grow it without bound
+var Mailbox = metrics.NewMailbox("symbol", mailboxSize)
+
+// Policy restarts a crashed symbol actor, it only counts trades so there's
+// nothing to restore. its stat child keeps running through the restart
+var Policy = supervise.Policy{
+	MaxRestarts: 5,
+	Delay:       time.Second,
+}
+
+type respawnStat struct{}
+
+// newStat builds the stat child, tests swap in one that crashes
+var newStat = stat.New
+
 type Symbol struct {
 	pair event.Pair
 	statPID *actor.PID
+	respawns int // stat children spawned again after they were given up on
+	log *slog.Logger
+	trades int64
 }
 
-func New(pair event.Pair) actor.Producer {
+func New(pair event.Pair, log *slog.Logger) actor.Producer {
 	return func () actor.Receiver {
 		return &Symbol{
 			pair: pair,
+			log: log.With("pair", pair.Symbol),
 		}
 	}
 }
@@ -26,14 +53,58 @@ func (s *Symbol) Receive(c *actor.Context) {
 	switch v := c.Message().(type) {
 	case actor.Started:
 		s.start(c)
+	case actor.Stopped:
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{PID: c.PID()})
 	case event.StockTrade:
-		fmt.Printf("Trade: %v\n", v)
-		time.Sleep(2 * time.Second)
+		Mailbox.Handled()
+		s.trades++
+		s.log.Debug("trade", "price", v.Price, "qty", v.Qty, "trades", s.trades)
 	case event.Stat:
-		c.Forward(s.statPID)
+		Mailbox.Handled()
+		// the stat actor has its own bound, past it stats are dropped
+		// rather than queued behind each other
+		if s.statPID != nil && stat.Mailbox.Reserve() {
+			c.Forward(s.statPID)
+		}
+	case supervise.ChildDied:
+		if s.statPID == nil || !v.PID.Equals(s.statPID) {
+			return
+		}
+		s.statPID = nil
+		if s.respawns >= stat.Policy.Respawns {
+			s.log.Error("stat actor gave up for good", "respawns", s.respawns)
+			return
+		}
+		s.respawns++
+		s.log.Warn("respawning stat actor", "in", stat.Policy.RespawnDelay, "respawn", s.respawns)
+		self, engine := c.PID(), c.Engine()
+		time.AfterFunc(stat.Policy.RespawnDelay, func() {
+			engine.Send(self, respawnStat{})
+		})
+	case respawnStat:
+		s.spawnStat(c)
+	}
+}
+
+// spawnStat starts the stat child, unless one survived a restart of this
+// actor, hollywood keeps the children of a crashed actor running
+func (s *Symbol) spawnStat(c *actor.Context) {
+	for _, child := range c.Children() {
+		if supervise.Kind(child) == "stat" {
+			s.statPID = child
+			return
+		}
 	}
+	s.statPID = c.SpawnChild(newStat(s.pair, s.log), "stat", stat.Policy.Opts()...)
 }
 
 func (s *Symbol) start(c *actor.Context) {
-	s.statPID = c.SpawnChild(stat.New(s.pair), "stat")
-}
\ No newline at end of file
+	s.spawnStat(c)
+	for _, topic := range []bus.Topic{bus.Trades(s.pair), bus.Stats(s.pair)} {
+		c.Send(bus.PID(c.Engine()), bus.Subscribe{
+			Pattern: string(topic),
+			PID: c.PID(),
+			Mailbox: Mailbox,
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package symbol
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/actor/stat"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/logging"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// crashingStat panics on every stat and says when it started
+type crashingStat struct {
+	started chan *actor.PID
+}
+
+func (s crashingStat) Receive(c *actor.Context) {
+	switch c.Message().(type) {
+	case actor.Started:
+		s.started <- c.PID()
+	case event.Stat:
+		panic("stat")
+	}
+}
+
+func TestRespawnsStatChild(t *testing.T) {
+	started := make(chan *actor.PID, 16)
+	oldNew, oldPolicy := newStat, stat.Policy
+	newStat = func(event.Pair, *slog.Logger) actor.Producer {
+		return func() actor.Receiver { return crashingStat{started: started} }
+	}
+	stat.Policy = supervise.Policy{MaxRestarts: 1, Delay: time.Millisecond, Respawns: 1, RespawnDelay: 10 * time.Millisecond}
+	t.Cleanup(func() { newStat, stat.Policy = oldNew, oldPolicy })
+
+	e, err := actor.NewEngine(actor.NewEngineConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	supervise.Start(e, logging.Discard())
+	pair := event.Pair{Exchange: "test", Symbol: "TEST:RESPAWN"}
+	pid := e.Spawn(New(pair, logging.Discard()), "symbol")
+	// stop it before the cleanup above puts the globals back
+	t.Cleanup(func() { e.Poison(pid).Wait() })
+
+	first := waitStarted(t, started)
+	if parent := supervise.Parent(first); parent == nil || !parent.Equals(pid) {
+		t.Fatalf("stat child %s isn't the symbol actor's", first.GetID())
+	}
+
+	// one crash it restarts from, the next is past its limit of one.
+	// the supervisor sees it given up and tells the symbol actor
+	crash := func() {
+		if stat.Mailbox.Reserve() {
+			e.Send(pid, event.Stat{Pair: pair, MarkPrice: 1})
+		}
+	}
+	crash()
+	if restarted := waitStarted(t, started); !restarted.Equals(first) {
+		t.Fatalf("restart came back as %s, want the same %s", restarted.GetID(), first.GetID())
+	}
+	crash()
+	respawned := waitStarted(t, started)
+	if respawned.Equals(first) {
+		t.Fatalf("got %s started again, want a new child", first.GetID())
+	}
+	if parent := supervise.Parent(respawned); parent == nil || !parent.Equals(pid) {
+		t.Fatalf("respawned %s isn't the symbol actor's", respawned.GetID())
+	}
+
+	// past its respawns it stays dead
+	crash()
+	waitStarted(t, started)
+	crash()
+	select {
+	case pid := <-started:
+		t.Fatalf("%s started past the respawn limit", pid.GetID())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func waitStarted(t *testing.T, started chan *actor.PID) *actor.PID {
+	t.Helper()
+	select {
+	case pid := <-started:
+		return pid
+	case <-time.After(5 * time.Second):
+		t.Fatal("stat child didn't start")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package alert keeps price alerts, "AAPL above 190", and says which ones
+// a trade sets off. An alert goes off once and is gone. They're saved as
+// JSON so they survive a restart.
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Alert struct {
+	// Symbol is the full finnhub symbol
+	Symbol  string    `json:"symbol"`
+	Above   bool      `json:"above"`
+	Price   float64   `json:"price"`
+	Created time.Time `json:"created"`
+}
+
+func (a Alert) String() string {
+	direction := "below"
+	if a.Above {
+		direction = "above"
+	}
+	return fmt.Sprintf("%s %s %s", a.Symbol, direction, strconv.FormatFloat(a.Price, 'f', -1, 64))
+}
+
+// Hit says whether price sets a off
+func (a Alert) Hit(price float64) bool {
+	if a.Above {
+		return price >= a.Price
+	}
+	return price <= a.Price
+}
+
+// Parse reads "AAPL above 190", "eth < 3000" and the like. resolve turns
+// what was typed into the full symbol
+func Parse(s string, resolve func(string) string) (Alert, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return Alert{}, fmt.Errorf("%q: want SYMBOL above|below PRICE", s)
+	}
+	a := Alert{Symbol: resolve(strings.ToUpper(fields[0])), Created: time.Now()}
+	switch strings.ToLower(fields[1]) {
+	case "above", ">", ">=", "over":
+		a.Above = true
+	case "below", "<", "<=", "under":
+	default:
+		return Alert{}, fmt.Errorf("%q: %q isn't above or below", s, fields[1])
+	}
+	price, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil || price <= 0 {
+		return Alert{}, fmt.Errorf("%q: %q isn't a price", s, fields[2])
+	}
+	a.Price = price
+	return a, nil
+}
+
+// Book is the alerts waiting to go off. safe to use from any goroutine
+type Book struct {
+	path string
+
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+// Open loads the alerts saved at path, a missing file is none
+func Open(path string) (*Book, error) {
+	b := &Book{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b.alerts); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return b, nil
+}
+
+func (b *Book) Add(a Alert) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.alerts = append(b.alerts, a)
+	return b.save()
+}
+
+// Remove drops a, if it hasn't gone off already
+func (b *Book) Remove(a Alert) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, other := range b.alerts {
+		if other.Symbol == a.Symbol && other.Above == a.Above && other.Price == a.Price && other.Created.Equal(a.Created) {
+			b.alerts = append(b.alerts[:i], b.alerts[i+1:]...)
+			return b.save()
+		}
+	}
+	return nil
+}
+
+// All are the waiting alerts, oldest first
+func (b *Book) All() []Alert {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Alert(nil), b.alerts...)
+}
+
+// Check takes out and returns the alerts of symbol that price sets off
+func (b *Book) Check(symbol string, price float64) ([]Alert, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var hit []Alert
+	kept := b.alerts[:0]
+	for _, a := range b.alerts {
+		if strings.EqualFold(a.Symbol, symbol) && a.Hit(price) {
+			hit = append(hit, a)
+			continue
+		}
+		kept = append(kept, a)
+	}
+	b.alerts = kept
+	if len(hit) == 0 {
+		return nil, nil
+	}
+	return hit, b.save()
+}
+
+func (b *Book) save() error {
+	data, err := json.MarshalIndent(b.alerts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
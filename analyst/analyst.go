@@ -0,0 +1,144 @@
+// Package analyst gathers what analysts think of a symbol: the monthly
+// recommendation trend, the consensus price target and rating changes.
+package analyst
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+// how far back upgrades and downgrades are fetched
+const changesWindow = 180 * 24 * time.Hour
+
+// Ratings is everything known about a symbol's analyst coverage
+type Ratings struct {
+	Trends  []event.RecommendationTrends // oldest month first
+	Target  *event.PriceTarget           // nil when finnhub had none
+	Changes []event.RatingChange         // newest first
+	Updated time.Time
+}
+
+// Stale reports whether r is missing or older than ttl
+func (r *Ratings) Stale(now time.Time, ttl time.Duration) bool {
+	return r == nil || now.Sub(r.Updated) > ttl
+}
+
+// Latest is the newest month, ok is false without any history
+func (r *Ratings) Latest() (event.RecommendationTrends, bool) {
+	if r == nil || len(r.Trends) == 0 {
+		return event.RecommendationTrends{}, false
+	}
+	return r.Trends[len(r.Trends)-1], true
+}
+
+// Change is the month-over-month difference of the newest month, zero
+// without two months of history
+func (r *Ratings) Change() event.RecommendationTrends {
+	if r == nil || len(r.Trends) < 2 {
+		return event.RecommendationTrends{}
+	}
+	return Diff(r.Trends[len(r.Trends)-2], r.Trends[len(r.Trends)-1])
+}
+
+// Diff is cur minus prev, count by count
+func Diff(prev, cur event.RecommendationTrends) event.RecommendationTrends {
+	return event.RecommendationTrends{
+		Pair:       cur.Pair,
+		Period:     cur.Period,
+		StrongBuy:  cur.StrongBuy - prev.StrongBuy,
+		Buy:        cur.Buy - prev.Buy,
+		Hold:       cur.Hold - prev.Hold,
+		Sell:       cur.Sell - prev.Sell,
+		StrongSell: cur.StrongSell - prev.StrongSell,
+	}
+}
+
+// Total is the number of analysts rating in t
+func Total(t event.RecommendationTrends) int64 {
+	return t.StrongBuy + t.Buy + t.Hold + t.Sell + t.StrongSell
+}
+
+// Score is the average rating from 1 (strong buy) to 5 (strong sell),
+// 0 when nobody rated
+func Score(t event.RecommendationTrends) float64 {
+	total := Total(t)
+	if total == 0 {
+		return 0
+	}
+	sum := t.StrongBuy*1 + t.Buy*2 + t.Hold*3 + t.Sell*4 + t.StrongSell*5
+	return float64(sum) / float64(total)
+}
+
+// Fetch gets the trend history, price target and recent rating changes of
+// symbol. the target and changes are paid endpoints on finnhub, what came
+// back is returned along with the errors of what didn't
+func Fetch(ctx context.Context, client *FH.DefaultApiService, symbol string, now time.Time) (*Ratings, error) {
+	pair := event.Pair{Exchange: "finnhub", Symbol: symbol}
+	r := &Ratings{Updated: now}
+	var errs []error
+
+	trends, _, err := client.RecommendationTrends(ctx).Symbol(symbol).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("recommendation trends for %s: %w", symbol, err))
+	}
+	for _, t := range trends {
+		r.Trends = append(r.Trends, event.RecommendationTrends{
+			Pair:       pair,
+			Period:     t.GetPeriod(),
+			Buy:        t.GetBuy(),
+			Hold:       t.GetHold(),
+			Sell:       t.GetSell(),
+			StrongBuy:  t.GetStrongBuy(),
+			StrongSell: t.GetStrongSell(),
+		})
+	}
+	// finnhub sends the newest month first
+	sort.SliceStable(r.Trends, func(i, j int) bool { return r.Trends[i].Period < r.Trends[j].Period })
+
+	target, _, err := client.PriceTarget(ctx).Symbol(symbol).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("price target for %s: %w", symbol, err))
+	} else if target.HasTargetMean() {
+		var updated int64
+		if t, err := time.Parse("2006-01-02 15:04:05", target.GetLastUpdated()); err == nil {
+			updated = t.Unix()
+		}
+		r.Target = &event.PriceTarget{
+			Pair:     pair,
+			High:     float64(target.GetTargetHigh()),
+			Low:      float64(target.GetTargetLow()),
+			Mean:     float64(target.GetTargetMean()),
+			Median:   float64(target.GetTargetMedian()),
+			Analysts: target.GetNumberAnalysts(),
+			Unix:     updated,
+		}
+	}
+
+	changes, _, err := client.UpgradeDowngrade(ctx).Symbol(symbol).
+		From(now.Add(-changesWindow).Format("2006-01-02")).
+		To(now.Format("2006-01-02")).
+		Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("upgrades and downgrades for %s: %w", symbol, err))
+	}
+	for _, c := range changes {
+		r.Changes = append(r.Changes, event.RatingChange{
+			Pair:      pair,
+			Unix:      c.GetGradeTime(),
+			Company:   c.GetCompany(),
+			FromGrade: c.GetFromGrade(),
+			ToGrade:   c.GetToGrade(),
+			Action:    c.GetAction(),
+		})
+	}
+	sort.SliceStable(r.Changes, func(i, j int) bool { return r.Changes[i].Unix > r.Changes[j].Unix })
+
+	return r, errors.Join(errs...)
+}
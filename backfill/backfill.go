@@ -0,0 +1,243 @@
+// Package backfill fetches historical bars for a symbol so charts have
+// context the moment it is selected, instead of starting empty.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/store"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+// Source is anywhere historical bars can come from.
+type Source interface {
+	Name() string
+	Candles(ctx context.Context, pair event.Pair, tf candle.Timeframe, from, to time.Time) ([]event.Candle, error)
+}
+
+// FinnhubSource uses finnhub's stock and crypto candle endpoints. Pair
+// symbols are full finnhub symbols ("BINANCE:BTCUSDT", "AAPL").
+type FinnhubSource struct {
+	Client *FH.DefaultApiService
+}
+
+func (FinnhubSource) Name() string { return "finnhub" }
+
+func (s FinnhubSource) Candles(ctx context.Context, pair event.Pair, tf candle.Timeframe, from, to time.Time) ([]event.Candle, error) {
+	var o, h, l, c, v []float32
+	var t []int64
+	var status string
+
+	if calendar.For(pair) == calendar.Crypto {
+		res, _, err := s.Client.CryptoCandles(ctx).Symbol(pair.Symbol).Resolution(tf.Resolution).
+			From(from.Unix()).To(to.Unix()).Execute()
+		if err != nil {
+			return nil, err
+		}
+		o, h, l, c, v, t, status = res.GetO(), res.GetH(), res.GetL(), res.GetC(), res.GetV(), res.GetT(), res.GetS()
+	} else {
+		res, _, err := s.Client.StockCandles(ctx).Symbol(pair.Symbol).Resolution(tf.Resolution).
+			From(from.Unix()).To(to.Unix()).Execute()
+		if err != nil {
+			return nil, err
+		}
+		o, h, l, c, v, t, status = res.GetO(), res.GetH(), res.GetL(), res.GetC(), res.GetV(), res.GetT(), res.GetS()
+	}
+
+	// "no_data" is finnhub's way of saying an empty range
+	if status == "no_data" {
+		return nil, nil
+	}
+	if status != "ok" {
+		return nil, fmt.Errorf("finnhub candles for %s: status %q", pair.Symbol, status)
+	}
+
+	n := min(len(o), len(h), len(l), len(c), len(v), len(t))
+	bars := make([]event.Candle, 0, n)
+	for i := 0; i < n; i++ {
+		bars = append(bars, event.Candle{
+			Pair:      pair,
+			Timeframe: tf.Name,
+			Unix:      tf.Bucket(t[i]),
+			Open:      float64(o[i]),
+			High:      float64(h[i]),
+			Low:       float64(l[i]),
+			Close:     float64(c[i]),
+			Volume:    float64(v[i]),
+		})
+	}
+	return bars, nil
+}
+
+// StoreSource aggregates bars from trades recorded in the local tick store.
+// Trades are stored under the lowercase symbol the websocket reports.
+// Bars imported into the store fill the buckets the trades don't cover.
+type StoreSource struct {
+	Ticks *store.TickStore
+	// Adjust, when set, split-adjusts the bars, stored trades are raw prices
+	Adjust func(pair event.Pair, bars []event.Candle) []event.Candle
+}
+
+func (StoreSource) Name() string { return "store" }
+
+func (s StoreSource) Candles(ctx context.Context, pair event.Pair, tf candle.Timeframe, from, to time.Time) ([]event.Candle, error) {
+	trades, err := s.Ticks.Trades(pair, from, to)
+	if err != nil {
+		return nil, err
+	}
+	bars := candle.FromTrades(pair, tf, trades)
+	if s.Adjust != nil {
+		bars = s.Adjust(pair, bars)
+	}
+
+	imported, err := s.imported(pair, tf, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(imported) == 0 {
+		return bars, nil
+	}
+	have := make(map[int64]bool, len(bars))
+	for _, bar := range bars {
+		have[bar.Unix] = true
+	}
+	for _, bar := range imported {
+		if !have[bar.Unix] {
+			bars = append(bars, bar)
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Unix < bars[j].Unix })
+	return bars, nil
+}
+
+// imported are the store's imported bars at tf, rolled up from the
+// longest shorter timeframe there are bars of when there are none at tf.
+// imported bars come adjusted already, like finnhub's
+func (s StoreSource) imported(pair event.Pair, tf candle.Timeframe, from, to time.Time) ([]event.Candle, error) {
+	for i := len(candle.Timeframes) - 1; i >= 0; i-- {
+		src := candle.Timeframes[i]
+		if src.Duration > tf.Duration || tf.Duration%src.Duration != 0 {
+			continue
+		}
+		bars, err := s.Ticks.Bars(pair, src.Name, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(bars) > 0 {
+			return candle.Resample(tf, bars), nil
+		}
+	}
+	return nil, nil
+}
+
+// Service asks its sources in order. Bars from earlier sources win, later
+// ones only fill the buckets that are still missing.
+type Service struct {
+	sources []Source
+	// Bars is how many bars a backfill aims for
+	Bars int
+}
+
+func New(sources ...Source) *Service {
+	return &Service{
+		sources: sources,
+		Bars:    300,
+	}
+}
+
+// Range is the window Backfill asks for when aiming for s.Bars bars ending
+// at to. Venues that close get a few extra days so a weekend or holiday
+// doesn't leave the chart empty.
+func (s *Service) Range(pair event.Pair, tf candle.Timeframe, to time.Time) (time.Time, time.Time) {
+	from := to.Add(-tf.Duration * time.Duration(s.Bars))
+	if !calendar.For(pair).AlwaysOpen {
+		from = from.AddDate(0, 0, -4)
+		if tf.Duration >= 24*time.Hour {
+			// roughly 5 trading days in 7
+			from = to.Add(-tf.Duration * time.Duration(s.Bars*7/5))
+		}
+	}
+	return from, to
+}
+
+// Fetch returns up to s.Bars bars of pair ending at to, oldest first and
+// de-duplicated across sources. It only fails if every source failed.
+func (s *Service) Fetch(ctx context.Context, pair event.Pair, tf candle.Timeframe, to time.Time) ([]event.Candle, error) {
+	from, to := s.Range(pair, tf, to)
+	bars, err := Candles(ctx, s.sources, pair, tf, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) > s.Bars {
+		bars = bars[len(bars)-s.Bars:]
+	}
+	return bars, nil
+}
+
+// Candles asks sources in order for the bars of pair in [from, to), oldest
+// first. Bars from earlier sources win, later ones only fill the buckets
+// that are still missing. It only fails if every source failed.
+func Candles(ctx context.Context, sources []Source, pair event.Pair, tf candle.Timeframe, from, to time.Time) ([]event.Candle, error) {
+	byBucket := make(map[int64]event.Candle)
+	var errs []error
+	for _, src := range sources {
+		bars, err := src.Candles(ctx, pair, tf, from, to)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+			continue
+		}
+		for _, bar := range bars {
+			if _, ok := byBucket[bar.Unix]; !ok {
+				byBucket[bar.Unix] = bar
+			}
+		}
+	}
+	if len(errs) == len(sources) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	bars := make([]event.Candle, 0, len(byBucket))
+	for _, bar := range byBucket {
+		bars = append(bars, bar)
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Unix < bars[j].Unix })
+	return bars, nil
+}
+
+// Merger is where backfilled bars end up, usually a *candle.Aggregator.
+type Merger interface {
+	Merge(pair event.Pair, tf candle.Timeframe, history []event.Candle) []candle.Gap
+}
+
+// Backfill fetches history for pair and merges it into m. Gaps left at the
+// seam between history and live bars get one more pass over the sources for
+// just that window; whatever is still missing after that is returned.
+func (s *Service) Backfill(ctx context.Context, m Merger, pair event.Pair, tf candle.Timeframe, now time.Time) ([]candle.Gap, error) {
+	bars, err := s.Fetch(ctx, pair, tf, now)
+	if err != nil {
+		return nil, err
+	}
+	gaps := m.Merge(pair, tf, bars)
+
+	for _, gap := range gaps {
+		for _, src := range s.sources {
+			fill, err := src.Candles(ctx, pair, tf, gap.From, gap.To)
+			if err != nil || len(fill) == 0 {
+				continue
+			}
+			m.Merge(pair, tf, fill)
+		}
+	}
+	if len(gaps) == 0 {
+		return nil, nil
+	}
+	return m.Merge(pair, tf, nil), nil
+}
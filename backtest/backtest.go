@@ -0,0 +1,182 @@
+// Package backtest runs a strategy over historical bars or recorded trades
+// against an in-memory paper account. Runs are deterministic: the engine
+// clock only moves with the data, never with the wall clock.
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/paper"
+	"github.com/Scrimzay/stockspider/strategy"
+)
+
+type Config struct {
+	// Paper sets cash, slippage and fees. Path and Now are ignored, a
+	// backtest never touches the live account or the wall clock.
+	Paper     paper.Config
+	Timeframe candle.Timeframe
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Paper:     paper.DefaultConfig(),
+		Timeframe: candle.H1,
+	}
+}
+
+// runner holds the simulated clock and the account of one run
+type runner struct {
+	cfg    Config
+	clock  time.Time
+	engine *paper.Engine
+	report *Report
+}
+
+func newRunner(cfg Config, s strategy.Strategy, respectVolume bool) (*runner, error) {
+	r := &runner{cfg: cfg}
+	pcfg := cfg.Paper
+	pcfg.Path = ""
+	pcfg.Now = func() time.Time { return r.clock }
+	pcfg.RespectVolume = pcfg.RespectVolume && respectVolume
+
+	engine, err := paper.New(pcfg)
+	if err != nil {
+		return nil, err
+	}
+	r.engine = engine
+	r.report = &Report{
+		Strategy:     s.Name(),
+		Timeframe:    cfg.Timeframe.Name,
+		StartingCash: pcfg.StartingCash,
+	}
+	return r, nil
+}
+
+func (r *runner) mark(t time.Time) {
+	r.report.addPoint(t, r.engine.Snapshot().Equity)
+}
+
+func (r *runner) finish(symbols map[string]bool) *Report {
+	for symbol := range symbols {
+		r.report.Symbols = append(r.report.Symbols, symbol)
+	}
+	sort.Strings(r.report.Symbols)
+	r.report.finish(r.engine.Fills())
+	return r.report
+}
+
+// synthetic turns a bar into the trades it most likely printed: open, the
+// nearer extreme, the other extreme, close. The extremes are ordered the way
+// the bar moved so a stop isn't hit by a wick that came after the target.
+func synthetic(bar event.Candle, tf candle.Timeframe) []event.StockTrade {
+	start := time.Unix(bar.Unix, 0)
+	step := tf.Duration / 3
+	prices := []float64{bar.Open, bar.Low, bar.High, bar.Close}
+	if bar.Close < bar.Open {
+		prices = []float64{bar.Open, bar.High, bar.Low, bar.Close}
+	}
+	times := []time.Time{start, start.Add(step), start.Add(2 * step), start.Add(tf.Duration - time.Millisecond)}
+
+	trades := make([]event.StockTrade, len(prices))
+	for i := range prices {
+		trades[i] = event.StockTrade{
+			Pair:  bar.Pair,
+			Price: prices[i],
+			Qty:   bar.Volume / float64(len(prices)),
+			IsBuy: bar.Close >= bar.Open,
+			Unix:  times[i].UnixMilli(),
+		}
+	}
+	return trades
+}
+
+// RunBars runs s over bars. Orders fill against trades synthesized from
+// each bar, so an order placed in OnBar fills during the next bar at the
+// earliest. The strategy only gets OnBar calls in this mode, the synthetic
+// trades aren't real prints. Every bar has to be of cfg.Timeframe, the
+// synthetic trades are spread over it, bars of another timeframe are
+// refused.
+func RunBars(cfg Config, s strategy.Strategy, bars []event.Candle) (*Report, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no bars to backtest")
+	}
+	for _, bar := range bars {
+		if bar.Timeframe != "" && bar.Timeframe != cfg.Timeframe.Name {
+			return nil, fmt.Errorf("%s bar at %s is %s, the backtest runs on %s",
+				bar.Pair.Symbol, time.Unix(bar.Unix, 0).UTC().Format(time.RFC3339), bar.Timeframe, cfg.Timeframe.Name)
+		}
+	}
+	r, err := newRunner(cfg, s, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]event.Candle, len(bars))
+	copy(sorted, bars)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Unix != sorted[j].Unix {
+			return sorted[i].Unix < sorted[j].Unix
+		}
+		return strings.ToLower(sorted[i].Pair.Symbol) < strings.ToLower(sorted[j].Pair.Symbol)
+	})
+
+	symbols := make(map[string]bool)
+	for _, bar := range sorted {
+		bar.Pair.Symbol = strings.ToLower(bar.Pair.Symbol)
+		symbols[bar.Pair.Symbol] = true
+
+		for _, trade := range synthetic(bar, cfg.Timeframe) {
+			r.clock = time.UnixMilli(trade.Unix)
+			if _, err := r.engine.OnTrade(trade); err != nil {
+				return nil, err
+			}
+		}
+
+		end := time.Unix(bar.Unix, 0).Add(cfg.Timeframe.Duration)
+		r.clock = end
+		s.OnBar(r.engine, bar)
+		r.mark(end)
+	}
+	return r.finish(symbols), nil
+}
+
+// RunTrades runs s over recorded trades, the same way strategy.Live runs it
+// against the live feed: the engine matches each trade first, then the
+// strategy sees any bar it closed, then the trade itself.
+func RunTrades(cfg Config, s strategy.Strategy, trades []event.StockTrade) (*Report, error) {
+	if len(trades) == 0 {
+		return nil, fmt.Errorf("no trades to backtest")
+	}
+	r, err := newRunner(cfg, s, true)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]event.StockTrade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Unix < sorted[j].Unix })
+
+	symbols := make(map[string]bool)
+	bars := strategy.NewBarCloser(cfg.Timeframe)
+	for _, trade := range sorted {
+		trade.Pair.Symbol = strings.ToLower(trade.Pair.Symbol)
+		symbols[trade.Pair.Symbol] = true
+		r.clock = time.UnixMilli(trade.Unix)
+
+		if _, err := r.engine.OnTrade(trade); err != nil {
+			return nil, err
+		}
+		if bar, ok := bars.Add(trade); ok {
+			s.OnBar(r.engine, bar)
+			r.mark(time.Unix(bar.Unix, 0).Add(cfg.Timeframe.Duration))
+		}
+		s.OnTrade(r.engine, trade)
+	}
+	r.mark(r.clock)
+	return r.finish(symbols), nil
+}
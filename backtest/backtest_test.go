@@ -0,0 +1,107 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/strategy"
+)
+
+type hold struct{}
+
+func (hold) Name() string                              { return "hold" }
+func (hold) OnTrade(strategy.Broker, event.StockTrade) {}
+func (hold) OnBar(b strategy.Broker, bar event.Candle) {}
+
+func bars(symbol, tf string, closes ...float64) []event.Candle {
+	start := time.Date(2024, 3, 11, 14, 0, 0, 0, time.UTC).Unix()
+	out := make([]event.Candle, len(closes))
+	for i, c := range closes {
+		out[i] = event.Candle{
+			Pair:      event.Pair{Exchange: "finnhub", Symbol: symbol},
+			Timeframe: tf,
+			Unix:      start + int64(i)*3600,
+			Open:      c, High: c, Low: c, Close: c,
+			Volume: 100,
+		}
+	}
+	return out
+}
+
+func TestRunBarsOnePointPerBar(t *testing.T) {
+	cfg := DefaultConfig()
+	all := append(bars("AAA", "1h", 10, 11, 12), bars("BBB", "1h", 20, 19, 18)...)
+	report, err := RunBars(cfg, hold{}, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Equity) != 3 {
+		t.Fatalf("%d equity points for 3 bars of 2 symbols, want 3", len(report.Equity))
+	}
+	for i := 1; i < len(report.Equity); i++ {
+		if !report.Equity[i].Time.After(report.Equity[i-1].Time) {
+			t.Fatalf("equity point %d at %v isn't after %v", i, report.Equity[i].Time, report.Equity[i-1].Time)
+		}
+	}
+}
+
+func TestRunBarsTimeframeMismatch(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Timeframe = candle.M5
+	if _, err := RunBars(cfg, hold{}, bars("AAA", "1h", 10, 11)); err == nil {
+		t.Fatal("1h bars ran on a 5m backtest")
+	}
+	// bars that don't say are taken as cfg's
+	if _, err := RunBars(cfg, hold{}, bars("AAA", "", 10, 11)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeEquity(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+	r := &Report{}
+	// the 10:00 dip only exists half way through marking both symbols
+	r.addPoint(at(9), 100)
+	r.addPoint(at(10), 50)
+	r.addPoint(at(10), 100)
+	r.addPoint(at(11), 90)
+	r.mergeEquity()
+
+	if len(r.Equity) != 3 || r.Equity[1].Equity != 100 {
+		t.Fatalf("merged %+v, want 9:00, 10:00 at 100, 11:00", r.Equity)
+	}
+	var maxDD float64
+	for _, p := range r.Equity {
+		maxDD = math.Max(maxDD, p.Drawdown)
+	}
+	if math.Abs(maxDD-0.1) > 1e-9 {
+		t.Fatalf("max drawdown %v, want 0.1", maxDD)
+	}
+}
+
+func TestSharpe(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name  string
+		curve []Point
+	}{
+		{"empty", nil},
+		{"one return", []Point{{Time: at(1), Equity: 100}, {Time: at(2), Equity: 110}}},
+		// only one usable return once the zero equity is skipped
+		{"one usable return", []Point{{Time: at(1), Equity: 0}, {Time: at(2), Equity: 100}, {Time: at(3), Equity: 110}}},
+		{"flat", []Point{{Time: at(1), Equity: 100}, {Time: at(2), Equity: 100}, {Time: at(3), Equity: 100}}},
+	}
+	for _, tt := range tests {
+		if got := sharpe(tt.curve); got != 0 {
+			t.Errorf("%s: sharpe %v, want 0", tt.name, got)
+		}
+	}
+
+	curve := []Point{{Time: at(1), Equity: 100}, {Time: at(2), Equity: 101}, {Time: at(3), Equity: 103}}
+	if got := sharpe(curve); got <= 0 || math.IsNaN(got) || math.IsInf(got, 0) {
+		t.Fatalf("sharpe of a rising curve %v, want positive", got)
+	}
+}
@@ -0,0 +1,254 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/paper"
+)
+
+type Point struct {
+	Time     time.Time
+	Equity   float64
+	Drawdown float64 // fraction below the running peak
+}
+
+// Trade is one fill, sells carry the P&L of the quantity they closed
+type Trade struct {
+	Time   time.Time
+	Symbol string
+	Side   paper.Side
+	Qty    float64
+	Price  float64
+	Fee    float64
+	PnL    float64 // net of fees on both legs, zero for buys
+}
+
+type Report struct {
+	Strategy     string
+	Symbols      []string
+	Timeframe    string
+	From         time.Time
+	To           time.Time
+	StartingCash float64
+	FinalEquity  float64
+	Return       float64 // fraction
+	MaxDrawdown  float64 // fraction
+	Sharpe       float64 // annualized, zero risk-free rate
+	WinRate      float64 // winning sell orders over all sell orders
+	Wins         int
+	Losses       int
+	Fees         float64
+
+	Equity []Point
+	Trades []Trade
+}
+
+func (r *Report) addPoint(t time.Time, equity float64) {
+	r.Equity = append(r.Equity, Point{Time: t, Equity: equity})
+}
+
+// mergeEquity leaves one point per timestamp, the last one marked. with
+// several symbols every bar close marks the same time once per symbol and
+// only the last of them has all of them in it. drawdowns are taken after
+func (r *Report) mergeEquity() {
+	sort.SliceStable(r.Equity, func(i, j int) bool { return r.Equity[i].Time.Before(r.Equity[j].Time) })
+	merged := r.Equity[:0]
+	for _, p := range r.Equity {
+		if n := len(merged); n > 0 && merged[n-1].Time.Equal(p.Time) {
+			merged[n-1] = p
+			continue
+		}
+		merged = append(merged, p)
+	}
+	r.Equity = merged
+
+	peak := 0.0
+	for i, p := range r.Equity {
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			r.Equity[i].Drawdown = (peak - p.Equity) / peak
+		}
+	}
+}
+
+func (r *Report) finish(fills []paper.Fill) {
+	r.mergeEquity()
+	if len(r.Equity) > 0 {
+		r.From = r.Equity[0].Time
+		r.To = r.Equity[len(r.Equity)-1].Time
+		r.FinalEquity = r.Equity[len(r.Equity)-1].Equity
+	}
+	if r.StartingCash > 0 {
+		r.Return = r.FinalEquity/r.StartingCash - 1
+	}
+	for _, p := range r.Equity {
+		r.MaxDrawdown = math.Max(r.MaxDrawdown, p.Drawdown)
+	}
+	r.Sharpe = sharpe(r.Equity)
+
+	// replay the fills with average cost per symbol to price each sell,
+	// buy fees are spread over the quantity they bought
+	type holding struct{ qty, cost float64 }
+	held := make(map[string]*holding)
+	// a sell order can fill in pieces, it wins or loses as a whole
+	var sellOrders []string
+	orderPnL := make(map[string]float64)
+	for _, f := range fills {
+		h, ok := held[f.Symbol]
+		if !ok {
+			h = &holding{}
+			held[f.Symbol] = h
+		}
+		t := Trade{
+			Time:   time.UnixMilli(f.Unix).UTC(),
+			Symbol: f.Symbol,
+			Side:   f.Side,
+			Qty:    f.Qty,
+			Price:  f.Price,
+			Fee:    f.Fee,
+		}
+		r.Fees += f.Fee
+		switch f.Side {
+		case paper.Buy:
+			h.cost += f.Price*f.Qty + f.Fee
+			h.qty += f.Qty
+		case paper.Sell:
+			avg := 0.0
+			if h.qty > 0 {
+				avg = h.cost / h.qty
+			}
+			t.PnL = (f.Price-avg)*f.Qty - f.Fee
+			h.cost -= avg * f.Qty
+			h.qty -= f.Qty
+			if _, ok := orderPnL[f.OrderID]; !ok {
+				sellOrders = append(sellOrders, f.OrderID)
+			}
+			orderPnL[f.OrderID] += t.PnL
+		}
+		r.Trades = append(r.Trades, t)
+	}
+	for _, id := range sellOrders {
+		if orderPnL[id] > 0 {
+			r.Wins++
+		} else {
+			r.Losses++
+		}
+	}
+	if r.Wins+r.Losses > 0 {
+		r.WinRate = float64(r.Wins) / float64(r.Wins+r.Losses)
+	}
+}
+
+// sharpe annualizes by the average spacing of the curve, so it works the
+// same for minute and daily bars
+func sharpe(curve []Point) float64 {
+	if len(curve) < 3 {
+		return 0
+	}
+	var returns []float64
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1].Equity > 0 {
+			returns = append(returns, curve[i].Equity/curve[i-1].Equity-1)
+		}
+	}
+	// the sample deviation needs two returns
+	if len(returns) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+
+	spacing := curve[len(curve)-1].Time.Sub(curve[0].Time) / time.Duration(len(curve)-1)
+	if spacing <= 0 {
+		return 0
+	}
+	periodsPerYear := float64(365*24*time.Hour) / float64(spacing)
+	return mean / std * math.Sqrt(periodsPerYear)
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// WriteCSV writes summary.csv, equity.csv and trades.csv into dir.
+func (r *Report) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	summary := [][]string{
+		{"strategy", r.Strategy},
+		{"symbols", strings.Join(r.Symbols, " ")},
+		{"timeframe", r.Timeframe},
+		{"from", r.From.UTC().Format(time.RFC3339)},
+		{"to", r.To.UTC().Format(time.RFC3339)},
+		{"starting_cash", formatFloat(r.StartingCash)},
+		{"final_equity", formatFloat(r.FinalEquity)},
+		{"return", formatFloat(r.Return)},
+		{"max_drawdown", formatFloat(r.MaxDrawdown)},
+		{"sharpe", formatFloat(r.Sharpe)},
+		{"win_rate", formatFloat(r.WinRate)},
+		{"wins", strconv.Itoa(r.Wins)},
+		{"losses", strconv.Itoa(r.Losses)},
+		{"fees", formatFloat(r.Fees)},
+	}
+	if err := writeCSV(filepath.Join(dir, "summary.csv"), append([][]string{{"metric", "value"}}, summary...)); err != nil {
+		return err
+	}
+
+	equity := [][]string{{"time", "equity", "drawdown"}}
+	for _, p := range r.Equity {
+		equity = append(equity, []string{p.Time.UTC().Format(time.RFC3339), formatFloat(p.Equity), formatFloat(p.Drawdown)})
+	}
+	if err := writeCSV(filepath.Join(dir, "equity.csv"), equity); err != nil {
+		return err
+	}
+
+	trades := [][]string{{"time", "symbol", "side", "qty", "price", "fee", "pnl"}}
+	for _, t := range r.Trades {
+		trades = append(trades, []string{
+			t.Time.Format(time.RFC3339), t.Symbol, string(t.Side),
+			formatFloat(t.Qty), formatFloat(t.Price), formatFloat(t.Fee), formatFloat(t.PnL),
+		})
+	}
+	return writeCSV(filepath.Join(dir, "trades.csv"), trades)
+}
+
+func writeCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
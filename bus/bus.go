@@ -0,0 +1,192 @@
+// Package bus is the topic based pub/sub everything in the app talks
+// through. A broker actor on the hollywood engine takes published messages
+// and forwards them to every subscriber whose pattern matches the topic,
+// so publishers and subscribers only need the engine, never each other.
+//
+//	bus.Start(e)
+//	bus.Func(e, "gui", "trades.*", func(t event.StockTrade) { ... })
+//	bus.Publish(e, bus.Trades(trade.Pair), trade)
+package bus
+
+import (
+	"time"
+
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// the broker is spawned under a fixed kind and id so PID can find it
+// from nothing but the engine
+const (
+	kind = "bus"
+	id   = "broker"
+)
+
+// how many published messages may wait for the broker before Publish
+// starts dropping them
+const mailboxSize = 1 << 16
+
+// how many messages a Func subscriber may have waiting
+const funcMailboxSize = 1 << 14
+
+// Policy restarts a crashed broker with the subscriptions it had, a
+// broker that comes back empty would cut every subscriber off silently
+var Policy = supervise.Policy{
+	MaxRestarts: 100,
+	Delay:       10 * time.Millisecond,
+}
+
+var snapshots = supervise.NewSnapshots[[]*Subscribe]()
+
+// Mailbox is the depth of the broker's mailbox
+var Mailbox = metrics.NewMailbox("bus", mailboxSize)
+
+// Subscribe asks the broker to forward every message published on a topic
+// matching Pattern to PID. Filter, when set, sees the message first and
+// can turn it away. Mailbox, when set, bounds what PID may have waiting,
+// the broker Reserves before forwarding and the subscriber calls Handled
+type Subscribe struct {
+	Pattern string
+	PID     *actor.PID
+	Filter  func(msg any) bool
+	Mailbox *metrics.Mailbox
+}
+
+// Unsubscribe drops the subscriptions of PID on Pattern, "" drops all of them
+type Unsubscribe struct {
+	Pattern string
+	PID     *actor.PID
+}
+
+type published struct {
+	topic Topic
+	msg   any
+}
+
+// Start spawns the broker on e, once, before anything publishes
+func Start(e *actor.Engine) *actor.PID {
+	opts := append(Policy.Opts(), actor.WithID(id))
+	return e.Spawn(newBroker, kind, opts...)
+}
+
+// PID is the broker of e
+func PID(e *actor.Engine) *actor.PID {
+	return actor.NewPID(e.Address(), kind+"/"+id)
+}
+
+// Publish hands msg to the broker for everyone subscribed to topic. false
+// means the broker is too far behind and msg was dropped
+func Publish(e *actor.Engine, topic Topic, msg any) bool {
+	if !Mailbox.Reserve() {
+		return false
+	}
+	e.Send(PID(e), published{topic: topic, msg: msg})
+	return true
+}
+
+// Sub subscribes pid to pattern with no filter or bound
+func Sub(e *actor.Engine, pattern string, pid *actor.PID) {
+	e.Send(PID(e), Subscribe{Pattern: pattern, PID: pid})
+}
+
+// Unsub drops every subscription of pid
+func Unsub(e *actor.Engine, pid *actor.PID) {
+	e.Send(PID(e), Unsubscribe{PID: pid})
+}
+
+// Filter makes a typed Subscribe.Filter, messages that aren't a T are
+// turned away
+func Filter[T any](keep func(T) bool) func(any) bool {
+	return func(msg any) bool {
+		v, ok := msg.(T)
+		return ok && (keep == nil || keep(v))
+	}
+}
+
+// Func spawns a subscriber that calls fn with every T published on a
+// topic matching pattern, for code that isn't an actor of its own like
+// the GUI. fn runs on the subscriber's goroutine, one message at a time.
+// Poison the returned PID to unsubscribe
+func Func[T any](e *actor.Engine, name, pattern string, fn func(T)) *actor.PID {
+	mailbox := metrics.NewMailbox("bus:"+name, funcMailboxSize)
+	return e.SpawnFunc(func(c *actor.Context) {
+		switch msg := c.Message().(type) {
+		case actor.Started:
+			c.Send(PID(c.Engine()), Subscribe{
+				Pattern: pattern,
+				PID:     c.PID(),
+				Filter:  Filter[T](nil),
+				Mailbox: mailbox,
+			})
+		case actor.Stopped:
+			c.Send(PID(c.Engine()), Unsubscribe{PID: c.PID()})
+		case T:
+			mailbox.Handled()
+			fn(msg)
+		}
+	}, "bus-"+name)
+}
+
+type broker struct {
+	subs []*Subscribe
+	// subscribers per topic seen so far, rebuilt whenever subs change
+	routes map[Topic][]*Subscribe
+}
+
+func newBroker() actor.Receiver {
+	return &broker{routes: make(map[Topic][]*Subscribe)}
+}
+
+func (b *broker) Receive(c *actor.Context) {
+	switch msg := c.Message().(type) {
+	case actor.Started:
+		if subs, ok := snapshots.Load(c.PID().GetID()); ok {
+			b.subs = subs
+		}
+	case actor.Stopped:
+		snapshots.Save(c.PID().GetID(), b.subs)
+	case published:
+		Mailbox.Handled()
+		for _, sub := range b.route(msg.topic) {
+			if sub.Filter != nil && !sub.Filter(msg.msg) {
+				continue
+			}
+			if sub.Mailbox != nil && !sub.Mailbox.Reserve() {
+				continue
+			}
+			c.Send(sub.PID, msg.msg)
+		}
+	case Subscribe:
+		b.subs = append(b.subs, &msg)
+		clear(b.routes)
+	case Unsubscribe:
+		kept := b.subs[:0]
+		for _, sub := range b.subs {
+			if sub.PID.Equals(msg.PID) && (msg.Pattern == "" || msg.Pattern == sub.Pattern) {
+				continue
+			}
+			kept = append(kept, sub)
+		}
+		clear(b.subs[len(kept):])
+		b.subs = kept
+		clear(b.routes)
+	}
+}
+
+// route is who's subscribed to topic, cached since the same few hundred
+// topics come by over and over
+func (b *broker) route(topic Topic) []*Subscribe {
+	if subs, ok := b.routes[topic]; ok {
+		return subs
+	}
+	var subs []*Subscribe
+	for _, sub := range b.subs {
+		if Match(sub.Pattern, topic) {
+			subs = append(subs, sub)
+		}
+	}
+	b.routes[topic] = subs
+	return subs
+}
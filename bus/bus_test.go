@@ -0,0 +1,223 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/metrics"
+
+	"github.com/anthdm/hollywood/actor"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newBus is an engine with a broker on it. the broker's snapshot is keyed
+// by its id, the same on every engine, so it's dropped again afterwards
+func newBus(t *testing.T) *actor.Engine {
+	t.Helper()
+	e, err := actor.NewEngine(actor.NewEngineConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := Start(e)
+	t.Cleanup(func() {
+		e.Poison(pid).Wait()
+		snapshots.Delete(pid.GetID())
+	})
+	return e
+}
+
+// collect spawns a subscriber that hands every message it gets to the
+// returned channel and never calls Handled, mailbox bounds what it can have
+func collect(t *testing.T, e *actor.Engine, name, pattern string, filter func(any) bool, mailbox *metrics.Mailbox) <-chan any {
+	t.Helper()
+	got := make(chan any, 64)
+	pid := e.SpawnFunc(func(c *actor.Context) {
+		switch msg := c.Message().(type) {
+		case actor.Initialized, actor.Started, actor.Stopped:
+		default:
+			got <- msg
+		}
+	}, "test-"+name)
+	t.Cleanup(func() { e.Poison(pid).Wait() })
+	e.Send(PID(e), Subscribe{Pattern: pattern, PID: pid, Filter: filter, Mailbox: mailbox})
+	return got
+}
+
+// receive reads want messages off ch, then makes sure no more come
+func receive(t *testing.T, ch <-chan any, want ...any) {
+	t.Helper()
+	for i, w := range want {
+		select {
+		case got := <-ch:
+			if got != w {
+				t.Fatalf("message %d is %#v, want %#v", i, got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d messages, want %d", i, len(want))
+		}
+	}
+	select {
+	case got := <-ch:
+		t.Fatalf("got %#v past the %d wanted", got, len(want))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRouting(t *testing.T) {
+	e := newBus(t)
+	aapl := event.Pair{Exchange: "finnhub", Symbol: "AAPL"}
+	brk := event.Pair{Exchange: "finnhub", Symbol: "BRK.B"}
+
+	all := collect(t, e, "all", "trades.*", nil, nil)
+	one := collect(t, e, "one", "trades.aapl", nil, nil)
+	stats := collect(t, e, "stats", "stats.*", nil, nil)
+	everything := collect(t, e, "everything", "*", nil, nil)
+
+	a := event.StockTrade{Pair: aapl, Price: 1}
+	b := event.StockTrade{Pair: brk, Price: 2}
+	s := event.Stat{Pair: aapl, MarkPrice: 3}
+	Publish(e, Trades(aapl), a)
+	Publish(e, Trades(brk), b)
+	Publish(e, Stats(aapl), s)
+
+	receive(t, all, a, b)
+	receive(t, one, a)
+	receive(t, stats, s)
+	receive(t, everything, a, b, s)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	e := newBus(t)
+	got := make(chan any, 8)
+	pid := e.SpawnFunc(func(c *actor.Context) {
+		if msg, ok := c.Message().(event.StockTrade); ok {
+			got <- msg
+		}
+	}, "test-unsub")
+	t.Cleanup(func() { e.Poison(pid).Wait() })
+	Sub(e, "trades.*", pid)
+	Sub(e, "selected", pid)
+
+	aapl := event.Pair{Symbol: "aapl"}
+	first := event.StockTrade{Pair: aapl, Price: 1}
+	Publish(e, Trades(aapl), first)
+	receive(t, got, first)
+
+	// one pattern, then the rest
+	e.Send(PID(e), Unsubscribe{Pattern: "trades.*", PID: pid})
+	Publish(e, Trades(aapl), event.StockTrade{Pair: aapl, Price: 2})
+	receive(t, got)
+	Unsub(e, pid)
+	Publish(e, Selected, event.StockTrade{Pair: aapl, Price: 3})
+	receive(t, got)
+}
+
+func TestFilter(t *testing.T) {
+	e := newBus(t)
+	aapl := event.Pair{Symbol: "aapl"}
+
+	trades := collect(t, e, "typed", "*", Filter[event.StockTrade](nil), nil)
+	big := collect(t, e, "big", "trades.*", Filter(func(v event.StockTrade) bool { return v.Qty >= 100 }), nil)
+
+	small := event.StockTrade{Pair: aapl, Price: 1, Qty: 1}
+	large := event.StockTrade{Pair: aapl, Price: 1, Qty: 500}
+	Publish(e, Trades(aapl), small)
+	Publish(e, Stats(aapl), event.Stat{Pair: aapl})
+	Publish(e, Trades(aapl), large)
+
+	receive(t, trades, small, large)
+	receive(t, big, large)
+}
+
+func TestSubscriberMailboxOverflow(t *testing.T) {
+	e := newBus(t)
+	aapl := event.Pair{Symbol: "aapl"}
+
+	const name = "test-overflow"
+	mailbox := metrics.NewMailbox(name, 2)
+	before := dropped(t, name)
+	got := collect(t, e, "overflow", "trades.*", nil, mailbox)
+	var sent []any
+	for i := 0; i < 5; i++ {
+		trade := event.StockTrade{Pair: aapl, Price: float64(i)}
+		sent = append(sent, trade)
+		Publish(e, Trades(aapl), trade)
+	}
+
+	// the subscriber never takes anything out, the first two fit
+	receive(t, got, sent[:2]...)
+	if n := dropped(t, name); n != before+3 {
+		t.Fatalf("dropped went %v -> %v, want +3", before, n)
+	}
+	if mailbox.Depth() != 2 {
+		t.Fatalf("depth %d, want 2", mailbox.Depth())
+	}
+
+	// handling one makes room for one more
+	mailbox.Handled()
+	last := event.StockTrade{Pair: aapl, Price: 9}
+	Publish(e, Trades(aapl), last)
+	receive(t, got, last)
+}
+
+func TestRestartKeepsSubscriptions(t *testing.T) {
+	e := newBus(t)
+	aapl := event.Pair{Symbol: "aapl"}
+
+	// the broker calls filters itself, so one that panics crashes it
+	boom := event.StockTrade{Pair: aapl, Price: -1}
+	filter := func(msg any) bool {
+		if msg == any(boom) {
+			panic("boom")
+		}
+		return true
+	}
+	got := collect(t, e, "restart", "trades.*", filter, nil)
+
+	restarted := make(chan struct{}, 1)
+	events := e.SpawnFunc(func(c *actor.Context) {
+		if ev, ok := c.Message().(actor.ActorRestartedEvent); ok && ev.PID.Equals(PID(e)) {
+			restarted <- struct{}{}
+		}
+	}, "test-events")
+	t.Cleanup(func() { e.Poison(events).Wait() })
+	e.Subscribe(events)
+
+	before := event.StockTrade{Pair: aapl, Price: 1}
+	Publish(e, Trades(aapl), before)
+	receive(t, got, before)
+
+	Publish(e, Trades(aapl), boom)
+	select {
+	case <-restarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("broker didn't restart")
+	}
+
+	after := event.StockTrade{Pair: aapl, Price: 2}
+	Publish(e, Trades(aapl), after)
+	receive(t, got, after)
+}
+
+// dropped reads stockspider_actor_mailbox_dropped_total for one actor
+func dropped(t *testing.T, actorName string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != "stockspider_actor_mailbox_dropped_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "actor" && l.GetValue() == actorName {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
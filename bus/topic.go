@@ -0,0 +1,89 @@
+package bus
+
+import (
+	"strings"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// Topic names what a message is about, like "trades.binance:btcusdt".
+// Symbols are lowercased so every topic of a pair looks the same
+type Topic string
+
+// Selected carries event.Selected, the symbol the user is looking at
+const Selected Topic = "selected"
+
+// Watched carries event.Watched, the other symbols the user has on screen
+const Watched Topic = "watched"
+
+// Trades carries event.StockTrade for pair
+func Trades(pair event.Pair) Topic {
+	return topic("trades", pair.Symbol)
+}
+
+// Stats carries event.Stat for pair
+func Stats(pair event.Pair) Topic {
+	return topic("stats", pair.Symbol)
+}
+
+// Quotes carries event.Quote for pair
+func Quotes(pair event.Pair) Topic {
+	return topic("quotes", pair.Symbol)
+}
+
+// Candles carries finished event.Candle bars of pair at timeframe tf, "1m"
+func Candles(tf string, pair event.Pair) Topic {
+	return topic("candles."+tf, pair.Symbol)
+}
+
+// News carries de-duplicated event.News for pair, market news is filed
+// under the empty pair
+func News(pair event.Pair) Topic {
+	return topic("news", pair.Symbol)
+}
+
+// Books carries event.Book snapshots of pair
+func Books(pair event.Pair) Topic {
+	return topic("books", pair.Symbol)
+}
+
+// WSNews carries event.News straight off the websocket, the news consumer
+// de-dups it onto News
+func WSNews(pair event.Pair) Topic {
+	return topic("wsnews", pair.Symbol)
+}
+
+func topic(prefix, symbol string) Topic {
+	return Topic(prefix + "." + strings.ToLower(symbol))
+}
+
+// Match reports whether topic fits pattern, where * stands for any run of
+// characters, dots included. "trades.*" is every trade, "candles.1m.*"
+// every one minute bar and "*" everything. symbols can have dots of their
+// own ("brk.b") so * isn't limited to one segment
+func Match(pattern string, topic Topic) bool {
+	t := string(topic)
+	// the usual backtracking glob, only the last * ever needs revisiting
+	p, i := 0, 0
+	star, mark := -1, 0
+	for i < len(t) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, i
+			p++
+		case p < len(pattern) && pattern[p] == t[i]:
+			p++
+			i++
+		case star >= 0:
+			p = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
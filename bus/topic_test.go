@@ -0,0 +1,60 @@
+package bus
+
+import (
+	"testing"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   Topic
+		want    bool
+	}{
+		{"selected", Selected, true},
+		{"selected", Watched, false},
+		{"*", "trades.aapl", true},
+		{"*", "", true},
+		{"", "", true},
+		{"", "trades.aapl", false},
+
+		// exact against prefix
+		{"trades.aapl", "trades.aapl", true},
+		{"trades.aapl", "trades.aapl2", false},
+		{"trades.aap", "trades.aapl", false},
+		{"trades", "trades.aapl", false},
+		{"trades.*", "trades.aapl", true},
+		{"trades.*", "trades.", true},
+		{"trades.*", "trades", false},
+		{"trades.*", "tradesx.aapl", false},
+		{"trades.*", "stats.aapl", false},
+
+		// dotted symbols, * runs over the symbol's own dots
+		{"trades.*", Trades(event.Pair{Symbol: "BRK.B"}), true},
+		{"trades.brk.b", Trades(event.Pair{Symbol: "BRK.B"}), true},
+		{"trades.brk.*", Trades(event.Pair{Symbol: "BRK.B"}), true},
+		{"trades.brk", Trades(event.Pair{Symbol: "BRK.B"}), false},
+		{"trades.*", Trades(event.Pair{Symbol: "BINANCE:BTC.USDT"}), true},
+		{"trades.binance:*", Trades(event.Pair{Symbol: "BINANCE:BTC.USDT"}), true},
+		{"trades.*.usdt", Trades(event.Pair{Symbol: "BINANCE:BTC.USDT"}), true},
+		{"trades.*.usdt", Trades(event.Pair{Symbol: "BINANCE:BTC.USDC"}), false},
+
+		// * over several segments
+		{"candles.*", Candles("1m", event.Pair{Symbol: "AAPL"}), true},
+		{"candles.1m.*", Candles("1m", event.Pair{Symbol: "AAPL"}), true},
+		{"candles.1m.*", Candles("1h", event.Pair{Symbol: "AAPL"}), false},
+		{"candles.*.aapl", Candles("1h", event.Pair{Symbol: "AAPL"}), true},
+		{"*.aapl", Candles("1h", event.Pair{Symbol: "AAPL"}), true},
+		{"*.msft", Candles("1h", event.Pair{Symbol: "AAPL"}), false},
+		{"c*s.*.b*", Candles("1d", event.Pair{Symbol: "BRK.B"}), true},
+		{"**", "news.", true},
+		{"*a*a*", "trades.aapl", true},
+		{"*a*a*a*a*", "trades.aapl", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.topic); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.topic, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// Package calendar knows when the venues stockspider watches are trading:
+// regular hours, pre-market and after-hours sessions, exchange holidays and
+// half days, and 24/7 crypto.
+package calendar
+
+import (
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // so LoadLocation works on boxes without a zoneinfo db
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type Session int
+
+const (
+	Closed Session = iota
+	PreMarket
+	Regular
+	AfterHours
+	Continuous // venue never closes (crypto)
+)
+
+func (s Session) String() string {
+	switch s {
+	case PreMarket:
+		return "pre-market"
+	case Regular:
+		return "regular"
+	case AfterHours:
+		return "after-hours"
+	case Continuous:
+		return "24/7"
+	default:
+		return "closed"
+	}
+}
+
+// Hours are offsets from local midnight for one trading day.
+type Hours struct {
+	PreOpen   time.Duration
+	Open      time.Duration
+	Close     time.Duration
+	PostClose time.Duration
+}
+
+// Exchange describes the trading calendar of one venue.
+type Exchange struct {
+	Code       string // finnhub exchange code, e.g. "US"
+	Name       string
+	Location   *time.Location
+	Hours      Hours
+	HalfDay    Hours // hours on early close days
+	AlwaysOpen bool
+
+	// holidays and halfDays return the closures/early closes of a year keyed by "2006-01-02"
+	holidays func(year int) map[string]string
+	halfDays func(year int) map[string]string
+
+	mu    sync.Mutex
+	years map[int]yearRules
+}
+
+type yearRules struct {
+	holidays map[string]string
+	halfDays map[string]string
+}
+
+func hm(h, m int) time.Duration {
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+}
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// US covers NYSE and NASDAQ, which share the same holiday and half-day rules.
+var US = &Exchange{
+	Code:     "US",
+	Name:     "NYSE/NASDAQ",
+	Location: mustLoad("America/New_York"),
+	Hours:    Hours{PreOpen: hm(4, 0), Open: hm(9, 30), Close: hm(16, 0), PostClose: hm(20, 0)},
+	HalfDay:  Hours{PreOpen: hm(4, 0), Open: hm(9, 30), Close: hm(13, 0), PostClose: hm(17, 0)},
+	holidays: usHolidays,
+	halfDays: usHalfDays,
+}
+
+var LSE = &Exchange{
+	Code:     "L",
+	Name:     "London",
+	Location: mustLoad("Europe/London"),
+	Hours:    Hours{PreOpen: hm(8, 0), Open: hm(8, 0), Close: hm(16, 30), PostClose: hm(16, 30)},
+	HalfDay:  Hours{PreOpen: hm(8, 0), Open: hm(8, 0), Close: hm(12, 30), PostClose: hm(12, 30)},
+	holidays: lseHolidays,
+	halfDays: lseHalfDays,
+}
+
+var TSX = &Exchange{
+	Code:     "TO",
+	Name:     "Toronto",
+	Location: mustLoad("America/Toronto"),
+	Hours:    Hours{PreOpen: hm(9, 30), Open: hm(9, 30), Close: hm(16, 0), PostClose: hm(16, 0)},
+	HalfDay:  Hours{PreOpen: hm(9, 30), Open: hm(9, 30), Close: hm(13, 0), PostClose: hm(13, 0)},
+	holidays: tsxHolidays,
+	halfDays: tsxHalfDays,
+}
+
+var Crypto = &Exchange{
+	Code:       "CRYPTO",
+	Name:       "Crypto",
+	Location:   time.UTC,
+	AlwaysOpen: true,
+}
+
+// cryptoPrefixes are the finnhub exchange prefixes of 24/7 venues
+var cryptoPrefixes = []string{
+	"BINANCE:", "COINBASE:", "KRAKEN:", "BITFINEX:", "BITSTAMP:", "GEMINI:",
+	"HUOBI:", "KUCOIN:", "OKEX:", "POLONIEX:", "BITMEX:", "HITBTC:",
+}
+
+// Exchanges is every venue the calendar knows, in display order.
+var Exchanges = []*Exchange{US, LSE, TSX, Crypto}
+
+// ByCode looks an exchange up by its finnhub code.
+func ByCode(code string) (*Exchange, bool) {
+	for _, e := range Exchanges {
+		if e.Code == code {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// For returns the exchange calendar that applies to pair. Symbols are the
+// full finnhub symbols ("BINANCE:BTCUSDT", "VOD.L", "AAPL"), in any case.
+func For(pair event.Pair) *Exchange {
+	sym := strings.ToUpper(pair.Symbol)
+	for _, prefix := range cryptoPrefixes {
+		if strings.HasPrefix(sym, prefix) {
+			return Crypto
+		}
+	}
+	switch {
+	case strings.HasSuffix(sym, ".L"):
+		return LSE
+	case strings.HasSuffix(sym, ".TO"):
+		return TSX
+	}
+	return US
+}
+
+func IsOpen(pair event.Pair, t time.Time) bool {
+	return For(pair).IsOpen(t)
+}
+
+func SessionAt(pair event.Pair, t time.Time) Session {
+	return For(pair).SessionAt(t)
+}
+
+func NextOpen(pair event.Pair, t time.Time) time.Time {
+	return For(pair).NextOpen(t)
+}
+
+func NextClose(pair event.Pair, t time.Time) time.Time {
+	return For(pair).NextClose(t)
+}
+
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func (e *Exchange) rules(year int) yearRules {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.years == nil {
+		e.years = make(map[int]yearRules)
+	}
+	r, ok := e.years[year]
+	if !ok {
+		if e.holidays != nil {
+			r.holidays = e.holidays(year)
+		}
+		if e.halfDays != nil {
+			r.halfDays = e.halfDays(year)
+		}
+		e.years[year] = r
+	}
+	return r
+}
+
+// Holiday reports whether the venue is closed all day on t's local date, and why.
+func (e *Exchange) Holiday(t time.Time) (string, bool) {
+	if e.AlwaysOpen {
+		return "", false
+	}
+	t = t.In(e.Location)
+	name, ok := e.rules(t.Year()).holidays[dayKey(t)]
+	return name, ok
+}
+
+// IsHalfDay reports whether t's local date is an early close day.
+func (e *Exchange) IsHalfDay(t time.Time) bool {
+	if e.AlwaysOpen {
+		return false
+	}
+	t = t.In(e.Location)
+	_, ok := e.rules(t.Year()).halfDays[dayKey(t)]
+	return ok
+}
+
+// IsTradingDay reports whether the venue opens at all on t's local date.
+func (e *Exchange) IsTradingDay(t time.Time) bool {
+	if e.AlwaysOpen {
+		return true
+	}
+	t = t.In(e.Location)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := e.Holiday(t)
+	return !holiday
+}
+
+// day returns the session boundaries of t's local date
+func (e *Exchange) day(t time.Time) (preOpen, open, close, postClose time.Time) {
+	t = t.In(e.Location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, e.Location)
+	h := e.Hours
+	if e.IsHalfDay(t) {
+		h = e.HalfDay
+	}
+	at := func(d time.Duration) time.Time {
+		// go through the wall clock so DST days still land on the right time
+		return time.Date(midnight.Year(), midnight.Month(), midnight.Day(),
+			int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, e.Location)
+	}
+	return at(h.PreOpen), at(h.Open), at(h.Close), at(h.PostClose)
+}
+
+// TradingHours returns the regular session of t's local date, ok is false
+// when the venue does not trade that day or never closes.
+func (e *Exchange) TradingHours(t time.Time) (open, close time.Time, ok bool) {
+	if e.AlwaysOpen || !e.IsTradingDay(t) {
+		return time.Time{}, time.Time{}, false
+	}
+	_, open, close, _ = e.day(t)
+	return open, close, true
+}
+
+func (e *Exchange) SessionAt(t time.Time) Session {
+	if e.AlwaysOpen {
+		return Continuous
+	}
+	if !e.IsTradingDay(t) {
+		return Closed
+	}
+	preOpen, open, close, postClose := e.day(t)
+	switch {
+	case t.Before(preOpen):
+		return Closed
+	case t.Before(open):
+		if preOpen.Equal(open) {
+			return Closed
+		}
+		return PreMarket
+	case t.Before(close):
+		return Regular
+	case t.Before(postClose):
+		return AfterHours
+	default:
+		return Closed
+	}
+}
+
+// IsOpen reports whether the regular session is running at t.
+func (e *Exchange) IsOpen(t time.Time) bool {
+	s := e.SessionAt(t)
+	return s == Regular || s == Continuous
+}
+
+// maxScan bounds how far ahead NextOpen/NextClose look for a trading day
+const maxScan = 30
+
+// NextOpen returns the start of the next regular session after t. For a
+// venue that never closes it returns t.
+func (e *Exchange) NextOpen(t time.Time) time.Time {
+	if e.AlwaysOpen {
+		return t
+	}
+	d := t.In(e.Location)
+	for i := 0; i < maxScan; i++ {
+		if e.IsTradingDay(d) {
+			_, open, _, _ := e.day(d)
+			if open.After(t) {
+				return open
+			}
+		}
+		d = time.Date(d.Year(), d.Month(), d.Day()+1, 12, 0, 0, 0, e.Location)
+	}
+	return time.Time{}
+}
+
+// NextClose returns the end of the current regular session, or of the next
+// one if the venue is closed at t. For a venue that never closes it returns
+// the zero time.
+func (e *Exchange) NextClose(t time.Time) time.Time {
+	if e.AlwaysOpen {
+		return time.Time{}
+	}
+	d := t.In(e.Location)
+	for i := 0; i < maxScan; i++ {
+		if e.IsTradingDay(d) {
+			_, _, close, _ := e.day(d)
+			if close.After(t) {
+				return close
+			}
+		}
+		d = time.Date(d.Year(), d.Month(), d.Day()+1, 12, 0, 0, 0, e.Location)
+	}
+	return time.Time{}
+}
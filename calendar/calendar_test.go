@@ -0,0 +1,144 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func at(loc *time.Location, value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestHolidays(t *testing.T) {
+	tests := []struct {
+		name    string
+		e       *Exchange
+		day     string
+		holiday string // "" for a day that trades
+	}{
+		{"juneteenth observed on monday", US, "2022-06-20", "Juneteenth"},
+		{"no juneteenth before 2022", US, "2021-06-18", ""},
+		{"good friday us", US, "2024-03-29", "Good Friday"},
+		{"good friday lse", LSE, "2024-03-29", "Good Friday"},
+		{"good friday tsx", TSX, "2023-04-07", "Good Friday"},
+		{"easter monday lse", LSE, "2024-04-01", "Easter Monday"},
+		{"easter monday trades in the us", US, "2024-04-01", ""},
+		{"sandy", US, "2012-10-29", "Hurricane Sandy"},
+		{"sandy second day", US, "2012-10-30", "Hurricane Sandy"},
+		{"after sandy", US, "2012-10-31", ""},
+		{"saturday new year not observed friday", US, "2021-12-31", ""},
+		{"sunday new year observed monday", US, "2023-01-02", "New Year's Day"},
+		{"lse 2022 spring moved", LSE, "2022-06-02", "Spring Bank Holiday"},
+		{"lse 2022 jubilee", LSE, "2022-06-03", "Platinum Jubilee"},
+		{"lse 2022 usual spring date trades", LSE, "2022-05-30", ""},
+		{"victoria day", TSX, "2024-05-20", "Victoria Day"},
+		{"victoria day on the 24th", TSX, "2021-05-24", "Victoria Day"},
+		{"victoria day when the 25th is a monday", TSX, "2020-05-18", "Victoria Day"},
+		{"the 25th itself isn't victoria day", TSX, "2020-05-25", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := at(tt.e.Location, tt.day+" 12:00")
+			name, ok := tt.e.Holiday(day)
+			if tt.holiday == "" {
+				if ok {
+					t.Fatalf("%s %s is %q, want a trading day", tt.e.Code, tt.day, name)
+				}
+				if !tt.e.IsTradingDay(day) {
+					t.Fatalf("%s %s isn't a trading day", tt.e.Code, tt.day)
+				}
+				return
+			}
+			if !ok || name != tt.holiday {
+				t.Fatalf("%s %s is %q (%v), want %q", tt.e.Code, tt.day, name, ok, tt.holiday)
+			}
+			if tt.e.IsTradingDay(day) {
+				t.Fatalf("%s %s is a trading day", tt.e.Code, tt.day)
+			}
+		})
+	}
+}
+
+func TestHalfDays(t *testing.T) {
+	tests := []struct {
+		e    *Exchange
+		day  string
+		half bool
+	}{
+		{US, "2024-07-03", true},
+		{US, "2024-11-29", true},
+		{US, "2024-12-24", true},
+		{US, "2024-07-02", false},
+		// july 3rd 2022 is a sunday
+		{US, "2022-07-03", false},
+		{LSE, "2024-12-24", true},
+		{LSE, "2024-12-31", true},
+		{TSX, "2024-12-24", true},
+	}
+	for _, tt := range tests {
+		day := at(tt.e.Location, tt.day+" 12:00")
+		if got := tt.e.IsHalfDay(day); got != tt.half {
+			t.Errorf("%s %s half day %v, want %v", tt.e.Code, tt.day, got, tt.half)
+		}
+	}
+
+	// the us closes at 13:00 with the after hours session shortened to match
+	day := at(US.Location, "2024-07-03 12:00")
+	if _, close, ok := US.TradingHours(day); !ok || !close.Equal(at(US.Location, "2024-07-03 13:00")) {
+		t.Fatalf("2024-07-03 closes %v, want 13:00", close)
+	}
+	if s := US.SessionAt(at(US.Location, "2024-07-03 14:00")); s != AfterHours {
+		t.Fatalf("2024-07-03 14:00 is %v, want after hours", s)
+	}
+	if s := US.SessionAt(at(US.Location, "2024-07-03 17:30")); s != Closed {
+		t.Fatalf("2024-07-03 17:30 is %v, want closed", s)
+	}
+}
+
+func TestNextOpenClose(t *testing.T) {
+	tests := []struct {
+		name  string
+		e     *Exchange
+		from  string // in the exchange's time
+		open  string // utc
+		close string // utc
+	}{
+		// new york springs forward on sunday the 10th, monday opens an
+		// hour earlier in utc than friday did
+		{"weekend and dst", US, "2024-03-08 17:00", "2024-03-11 13:30", "2024-03-11 20:00"},
+		{"saturday", US, "2024-03-09 10:00", "2024-03-11 13:30", "2024-03-11 20:00"},
+		{"during the session", US, "2024-03-11 10:00", "2024-03-12 13:30", "2024-03-11 20:00"},
+		{"before the open", US, "2024-03-11 08:00", "2024-03-11 13:30", "2024-03-11 20:00"},
+		{"over good friday", US, "2024-03-28 16:30", "2024-04-01 13:30", "2024-04-01 20:00"},
+		{"half day", US, "2024-07-03 10:00", "2024-07-05 13:30", "2024-07-03 17:00"},
+		{"over sandy", US, "2012-10-26 17:00", "2012-10-31 13:30", "2012-10-31 20:00"},
+		// london falls back on sunday the 27th
+		{"lse weekend and dst", LSE, "2024-10-25 17:00", "2024-10-28 08:00", "2024-10-28 16:30"},
+		{"lse over easter", LSE, "2024-03-28 17:00", "2024-04-02 07:00", "2024-04-02 15:30"},
+		{"lse over the jubilee", LSE, "2022-06-01 17:00", "2022-06-06 07:00", "2022-06-06 15:30"},
+		{"tsx over victoria day", TSX, "2024-05-17 17:00", "2024-05-21 13:30", "2024-05-21 20:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := at(tt.e.Location, tt.from)
+			if got, want := tt.e.NextOpen(from), at(time.UTC, tt.open); !got.Equal(want) {
+				t.Errorf("next open %v, want %v", got.UTC(), want)
+			}
+			if got, want := tt.e.NextClose(from), at(time.UTC, tt.close); !got.Equal(want) {
+				t.Errorf("next close %v, want %v", got.UTC(), want)
+			}
+		})
+	}
+
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	if got := Crypto.NextOpen(now); !got.Equal(now) {
+		t.Errorf("crypto next open %v, want %v", got, now)
+	}
+	if got := Crypto.NextClose(now); !got.IsZero() {
+		t.Errorf("crypto next close %v, want never", got)
+	}
+}
@@ -0,0 +1,250 @@
+package calendar
+
+import "time"
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth (1-based) weekday of a month, or the last one when n is -1
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		d := date(year, month+1, 0)
+		for d.Weekday() != weekday {
+			d = d.AddDate(0, 0, -1)
+		}
+		return d
+	}
+	d := date(year, month, 1)
+	for d.Weekday() != weekday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d.AddDate(0, 0, 7*(n-1))
+}
+
+// easter returns easter sunday (gregorian, anonymous algorithm)
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// observed moves a saturday holiday to friday and a sunday one to monday
+func observed(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// substitute moves a weekend holiday to the following monday
+func substitute(d time.Time) time.Time {
+	for isWeekend(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// christmas returns christmas and boxing day with the commonwealth
+// substitute days, so neither lands on a weekend or on the other
+func christmas(year int) (christmasDay, boxingDay time.Time) {
+	christmasDay = substitute(date(year, time.December, 25))
+	boxingDay = substitute(date(year, time.December, 26))
+	if boxingDay.Equal(christmasDay) {
+		boxingDay = boxingDay.AddDate(0, 0, 1)
+	}
+	return christmasDay, boxingDay
+}
+
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// usSpecialClosures are unscheduled full-day NYSE closures
+var usSpecialClosures = map[string]string{
+	"2001-09-11": "September 11",
+	"2001-09-12": "September 11",
+	"2001-09-13": "September 11",
+	"2001-09-14": "September 11",
+	"2004-06-11": "Reagan Day of Mourning",
+	"2007-01-02": "Ford Day of Mourning",
+	"2012-10-29": "Hurricane Sandy",
+	"2012-10-30": "Hurricane Sandy",
+	"2018-12-05": "Bush Day of Mourning",
+	"2025-01-09": "Carter Day of Mourning",
+}
+
+// usHolidays follows NYSE rule 7.2. A new years day falling on a saturday
+// is not observed on the friday before, unlike the other fixed-date holidays.
+func usHolidays(year int) map[string]string {
+	days := make(map[string]string)
+	add := func(d time.Time, name string) {
+		if d.Year() == year && !isWeekend(d) {
+			days[dayKey(d)] = name
+		}
+	}
+
+	newYear := date(year, time.January, 1)
+	if newYear.Weekday() == time.Sunday {
+		newYear = newYear.AddDate(0, 0, 1)
+	}
+	add(newYear, "New Year's Day")
+	if year >= 1998 {
+		add(nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day")
+	}
+	add(nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday")
+	add(easter(year).AddDate(0, 0, -2), "Good Friday")
+	add(nthWeekday(year, time.May, time.Monday, -1), "Memorial Day")
+	if year >= 2022 {
+		add(observed(date(year, time.June, 19)), "Juneteenth")
+	}
+	add(observed(date(year, time.July, 4)), "Independence Day")
+	add(nthWeekday(year, time.September, time.Monday, 1), "Labor Day")
+	add(nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day")
+	add(observed(date(year, time.December, 25)), "Christmas Day")
+
+	for day, name := range usSpecialClosures {
+		if d, err := time.Parse("2006-01-02", day); err == nil && d.Year() == year {
+			days[day] = name
+		}
+	}
+	return days
+}
+
+// usHalfDays are the 13:00 early closes: the day before independence day,
+// the day after thanksgiving and christmas eve, when they are trading days.
+func usHalfDays(year int) map[string]string {
+	holidays := usHolidays(year)
+	days := make(map[string]string)
+	add := func(d time.Time, name string) {
+		if _, closed := holidays[dayKey(d)]; !closed && !isWeekend(d) {
+			days[dayKey(d)] = name
+		}
+	}
+
+	add(date(year, time.July, 3), "Independence Day Eve")
+	add(nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1), "Day after Thanksgiving")
+	add(date(year, time.December, 24), "Christmas Eve")
+	return days
+}
+
+// lseSpecialClosures are one-off UK bank holidays
+var lseSpecialClosures = map[string]string{
+	"2002-06-03": "Golden Jubilee",
+	"2011-04-29": "Royal Wedding",
+	"2012-06-05": "Diamond Jubilee",
+	"2022-06-03": "Platinum Jubilee",
+	"2022-09-19": "State Funeral",
+	"2023-05-08": "Coronation",
+}
+
+// lseHolidays are the england and wales bank holidays, including the years
+// the early may and spring bank holidays were moved.
+func lseHolidays(year int) map[string]string {
+	days := make(map[string]string)
+	add := func(d time.Time, name string) {
+		if d.Year() == year && !isWeekend(d) {
+			days[dayKey(d)] = name
+		}
+	}
+
+	add(substitute(date(year, time.January, 1)), "New Year's Day")
+	add(easter(year).AddDate(0, 0, -2), "Good Friday")
+	add(easter(year).AddDate(0, 0, 1), "Easter Monday")
+
+	earlyMay := nthWeekday(year, time.May, time.Monday, 1)
+	if year == 2020 {
+		earlyMay = date(2020, time.May, 8) // VE day
+	}
+	add(earlyMay, "Early May Bank Holiday")
+
+	spring := nthWeekday(year, time.May, time.Monday, -1)
+	switch year {
+	case 2002, 2012:
+		spring = date(year, time.June, 4)
+	case 2022:
+		spring = date(year, time.June, 2)
+	}
+	add(spring, "Spring Bank Holiday")
+	add(nthWeekday(year, time.August, time.Monday, -1), "Summer Bank Holiday")
+
+	christmasDay, boxingDay := christmas(year)
+	add(christmasDay, "Christmas Day")
+	add(boxingDay, "Boxing Day")
+
+	for day, name := range lseSpecialClosures {
+		if d, err := time.Parse("2006-01-02", day); err == nil && d.Year() == year {
+			days[day] = name
+		}
+	}
+	return days
+}
+
+// lseHalfDays close at 12:30 on christmas eve and new years eve
+func lseHalfDays(year int) map[string]string {
+	holidays := lseHolidays(year)
+	days := make(map[string]string)
+	for _, d := range []time.Time{date(year, time.December, 24), date(year, time.December, 31)} {
+		if _, closed := holidays[dayKey(d)]; !closed && !isWeekend(d) {
+			days[dayKey(d)] = d.Format("January 2")
+		}
+	}
+	return days
+}
+
+func tsxHolidays(year int) map[string]string {
+	days := make(map[string]string)
+	add := func(d time.Time, name string) {
+		if d.Year() == year && !isWeekend(d) {
+			days[dayKey(d)] = name
+		}
+	}
+
+	add(substitute(date(year, time.January, 1)), "New Year's Day")
+	if year >= 2008 {
+		add(nthWeekday(year, time.February, time.Monday, 3), "Family Day")
+	}
+	add(easter(year).AddDate(0, 0, -2), "Good Friday")
+
+	// victoria day is the last monday before may 25th
+	victoria := date(year, time.May, 24)
+	for victoria.Weekday() != time.Monday {
+		victoria = victoria.AddDate(0, 0, -1)
+	}
+	add(victoria, "Victoria Day")
+	add(substitute(date(year, time.July, 1)), "Canada Day")
+	add(nthWeekday(year, time.August, time.Monday, 1), "Civic Holiday")
+	add(nthWeekday(year, time.September, time.Monday, 1), "Labour Day")
+	add(nthWeekday(year, time.October, time.Monday, 2), "Thanksgiving Day")
+
+	christmasDay, boxingDay := christmas(year)
+	add(christmasDay, "Christmas Day")
+	add(boxingDay, "Boxing Day")
+	return days
+}
+
+// tsxHalfDays close at 13:00 on christmas eve
+func tsxHalfDays(year int) map[string]string {
+	holidays := tsxHolidays(year)
+	days := make(map[string]string)
+	d := date(year, time.December, 24)
+	if _, closed := holidays[dayKey(d)]; !closed && !isWeekend(d) {
+		days[dayKey(d)] = "Christmas Eve"
+	}
+	return days
+}
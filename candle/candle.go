@@ -0,0 +1,95 @@
+// Package candle aggregates trades into OHLCV bars and keeps per-symbol
+// series that live bars and backfilled history are merged into.
+package candle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type Timeframe struct {
+	Name       string
+	Duration   time.Duration
+	Resolution string // finnhub candle resolution
+}
+
+var (
+	M1  = Timeframe{Name: "1m", Duration: time.Minute, Resolution: "1"}
+	M5  = Timeframe{Name: "5m", Duration: 5 * time.Minute, Resolution: "5"}
+	M15 = Timeframe{Name: "15m", Duration: 15 * time.Minute, Resolution: "15"}
+	M30 = Timeframe{Name: "30m", Duration: 30 * time.Minute, Resolution: "30"}
+	H1  = Timeframe{Name: "1h", Duration: time.Hour, Resolution: "60"}
+	D1  = Timeframe{Name: "1d", Duration: 24 * time.Hour, Resolution: "D"}
+)
+
+// Timeframes are the supported timeframes, shortest first.
+var Timeframes = []Timeframe{M1, M5, M15, M30, H1, D1}
+
+func ParseTimeframe(name string) (Timeframe, error) {
+	for _, tf := range Timeframes {
+		if strings.EqualFold(tf.Name, name) {
+			return tf, nil
+		}
+	}
+	return Timeframe{}, fmt.Errorf("unknown timeframe %q", name)
+}
+
+// Bucket returns the open time (unix seconds) of the bar containing unix.
+func (tf Timeframe) Bucket(unix int64) int64 {
+	secs := int64(tf.Duration / time.Second)
+	if unix < 0 {
+		return unix - (secs+unix%secs)%secs
+	}
+	return unix - unix%secs
+}
+
+// Seconds is the bar length in seconds.
+func (tf Timeframe) Seconds() int64 {
+	return int64(tf.Duration / time.Second)
+}
+
+// FromTrades aggregates trades into bars of tf, oldest first. Trades
+// don't have to be sorted.
+func FromTrades(pair event.Pair, tf Timeframe, trades []event.StockTrade) []event.Candle {
+	s := NewSeries(pair, tf, 0)
+	for _, trade := range trades {
+		s.AddTrade(trade)
+	}
+	return s.Bars()
+}
+
+// Resample rolls bars up into bars of tf, oldest first. the bars have to
+// be of a timeframe tf is a multiple of, and sorted.
+func Resample(tf Timeframe, bars []event.Candle) []event.Candle {
+	var out []event.Candle
+	for _, bar := range bars {
+		bucket := tf.Bucket(bar.Unix)
+		if n := len(out); n > 0 && out[n-1].Unix == bucket {
+			last := &out[n-1]
+			last.High = max(last.High, bar.High)
+			last.Low = min(last.Low, bar.Low)
+			last.Close = bar.Close
+			last.Volume += bar.Volume
+			continue
+		}
+		bar.Unix = bucket
+		bar.Timeframe = tf.Name
+		out = append(out, bar)
+	}
+	return out
+}
+
+// apply folds a trade into a bar that already has an open
+func apply(bar *event.Candle, trade event.StockTrade) {
+	if trade.Price > bar.High {
+		bar.High = trade.Price
+	}
+	if trade.Price < bar.Low {
+		bar.Low = trade.Price
+	}
+	bar.Close = trade.Price
+	bar.Volume += trade.Qty
+}
@@ -0,0 +1,248 @@
+package candle
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// Series is the bar history of one pair at one timeframe, oldest first.
+type Series struct {
+	pair event.Pair
+	tf   Timeframe
+	max  int // 0 keeps everything
+
+	bars []event.Candle
+	// lastTrade is the newest trade folded in, older (late) trades still go
+	// into their own bucket but don't move the close
+	lastTrade int64
+}
+
+func NewSeries(pair event.Pair, tf Timeframe, max int) *Series {
+	return &Series{pair: pair, tf: tf, max: max}
+}
+
+func (s *Series) Bars() []event.Candle {
+	bars := make([]event.Candle, len(s.bars))
+	copy(bars, s.bars)
+	return bars
+}
+
+// index finds the bar of bucket, or where it would be inserted
+func (s *Series) index(bucket int64) (int, bool) {
+	i := sort.Search(len(s.bars), func(i int) bool { return s.bars[i].Unix >= bucket })
+	return i, i < len(s.bars) && s.bars[i].Unix == bucket
+}
+
+func (s *Series) insert(i int, bar event.Candle) {
+	s.bars = append(s.bars, event.Candle{})
+	copy(s.bars[i+1:], s.bars[i:])
+	s.bars[i] = bar
+}
+
+func (s *Series) trim() {
+	if s.max > 0 && len(s.bars) > s.max {
+		s.bars = s.bars[len(s.bars)-s.max:]
+	}
+}
+
+// AddTrade folds trade into its bar. When the trade opens a new newest
+// bar the one before it is finished and comes back with closed true
+func (s *Series) AddTrade(trade event.StockTrade) (finished event.Candle, closed bool) {
+	bucket := s.tf.Bucket(trade.Unix / 1000)
+	i, ok := s.index(bucket)
+	if !ok {
+		if i == len(s.bars) && i > 0 {
+			finished, closed = s.bars[i-1], true
+		}
+		s.insert(i, event.Candle{
+			Pair:      s.pair,
+			Timeframe: s.tf.Name,
+			Unix:      bucket,
+			Open:      trade.Price,
+			High:      trade.Price,
+			Low:       trade.Price,
+			Close:     trade.Price,
+		})
+	}
+
+	bar := &s.bars[i]
+	close := bar.Close
+	apply(bar, trade)
+	if trade.Unix < s.lastTrade {
+		bar.Close = close
+	}
+	if trade.Unix > s.lastTrade {
+		s.lastTrade = trade.Unix
+	}
+	s.trim()
+	return finished, closed
+}
+
+// Merge folds historical bars into the series. Buckets we don't have are
+// inserted as is. A bucket both sides have is the seam between history and
+// live data: it keeps the earliest open, the widest range and the live
+// close, and takes the larger volume since both sides may have counted the
+// same trades.
+func (s *Series) Merge(history []event.Candle) {
+	for _, h := range history {
+		h.Pair = s.pair
+		h.Timeframe = s.tf.Name
+		h.Unix = s.tf.Bucket(h.Unix)
+
+		i, ok := s.index(h.Unix)
+		if !ok {
+			s.insert(i, h)
+			continue
+		}
+
+		live := &s.bars[i]
+		live.Open = h.Open
+		if h.High > live.High {
+			live.High = h.High
+		}
+		if h.Low < live.Low || live.Low == 0 {
+			live.Low = h.Low
+		}
+		if live.Volume == 0 {
+			live.Close = h.Close
+		}
+		if h.Volume > live.Volume {
+			live.Volume = h.Volume
+		}
+	}
+	s.trim()
+}
+
+// Gap is a run of bars missing while the venue was trading.
+type Gap struct {
+	From time.Time // first missing bucket
+	To   time.Time // end of the last missing bucket
+}
+
+// Gaps returns the missing stretches of the series, ignoring time the
+// exchange was closed so nights and weekends don't count.
+func (s *Series) Gaps(exchange *calendar.Exchange) []Gap {
+	return Gaps(s.bars, s.tf, exchange)
+}
+
+func Gaps(bars []event.Candle, tf Timeframe, exchange *calendar.Exchange) []Gap {
+	var gaps []Gap
+	step := tf.Seconds()
+
+	for i := 1; i < len(bars); i++ {
+		var gap *Gap
+		for bucket := bars[i-1].Unix + step; bucket < bars[i].Unix; bucket += step {
+			t := time.Unix(bucket, 0)
+			if tf.Duration < 24*time.Hour && !exchange.IsOpen(t) {
+				gap = nil
+				continue
+			}
+			// daily buckets sit at 00:00 utc, which is the evening before in
+			// the americas. the bucket's utc date is the venue's trading day
+			if tf.Duration >= 24*time.Hour && !exchange.IsTradingDay(venueDay(t, exchange)) {
+				gap = nil
+				continue
+			}
+			if gap == nil {
+				gaps = append(gaps, Gap{From: t})
+				gap = &gaps[len(gaps)-1]
+			}
+			gap.To = t.Add(tf.Duration)
+		}
+	}
+	return gaps
+}
+
+// venueDay is noon on t's utc date in the exchange's own time
+func venueDay(t time.Time, exchange *calendar.Exchange) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 12, 0, 0, 0, exchange.Location)
+}
+
+// Aggregator keeps live series for every pair and timeframe it sees trades for.
+type Aggregator struct {
+	mu         sync.Mutex
+	timeframes []Timeframe
+	max        int
+	series     map[string]*Series
+}
+
+// NewAggregator builds bars for each timeframe in tfs, keeping at most max
+// bars per series.
+func NewAggregator(max int, tfs ...Timeframe) *Aggregator {
+	if len(tfs) == 0 {
+		tfs = Timeframes
+	}
+	return &Aggregator{
+		timeframes: tfs,
+		max:        max,
+		series:     make(map[string]*Series),
+	}
+}
+
+func seriesKey(pair event.Pair, tf Timeframe) string {
+	return strings.ToLower(pair.Symbol) + "|" + tf.Name
+}
+
+func (a *Aggregator) get(pair event.Pair, tf Timeframe) *Series {
+	key := seriesKey(pair, tf)
+	s, ok := a.series[key]
+	if !ok {
+		s = NewSeries(pair, tf, a.max)
+		a.series[key] = s
+	}
+	return s
+}
+
+// AddTrade folds trade into every timeframe and returns the bars it
+// finished, usually none
+func (a *Aggregator) AddTrade(trade event.StockTrade) []event.Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var finished []event.Candle
+	for _, tf := range a.timeframes {
+		if bar, closed := a.get(trade.Pair, tf).AddTrade(trade); closed {
+			finished = append(finished, bar)
+		}
+	}
+	return finished
+}
+
+// Bars returns a copy of the bars of pair at tf.
+func (a *Aggregator) Bars(pair event.Pair, tf Timeframe) []event.Candle {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.series[seriesKey(pair, tf)]
+	if !ok {
+		return nil
+	}
+	return s.Bars()
+}
+
+// Merge folds backfilled history into the live series of pair at tf and
+// returns the gaps left in it.
+func (a *Aggregator) Merge(pair event.Pair, tf Timeframe, history []event.Candle) []Gap {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := a.get(pair, tf)
+	s.Merge(history)
+	return s.Gaps(calendar.For(pair))
+}
+
+// Reset drops every series of pair.
+func (a *Aggregator) Reset(pair event.Pair) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, tf := range a.timeframes {
+		delete(a.series, seriesKey(pair, tf))
+	}
+}
@@ -0,0 +1,66 @@
+package candle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+func utc(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestGaps(t *testing.T) {
+	day := func(d string) string { return d + " 00:00" }
+	tests := []struct {
+		name     string
+		tf       Timeframe
+		exchange *calendar.Exchange
+		bars     []string // bucket starts, utc
+		want     []Gap
+	}{
+		{"us weekend", D1, calendar.US,
+			[]string{day("2024-03-08"), day("2024-03-11")}, nil},
+		{"us good friday and weekend", D1, calendar.US,
+			[]string{day("2024-03-28"), day("2024-04-01")}, nil},
+		{"us missing days", D1, calendar.US,
+			[]string{day("2024-03-11"), day("2024-03-14")},
+			[]Gap{{From: utc(day("2024-03-12")), To: utc(day("2024-03-14"))}}},
+		{"us missing friday before the weekend", D1, calendar.US,
+			[]string{day("2024-03-07"), day("2024-03-11")},
+			[]Gap{{From: utc(day("2024-03-08")), To: utc(day("2024-03-09"))}}},
+		{"tsx victoria day", D1, calendar.TSX,
+			[]string{day("2024-05-17"), day("2024-05-21")}, nil},
+		{"crypto never closes", D1, calendar.Crypto,
+			[]string{day("2024-03-08"), day("2024-03-11")},
+			[]Gap{{From: utc(day("2024-03-09")), To: utc(day("2024-03-11"))}}},
+		// friday's last hour into monday's first, dst switched in between
+		{"us hourly weekend", H1, calendar.US,
+			[]string{"2024-03-08 20:00", "2024-03-11 13:00"}, nil},
+		{"us hourly missing hour", H1, calendar.US,
+			[]string{"2024-03-11 14:00", "2024-03-11 16:00"},
+			[]Gap{{From: utc("2024-03-11 15:00"), To: utc("2024-03-11 16:00")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bars := make([]event.Candle, len(tt.bars))
+			for i, b := range tt.bars {
+				bars[i] = event.Candle{Timeframe: tt.tf.Name, Unix: utc(b).Unix()}
+			}
+			got := Gaps(bars, tt.tf, tt.exchange)
+			for i := range got {
+				got[i].From, got[i].To = got[i].From.UTC(), got[i].To.UTC()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("gaps %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
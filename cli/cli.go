@@ -0,0 +1,176 @@
+// Package cli is stockspider's scripted side: subcommands that print
+// quotes, stored trades, bars, fundamentals and status as a table, JSON
+// or CSV and exit, for shell pipelines and cron jobs.
+//
+//	stockspider quote AAPL MSFT
+//	stockspider trades AAPL --since 10m -o csv
+//	stockspider candles ETH --tf 5m --from 2024-06-01 --to 2024-06-02 -o json
+//	stockspider metrics AAPL
+//	stockspider status
+//	stockspider export candles AAPL MSFT --tf 1m --from 2024-06-03 --out bars.parquet
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/symbolArray"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+type command struct {
+	usage string
+	help  string
+	run   func(env *Env, args []string) error
+}
+
+var commands = map[string]command{}
+
+// Env is what commands print to and read from
+type Env struct {
+	Out    io.Writer
+	Format Format
+	// HubAddr is the running app's metrics address, where the local hub
+	// serves its quotes
+	HubAddr string
+	// TicksDir and QuotesDir are the stores the app records into
+	TicksDir  string
+	QuotesDir string
+
+	client *FH.DefaultApiService
+}
+
+// Client is the finnhub REST client, it needs API_KEY
+func (env *Env) Client() (*FH.DefaultApiService, error) {
+	if env.client != nil {
+		return env.client, nil
+	}
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("API_KEY is not set")
+	}
+	cfg := FH.NewConfiguration()
+	cfg.AddDefaultHeader("X-Finnhub-Token", apiKey)
+	cfg.HTTPClient = &http.Client{Transport: metrics.Transport(nil), Timeout: 30 * time.Second}
+	env.client = FH.NewAPIClient(cfg).DefaultApi
+	return env.client, nil
+}
+
+// IsCommand reports whether name is a subcommand, main hands those to Run
+// instead of opening the window
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok || name == "help" || name == "-h" || name == "--help"
+}
+
+// Run runs the subcommand in args[0]
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(out)
+		return nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage(out)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	hubAddr := os.Getenv("METRICS_ADDR")
+	if hubAddr == "" || hubAddr == "off" {
+		hubAddr = ":2112"
+	}
+	env := &Env{Out: out, HubAddr: hubAddr, TicksDir: "data/ticks", QuotesDir: "data/quotes"}
+	if err := cmd.run(env, args[1:]); !errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	// -h already printed the flags
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: stockspider [command] [flags]")
+	fmt.Fprintln(w, "\nwithout a command the GUI opens. commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-40s %s\n", commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintln(w, "\nevery command takes -o table|json|csv, `stockspider <command> -h` lists the rest")
+}
+
+// flags is a command's flag set with -o already on it
+func flags(env *Env, name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	format := fs.String("o", string(Table), "output format: table, json or csv")
+	fs.StringVar(&env.HubAddr, "hub", env.HubAddr, "address of the running app's metrics server")
+	return fs, format
+}
+
+// parse parses flags wherever they are among the positional arguments,
+// "trades AAPL --since 10m" as well as "trades --since 10m AAPL". format
+// is nil for commands that don't print through Output
+func parse(env *Env, fs *flag.FlagSet, format *string, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if format == nil {
+		return positional, nil
+	}
+	f, err := ParseFormat(*format)
+	if err != nil {
+		return nil, err
+	}
+	env.Format = f
+	return positional, nil
+}
+
+// pair is the finnhub pair of a symbol as typed, display names like
+// "ETH" resolve to their full symbol
+func pair(symbol string) event.Pair {
+	if full, ok := symbolArray.Symbols[strings.ToUpper(symbol)]; ok {
+		symbol = full
+	}
+	return event.Pair{Exchange: "finnhub", Symbol: strings.ToUpper(symbol)}
+}
+
+// timeLayouts are what --from and --to take, besides a duration back
+// from now like 90m
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time, use 2006-01-02, 2006-01-02 15:04, RFC 3339 or a duration like 90m", s)
+}
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%.4f", v)
+}
@@ -0,0 +1,462 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/corporate"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/eventpb"
+	"github.com/Scrimzay/stockspider/fundamentals"
+	"github.com/Scrimzay/stockspider/hub"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/store"
+)
+
+// how long a command waits on the local app before giving up on it
+const hubTimeout = 2 * time.Second
+
+func init() {
+	commands["quote"] = command{"quote SYMBOL...", "latest quotes, from the running app or finnhub", quoteCmd}
+	commands["trades"] = command{"trades SYMBOL [--since 10m]", "trades recorded in the tick store", tradesCmd}
+	commands["candles"] = command{"candles SYMBOL [--tf 5m] [--from] [--to]", "OHLCV bars from the tick store and finnhub", candlesCmd}
+	commands["metrics"] = command{"metrics SYMBOL", "finnhub basic financials", metricsCmd}
+	commands["status"] = command{"status", "feed health and market sessions", statusCmd}
+}
+
+// hubURL is path on the running app, ":2112" means this machine
+func (env *Env) hubURL(path string) string {
+	addr := env.HubAddr
+	if strings.HasPrefix(addr, ":") {
+		addr = "localhost" + addr
+	}
+	return "http://" + addr + path
+}
+
+func (env *Env) hubGet(path string) (*http.Response, error) {
+	client := &http.Client{Timeout: hubTimeout}
+	return client.Get(env.hubURL(path))
+}
+
+type quoteRecord struct {
+	Symbol        string  `json:"symbol"`
+	Current       float32 `json:"current"`
+	Open          float32 `json:"open"`
+	High          float32 `json:"high"`
+	Low           float32 `json:"low"`
+	PrevClose     float32 `json:"prevClose"`
+	Change        float32 `json:"change"`
+	ChangePercent float32 `json:"changePercent"`
+	Source        string  `json:"source"`
+}
+
+func quoteCmd(env *Env, args []string) error {
+	fs, format := flags(env, "quote")
+	source := fs.String("source", "auto", "where quotes come from: auto (the running app, then finnhub), hub or finnhub")
+	symbols, err := parse(env, fs, format, args)
+	if err != nil {
+		return err
+	}
+	if len(symbols) == 0 {
+		return errors.New("quote needs at least one symbol")
+	}
+	switch *source {
+	case "auto", "hub", "finnhub":
+	default:
+		return fmt.Errorf("unknown source %q, want auto, hub or finnhub", *source)
+	}
+
+	found := make(map[string]event.Quote)
+	if *source != "finnhub" {
+		quotes, err := env.hubQuotes(symbols)
+		if err != nil && *source == "hub" {
+			return fmt.Errorf("asking the running app: %w", err)
+		}
+		found = quotes
+	}
+
+	var records []quoteRecord
+	for _, symbol := range symbols {
+		p := pair(symbol)
+		q, ok := found[p.Symbol]
+		src := "hub"
+		if !ok {
+			if *source == "hub" {
+				return fmt.Errorf("the running app has no quote for %s", p.Symbol)
+			}
+			client, err := env.Client()
+			if err != nil {
+				return err
+			}
+			if q, err = hub.FetchQuote(client, p.Symbol); err != nil {
+				return fmt.Errorf("fetching quote for %s: %w", p.Symbol, err)
+			}
+			src = "finnhub"
+		}
+		r := quoteRecord{
+			Symbol:    p.Symbol,
+			Current:   q.Current,
+			Open:      q.Open,
+			High:      q.High,
+			Low:       q.Low,
+			PrevClose: q.PrevClose,
+			Source:    src,
+		}
+		r.Change = q.Current - q.PrevClose
+		if q.PrevClose != 0 {
+			r.ChangePercent = r.Change / q.PrevClose * 100
+		}
+		records = append(records, r)
+	}
+
+	out := Output{
+		Header:  []string{"symbol", "current", "open", "high", "low", "prev_close", "change", "change_pct", "source"},
+		Records: records,
+	}
+	for _, r := range records {
+		out.Rows = append(out.Rows, []string{
+			r.Symbol,
+			formatFloat(float64(r.Current)),
+			formatFloat(float64(r.Open)),
+			formatFloat(float64(r.High)),
+			formatFloat(float64(r.Low)),
+			formatFloat(float64(r.PrevClose)),
+			formatFloat(float64(r.Change)),
+			fmt.Sprintf("%.2f", r.ChangePercent),
+			r.Source,
+		})
+	}
+	return out.Write(env.Out, env.Format)
+}
+
+// hubQuotes asks the running app for the quotes it holds, keyed by full symbol
+func (env *Env) hubQuotes(symbols []string) (map[string]event.Quote, error) {
+	query := url.Values{}
+	for _, symbol := range symbols {
+		query.Add("symbol", pair(symbol).Symbol)
+	}
+	res, err := env.hubGet("/quotes?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %s", env.hubURL("/quotes"), res.Status)
+	}
+
+	quotes := make(map[string]event.Quote)
+	dec := eventpb.NewDecoder(res.Body, eventpb.JSON)
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return quotes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if q, ok := v.(event.Quote); ok {
+			quotes[q.Pair.Symbol] = q
+		}
+	}
+}
+
+type tradeRecord struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Price  float64   `json:"price"`
+	Qty    float64   `json:"qty"`
+	Side   string    `json:"side"`
+}
+
+func tradesCmd(env *Env, args []string) error {
+	fs, format := flags(env, "trades")
+	since := fs.Duration("since", 10*time.Minute, "how far back to go")
+	from := fs.String("from", "", "start time, overrides --since")
+	to := fs.String("to", "", "end time, default now")
+	dir := fs.String("dir", env.TicksDir, "tick store directory")
+	symbols, err := parse(env, fs, format, args)
+	if err != nil {
+		return err
+	}
+	if len(symbols) != 1 {
+		return errors.New("trades needs exactly one symbol")
+	}
+	now := time.Now()
+	start, end, err := timeRange(*from, *to, now.Add(-*since), now)
+	if err != nil {
+		return err
+	}
+
+	ticks, err := store.NewTickStore(*dir)
+	if err != nil {
+		return fmt.Errorf("opening tick store: %w", err)
+	}
+	defer ticks.Close()
+	p := pair(symbols[0])
+	trades, err := ticks.Trades(p, start, end)
+	if err != nil {
+		return fmt.Errorf("reading trades: %w", err)
+	}
+
+	out := Output{
+		Header:  []string{"time", "symbol", "price", "qty", "side"},
+		Records: []tradeRecord{},
+	}
+	records := make([]tradeRecord, 0, len(trades))
+	for _, t := range trades {
+		side := "sell"
+		if t.IsBuy {
+			side = "buy"
+		}
+		r := tradeRecord{
+			Time:   time.UnixMilli(t.Unix).UTC(),
+			Symbol: p.Symbol,
+			Price:  t.Price,
+			Qty:    t.Qty,
+			Side:   side,
+		}
+		records = append(records, r)
+		out.Rows = append(out.Rows, []string{
+			r.Time.Format(time.RFC3339Nano), r.Symbol, formatFloat(r.Price), formatFloat(r.Qty), r.Side,
+		})
+	}
+	out.Records = records
+	return out.Write(env.Out, env.Format)
+}
+
+type candleRecord struct {
+	Time   time.Time `json:"time"`
+	Symbol string    `json:"symbol"`
+	Open   float64   `json:"open"`
+	High   float64   `json:"high"`
+	Low    float64   `json:"low"`
+	Close  float64   `json:"close"`
+	Volume float64   `json:"volume"`
+}
+
+// how many bars candles goes back without --from
+const defaultBars = 100
+
+func candlesCmd(env *Env, args []string) error {
+	fs, format := flags(env, "candles")
+	tfName := fs.String("tf", "5m", "timeframe: 1m, 5m, 15m, 30m, 1h or 1d")
+	from := fs.String("from", "", fmt.Sprintf("start time, default %d bars back", defaultBars))
+	to := fs.String("to", "", "end time, default now")
+	source := fs.String("source", "auto", "where bars come from: auto (the tick store, then finnhub for what's missing), store or finnhub")
+	dir := fs.String("dir", env.TicksDir, "tick store directory")
+	symbols, err := parse(env, fs, format, args)
+	if err != nil {
+		return err
+	}
+	if len(symbols) != 1 {
+		return errors.New("candles needs exactly one symbol")
+	}
+	tf, err := candle.ParseTimeframe(*tfName)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	start, end, err := timeRange(*from, *to, now.Add(-tf.Duration*defaultBars), now)
+	if err != nil {
+		return err
+	}
+
+	ticks, err := store.NewTickStore(*dir)
+	if err != nil {
+		return fmt.Errorf("opening tick store: %w", err)
+	}
+	defer ticks.Close()
+	sources, err := env.barSources(*source, ticks)
+	if err != nil {
+		return err
+	}
+
+	p := pair(symbols[0])
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	bars, err := backfill.Candles(ctx, sources, p, tf, start, end)
+	if err != nil {
+		return err
+	}
+
+	out := Output{Header: []string{"time", "symbol", "open", "high", "low", "close", "volume"}}
+	records := make([]candleRecord, 0, len(bars))
+	for _, bar := range bars {
+		r := candleRecord{
+			Time:   time.Unix(bar.Unix, 0).UTC(),
+			Symbol: p.Symbol,
+			Open:   bar.Open,
+			High:   bar.High,
+			Low:    bar.Low,
+			Close:  bar.Close,
+			Volume: bar.Volume,
+		}
+		records = append(records, r)
+		out.Rows = append(out.Rows, []string{
+			r.Time.Format(time.RFC3339), r.Symbol,
+			formatFloat(r.Open), formatFloat(r.High), formatFloat(r.Low), formatFloat(r.Close), formatFloat(r.Volume),
+		})
+	}
+	out.Records = records
+	return out.Write(env.Out, env.Format)
+}
+
+// barSources are the sources of the bars --source asks for, in the order
+// backfill.Candles asks them
+func (env *Env) barSources(source string, ticks *store.TickStore) ([]backfill.Source, error) {
+	var sources []backfill.Source
+	if source == "auto" || source == "store" {
+		src := backfill.StoreSource{Ticks: ticks}
+		// finnhub candles come split-adjusted, our own ticks don't
+		if corp, err := corporate.NewStore("data/corporate"); err == nil {
+			src.Adjust = corp.AdjustCandles
+		}
+		sources = append(sources, src)
+	}
+	if source == "auto" || source == "finnhub" {
+		client, err := env.Client()
+		if err != nil && source == "finnhub" {
+			return nil, err
+		}
+		if err == nil {
+			sources = append(sources, backfill.FinnhubSource{Client: client})
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("unknown source %q, want auto, store or finnhub", source)
+	}
+	return sources, nil
+}
+
+type metricRecord struct {
+	Key      string  `json:"key"`
+	Label    string  `json:"label"`
+	Category string  `json:"category"`
+	Value    float64 `json:"value"`
+}
+
+func metricsCmd(env *Env, args []string) error {
+	fs, format := flags(env, "metrics")
+	symbols, err := parse(env, fs, format, args)
+	if err != nil {
+		return err
+	}
+	if len(symbols) != 1 {
+		return errors.New("metrics needs exactly one symbol")
+	}
+	client, err := env.Client()
+	if err != nil {
+		return err
+	}
+	p := pair(symbols[0])
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	f, err := fundamentals.Fetch(ctx, client, p.Symbol)
+	if err != nil {
+		return fmt.Errorf("fetching metrics for %s: %w", p.Symbol, err)
+	}
+
+	out := Output{Header: []string{"category", "metric", "value"}}
+	records := []metricRecord{}
+	for _, c := range fundamentals.Categories {
+		for _, m := range f.Available(c) {
+			v := f.Value(m.Key)
+			records = append(records, metricRecord{Key: m.Key, Label: m.Label, Category: c.String(), Value: v})
+			if env.Format == CSV {
+				// csv is for machines, keep the raw number
+				out.Rows = append(out.Rows, []string{c.String(), m.Label, strconv.FormatFloat(v, 'f', -1, 64)})
+			} else {
+				out.Rows = append(out.Rows, []string{c.String(), m.Label, m.Format(v)})
+			}
+		}
+	}
+	out.Records = records
+	return out.Write(env.Out, env.Format)
+}
+
+type exchangeRecord struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Open    bool   `json:"open"`
+	Session string `json:"session"`
+	Timer   string `json:"timer"`
+}
+
+type statusRecord struct {
+	// Feed is the running app's /healthz, nil when it isn't running
+	Feed      *metrics.Health  `json:"feed"`
+	Exchanges []exchangeRecord `json:"exchanges"`
+}
+
+func statusCmd(env *Env, args []string) error {
+	fs, format := flags(env, "status")
+	if _, err := parse(env, fs, format, args); err != nil {
+		return err
+	}
+
+	var status statusRecord
+	out := Output{Header: []string{"name", "open", "session", "detail"}}
+
+	feed := []string{"feed", "false", "down", "app not running at " + env.hubURL("")}
+	if res, err := env.hubGet("/healthz"); err == nil {
+		var h metrics.Health
+		// /healthz answers 503 with the same body when the feed is stale
+		if err := json.NewDecoder(res.Body).Decode(&h); err == nil {
+			status.Feed = &h
+			detail := "no message yet"
+			if h.LastMessageAge >= 0 {
+				detail = fmt.Sprintf("last message %.0fs ago", h.LastMessageAge)
+			}
+			feed = []string{"feed", strconv.FormatBool(h.Connected), h.Status, detail}
+		}
+		res.Body.Close()
+	}
+	out.Rows = append(out.Rows, feed)
+
+	now := time.Now()
+	for _, exchange := range calendar.Exchanges {
+		timer, session := hub.MarketTimer(exchange, now)
+		r := exchangeRecord{
+			Code:    exchange.Code,
+			Name:    exchange.Name,
+			Open:    exchange.IsOpen(now),
+			Session: session.String(),
+			Timer:   timer,
+		}
+		status.Exchanges = append(status.Exchanges, r)
+		out.Rows = append(out.Rows, []string{r.Name, strconv.FormatBool(r.Open), r.Session, r.Timer})
+	}
+	out.Records = status
+	return out.Write(env.Out, env.Format)
+}
+
+// timeRange reads --from and --to, empty ones are the defaults
+func timeRange(from, to string, defaultFrom, now time.Time) (time.Time, time.Time, error) {
+	start, end := defaultFrom, now
+	var err error
+	if from != "" {
+		if start, err = parseTime(from, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if to != "" {
+		if end, err = parseTime(to, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s isn't before --to %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/export"
+	"github.com/Scrimzay/stockspider/store"
+)
+
+func init() {
+	commands["export"] = command{"export KIND SYMBOL... [--out FILE]", "trades, quotes or candles as csv, jsonl or parquet", exportCmd}
+}
+
+func exportCmd(env *Env, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	from := fs.String("from", "24h", "start time")
+	to := fs.String("to", "", "end time, default now")
+	tfName := fs.String("tf", "1m", "candle timeframe: 1m, 5m, 15m, 30m, 1h or 1d")
+	formatName := fs.String("format", "", "csv, jsonl or parquet, default from --out's extension or csv")
+	out := fs.String("out", "", "file to write, default stdout")
+	source := fs.String("source", "auto", "where candles come from: auto (the tick store, then finnhub for what's missing), store or finnhub")
+	ticksDir := fs.String("dir", env.TicksDir, "tick store directory")
+	quotesDir := fs.String("quotes-dir", env.QuotesDir, "quote store directory")
+	positional, err := parse(env, fs, nil, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return errors.New("export needs what to export, trades, quotes or candles, and at least one symbol")
+	}
+
+	req := export.Request{Format: export.CSV}
+	if req.Kind, err = export.ParseKind(positional[0]); err != nil {
+		return err
+	}
+	for _, symbol := range positional[1:] {
+		req.Pairs = append(req.Pairs, pair(symbol))
+	}
+	switch {
+	case *formatName != "":
+		req.Format, err = export.ParseFormat(*formatName)
+	case *out != "":
+		req.Format, err = export.FormatOf(*out)
+	}
+	if err != nil {
+		return err
+	}
+	if req.Timeframe, err = candle.ParseTimeframe(*tfName); err != nil {
+		return err
+	}
+	now := time.Now()
+	if req.From, req.To, err = timeRange(*from, *to, now, now); err != nil {
+		return err
+	}
+
+	var sources export.Sources
+	switch req.Kind {
+	case export.Trades, export.Candles:
+		ticks, err := store.NewTickStore(*ticksDir)
+		if err != nil {
+			return fmt.Errorf("opening tick store: %w", err)
+		}
+		defer ticks.Close()
+		sources.Ticks = ticks
+		if req.Kind == export.Candles {
+			if sources.Bars, err = env.barSources(*source, ticks); err != nil {
+				return err
+			}
+		}
+	case export.Quotes:
+		quotes, err := store.NewQuoteStore(*quotesDir)
+		if err != nil {
+			return fmt.Errorf("opening quote store: %w", err)
+		}
+		defer quotes.Close()
+		sources.Quotes = quotes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if *out == "" {
+		_, err := sources.Write(ctx, env.Out, req)
+		return err
+	}
+	n, err := sources.WriteFile(ctx, *out, req)
+	if err != nil {
+		return err
+	}
+	// stdout may be piped somewhere, the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "wrote %d %s of %s to %s\n", n, req.Kind, symbolList(req.Pairs), *out)
+	return nil
+}
+
+func symbolList(pairs []event.Pair) string {
+	s := ""
+	for i, p := range pairs {
+		if i > 0 {
+			s += ", "
+		}
+		s += p.Symbol
+	}
+	return s
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/importer"
+	"github.com/Scrimzay/stockspider/store"
+)
+
+func init() {
+	commands["import"] = command{"import KIND FILE... [--preset] [--symbol]", "trades or candles from csv into the tick store", importCmd}
+}
+
+// mappings is --map, given once per field
+type mappings []string
+
+func (m *mappings) String() string     { return strings.Join(*m, ",") }
+func (m *mappings) Set(v string) error { *m = append(*m, v); return nil }
+
+type importRecord struct {
+	File       string    `json:"file"`
+	Rows       int       `json:"rows"`
+	Imported   int       `json:"imported"`
+	Duplicates int       `json:"duplicates"`
+	Skipped    int       `json:"skipped"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+}
+
+func importCmd(env *Env, args []string) error {
+	fs, format := flags(env, "import")
+	preset := fs.String("preset", "", "file layout: "+strings.Join(importer.PresetNames(), ", "))
+	var maps mappings
+	fs.Var(&maps, "map", "field=column, repeatable, on top of the preset. fields are time, date, clock, symbol, price, qty, side, open, high, low, close and volume")
+	symbol := fs.String("symbol", "", "whose rows they are, when the file has no symbol column")
+	tfName := fs.String("tf", "1d", "timeframe of the bars, candles only")
+	tz := fs.String("tz", "", "timezone of times without an offset, like America/New_York, default UTC or the preset's")
+	timeFormat := fs.String("time-format", "", "Go time layout, unix or unixms, default guesses")
+	comma := fs.String("comma", ",", "field separator")
+	noHeader := fs.Bool("no-header", false, "the first row is data, --map columns are then 0-based indexes")
+	dryRun := fs.Bool("dry-run", false, "read and check the files without storing anything")
+	dir := fs.String("dir", env.TicksDir, "tick store directory")
+	positional, err := parse(env, fs, format, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return errors.New("import needs what to import, trades or candles, and at least one file")
+	}
+
+	cfg := importer.Config{Pair: event.Pair{Exchange: "finnhub"}}
+	if cfg.Kind, err = importer.ParseKind(positional[0]); err != nil {
+		return err
+	}
+	if *preset != "" {
+		if err := importer.Apply(&cfg, *preset); err != nil {
+			return err
+		}
+	}
+	// flags given by hand win over the preset
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "no-header":
+			cfg.NoHeader = *noHeader
+		case "time-format":
+			cfg.TimeFormat = *timeFormat
+		}
+	})
+	for _, m := range maps {
+		field, column, ok := strings.Cut(m, "=")
+		if !ok {
+			return fmt.Errorf("--map %q isn't field=column", m)
+		}
+		if err := cfg.Mapping.Set(field, column); err != nil {
+			return err
+		}
+	}
+	if *symbol != "" {
+		cfg.Pair = pair(*symbol)
+	}
+	if *tz != "" {
+		if cfg.Location, err = time.LoadLocation(*tz); err != nil {
+			return fmt.Errorf("unknown timezone: %w", err)
+		}
+	}
+	if runes := []rune(*comma); len(runes) == 1 {
+		cfg.Comma = runes[0]
+	} else if *comma == `\t` {
+		cfg.Comma = '\t'
+	} else {
+		return fmt.Errorf("--comma %q isn't a single character", *comma)
+	}
+	if cfg.Kind == importer.Candles {
+		if cfg.Timeframe, err = candle.ParseTimeframe(*tfName); err != nil {
+			return err
+		}
+	}
+
+	var ticks *store.TickStore
+	if !*dryRun {
+		if ticks, err = store.NewTickStore(*dir); err != nil {
+			return fmt.Errorf("opening tick store: %w", err)
+		}
+		defer ticks.Close()
+	}
+
+	out := Output{Header: []string{"file", "rows", "imported", "duplicates", "skipped", "from", "to"}}
+	var records []importRecord
+	for _, path := range positional[1:] {
+		r, err := importFile(path, cfg, ticks)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		records = append(records, r)
+		from, to := "-", "-"
+		if !r.From.IsZero() {
+			from, to = r.From.Format(time.RFC3339), r.To.Format(time.RFC3339)
+		}
+		out.Rows = append(out.Rows, []string{
+			r.File, fmt.Sprint(r.Rows), fmt.Sprint(r.Imported), fmt.Sprint(r.Duplicates), fmt.Sprint(r.Skipped), from, to,
+		})
+	}
+	out.Records = records
+	return out.Write(env.Out, env.Format)
+}
+
+// importFile reads path and stores what's new, ticks is nil on a dry run
+func importFile(path string, cfg importer.Config, ticks *store.TickStore) (importRecord, error) {
+	r := importRecord{File: path}
+	f, err := os.Open(path)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+
+	res, err := importer.Read(f, cfg)
+	if err != nil {
+		return r, err
+	}
+	r.Rows, r.Duplicates, r.Skipped = res.Rows, res.Duplicates, res.Skipped
+	// the summary goes to stdout, which may be json, the bad rows don't
+	for _, e := range res.Errors {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, e)
+	}
+	if res.Skipped > len(res.Errors) {
+		fmt.Fprintf(os.Stderr, "%s: and %d more bad rows\n", path, res.Skipped-len(res.Errors))
+	}
+
+	span := func(t time.Time) {
+		if r.From.IsZero() || t.Before(r.From) {
+			r.From = t
+		}
+		if t.After(r.To) {
+			r.To = t
+		}
+	}
+	switch cfg.Kind {
+	case importer.Trades:
+		for i := range res.Trades {
+			// symbols in the file can be display names too
+			res.Trades[i].Pair = pair(res.Trades[i].Pair.Symbol)
+			span(time.UnixMilli(res.Trades[i].Unix).UTC())
+		}
+		r.Imported = len(res.Trades)
+		if ticks != nil {
+			added, err := ticks.AddTrades(res.Trades)
+			r.Duplicates += len(res.Trades) - added
+			r.Imported = added
+			if err != nil {
+				return r, err
+			}
+		}
+
+	case importer.Candles:
+		byPair := make(map[event.Pair][]event.Candle)
+		for _, bar := range res.Bars {
+			bar.Pair = pair(bar.Pair.Symbol)
+			span(time.Unix(bar.Unix, 0).UTC())
+			byPair[bar.Pair] = append(byPair[bar.Pair], bar)
+		}
+		r.Imported = len(res.Bars)
+		if ticks != nil {
+			r.Imported = 0
+			for p, bars := range byPair {
+				added, err := ticks.AddBars(p, cfg.Timeframe.Name, bars)
+				r.Duplicates += len(bars) - added
+				r.Imported += added
+				if err != nil {
+					return r, err
+				}
+			}
+		}
+	}
+	return r, nil
+}
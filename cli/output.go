@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is how a command prints its result
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	CSV   Format = "csv"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Table, JSON, CSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, want table, json or csv", s)
+}
+
+// Output is a command's result. table and csv print Header and Rows,
+// json prints Records so numbers stay numbers
+type Output struct {
+	Header  []string
+	Rows    [][]string
+	Records any
+}
+
+func (o Output) Write(w io.Writer, f Format) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.Records)
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(o.Header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(o.Rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(o.Header, "\t"))
+	for _, row := range o.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
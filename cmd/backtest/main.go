@@ -0,0 +1,197 @@
+// backtest runs a registered strategy over stored or fetched history and
+// writes the report.
+//
+//	go run ./cmd/backtest -strategy sma-cross -symbols BTC/USDT -tf 1h -from 2024-01-01
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/backtest"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/corporate"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/store"
+	"github.com/Scrimzay/stockspider/strategy"
+	"github.com/Scrimzay/stockspider/symbolArray"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		strategyName = flag.String("strategy", "sma-cross", "strategy to run: "+strings.Join(strategy.Names(), ", "))
+		symbolsFlag  = flag.String("symbols", "", "comma separated symbols, display names or full finnhub symbols")
+		tfName       = flag.String("tf", "1h", "bar timeframe")
+		fromFlag     = flag.String("from", "", "start date, 2006-01-02 or RFC3339")
+		toFlag       = flag.String("to", "", "end date, defaults to now")
+		source       = flag.String("source", "store", "store (bars from recorded ticks), ticks (replay recorded ticks) or finnhub")
+		dataDir      = flag.String("data", filepath.Join("data", "ticks"), "tick store directory")
+		corpDir      = flag.String("corporate", filepath.Join("data", "corporate"), "corporate events directory, recorded ticks are split-adjusted with it")
+		cash         = flag.Float64("cash", 100_000, "starting cash")
+		slippage     = flag.Float64("slippage", 2, "slippage in basis points")
+		feeRate      = flag.Float64("fee", 0.0005, "fee as a fraction of notional")
+		minFee       = flag.Float64("minfee", 0.5, "minimum fee per fill")
+		out          = flag.String("out", "backtest-out", "report directory")
+		format       = flag.String("format", "json", "json, csv or both")
+	)
+	flag.Parse()
+
+	if err := run(*strategyName, *symbolsFlag, *tfName, *fromFlag, *toFlag, *source, *dataDir, *corpDir,
+		*cash, *slippage, *feeRate, *minFee, *out, *format); err != nil {
+		fmt.Fprintln(os.Stderr, "backtest:", err)
+		os.Exit(1)
+	}
+}
+
+func parseTime(s string, fallback time.Time) (time.Time, error) {
+	if s == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// fullSymbol maps display names like "ETH" to what finnhub and the tick store use
+func fullSymbol(symbol string) string {
+	if full, ok := symbolArray.Symbols[symbol]; ok {
+		return full
+	}
+	return symbol
+}
+
+func run(strategyName, symbolsFlag, tfName, fromFlag, toFlag, source, dataDir, corpDir string,
+	cash, slippage, feeRate, minFee float64, out, format string) error {
+	s, err := strategy.New(strategyName)
+	if err != nil {
+		return err
+	}
+	tf, err := candle.ParseTimeframe(tfName)
+	if err != nil {
+		return err
+	}
+	to, err := parseTime(toFlag, time.Now())
+	if err != nil {
+		return fmt.Errorf("bad -to: %w", err)
+	}
+	from, err := parseTime(fromFlag, to.AddDate(0, -1, 0))
+	if err != nil {
+		return fmt.Errorf("bad -from: %w", err)
+	}
+	if symbolsFlag == "" {
+		return fmt.Errorf("-symbols is required")
+	}
+
+	var pairs []event.Pair
+	for _, sym := range strings.Split(symbolsFlag, ",") {
+		if sym = strings.TrimSpace(sym); sym != "" {
+			pairs = append(pairs, event.Pair{Exchange: "finnhub", Symbol: fullSymbol(sym)})
+		}
+	}
+
+	cfg := backtest.DefaultConfig()
+	cfg.Timeframe = tf
+	cfg.Paper.StartingCash = cash
+	cfg.Paper.SlippageBps = slippage
+	cfg.Paper.FeeRate = feeRate
+	cfg.Paper.MinFee = minFee
+
+	ctx := context.Background()
+	var report *backtest.Report
+
+	// recorded ticks are raw prices, finnhub's candles are already adjusted
+	corp, err := corporate.NewStore(corpDir)
+	if err != nil {
+		return err
+	}
+
+	switch source {
+	case "ticks":
+		ticks, err := store.NewTickStore(dataDir)
+		if err != nil {
+			return err
+		}
+		var trades []event.StockTrade
+		for _, pair := range pairs {
+			t, err := ticks.Trades(pair, from, to)
+			if err != nil {
+				return err
+			}
+			trades = append(trades, corporate.AdjustTrades(t, corp.Splits(pair.Symbol))...)
+		}
+		report, err = backtest.RunTrades(cfg, s, trades)
+		if err != nil {
+			return err
+		}
+
+	case "store", "finnhub":
+		var src backfill.Source
+		if source == "store" {
+			ticks, err := store.NewTickStore(dataDir)
+			if err != nil {
+				return err
+			}
+			src = backfill.StoreSource{Ticks: ticks, Adjust: corp.AdjustCandles}
+		} else {
+			if err := godotenv.Load(".env"); err != nil {
+				return fmt.Errorf("loading .env: %w", err)
+			}
+			fhCfg := FH.NewConfiguration()
+			fhCfg.AddDefaultHeader("X-Finnhub-Token", os.Getenv("API_KEY"))
+			src = backfill.FinnhubSource{Client: FH.NewAPIClient(fhCfg).DefaultApi}
+		}
+
+		var bars []event.Candle
+		for _, pair := range pairs {
+			b, err := src.Candles(ctx, pair, tf, from, to)
+			if err != nil {
+				return fmt.Errorf("loading %s bars: %w", pair.Symbol, err)
+			}
+			bars = append(bars, b...)
+		}
+		report, err = backtest.RunBars(cfg, s, bars)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown -source %q", source)
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+	if format == "json" || format == "both" {
+		f, err := os.Create(filepath.Join(out, "report.json"))
+		if err != nil {
+			return err
+		}
+		if err := report.WriteJSON(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if format == "csv" || format == "both" {
+		if err := report.WriteCSV(out); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s on %s (%s): return %.2f%%, max drawdown %.2f%%, sharpe %.2f, win rate %.0f%% over %d sell orders\n",
+		report.Strategy, strings.Join(report.Symbols, ","), report.Timeframe,
+		report.Return*100, report.MaxDrawdown*100, report.Sharpe, report.WinRate*100, report.Wins+report.Losses)
+	return nil
+}
@@ -0,0 +1,97 @@
+// ingest is the headless half of a split deployment: the finnhub websocket
+// and news consumers with an exporter that viewers subscribe to over
+// hollywood remote. Run the app with INGEST_ADDR set to the -listen
+// address to view it.
+//
+//	API_KEY=... go run ./cmd/ingest -listen 127.0.0.1:4000
+//	NODE_LISTEN=127.0.0.1:4001 INGEST_ADDR=127.0.0.1:4000 go run .
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Scrimzay/stockspider/actor/consumer/finnhub"
+	"github.com/Scrimzay/stockspider/actor/consumer/news"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/logging"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/node"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	var (
+		listen      = flag.String("listen", os.Getenv("NODE_LISTEN"), "host:port viewers connect to")
+		metricsAddr = flag.String("metrics", ":2113", "address for /metrics and /healthz, off turns it off")
+	)
+	flag.Parse()
+	if err := run(*listen, *metricsAddr); err != nil {
+		fmt.Fprintln(os.Stderr, "ingest:", err)
+		os.Exit(1)
+	}
+}
+
+func run(listen, metricsAddr string) error {
+	if listen == "" {
+		return fmt.Errorf("no -listen address, viewers would have nothing to connect to")
+	}
+	// a missing .env is fine as long as the environment has what it needs
+	godotenv.Load(".env")
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("API_KEY is not set")
+	}
+
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("bad log config: %w", err)
+	}
+	logs, err := logging.New(logCfg)
+	if err != nil {
+		return fmt.Errorf("starting logging: %w", err)
+	}
+	defer logs.Close()
+	log := logs.Component("ingest")
+	slog.SetDefault(logs.Component("actor"))
+
+	e, err := node.NewEngine(node.Config{Listen: listen})
+	if err != nil {
+		return fmt.Errorf("starting actor engine: %w", err)
+	}
+	supervise.Start(e, logs.Component("supervisor"))
+	bus.Start(e)
+	node.StartExporter(e, logs.Component("node"))
+
+	cfg := FH.NewConfiguration()
+	cfg.AddDefaultHeader("X-Finnhub-Token", apiKey)
+	cfg.HTTPClient = &http.Client{Transport: metrics.Transport(nil)}
+	client := FH.NewAPIClient(cfg).DefaultApi
+
+	if metricsAddr != "off" {
+		go func() {
+			if err := metrics.Serve(metricsAddr, time.Minute); err != nil {
+				log.Error("metrics server stopped", "err", err)
+			}
+		}()
+	}
+
+	e.Spawn(finnhub.New(logs.Component("finnhub")), "finnhub", finnhub.Policy.Opts()...)
+	e.Spawn(news.New(client, logs.Component("news")), "news", news.Policy.Opts()...)
+	log.Info("ingest node up", "listen", listen)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	log.Info("shutting down")
+	return nil
+}
@@ -0,0 +1,196 @@
+// tradebench pushes synthetic trades through the same path the app uses:
+// the bus, the bounded trade queue, the candle aggregator, the display
+// tape drained at frame rate and the symbol and stat actors. It reports
+// what got through and what was dropped or conflated on the way.
+//
+//	go run ./cmd/tradebench -symbols 20 -duration 10s
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Scrimzay/stockspider/actor/symbol"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/logging"
+	"github.com/Scrimzay/stockspider/queue"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+func main() {
+	var (
+		symbols  = flag.Int("symbols", 20, "how many symbols trades are spread over")
+		duration = flag.Duration("duration", 10*time.Second, "how long to push trades")
+		rate     = flag.Int("rate", 0, "trades per second to offer, 0 is as fast as possible")
+		capacity = flag.Int("queue", 1<<16, "trade queue capacity")
+		fps      = flag.Int("fps", 60, "how often the tape is drained, like the render loop")
+	)
+	flag.Parse()
+
+	res, err := run(*symbols, *duration, *rate, *capacity, *fps)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tradebench:", err)
+		os.Exit(1)
+	}
+	res.print(*duration)
+}
+
+type result struct {
+	offered   uint64
+	busDrops  uint64
+	processed uint64
+	dropped   uint64
+	conflated uint64
+	displayed uint64
+	elapsed   time.Duration
+}
+
+func (r result) print(duration time.Duration) {
+	secs := r.elapsed.Seconds()
+	fmt.Printf("offered    %10d trades  %10.0f/s\n", r.offered, float64(r.offered)/duration.Seconds())
+	fmt.Printf("processed  %10d trades  %10.0f/s\n", r.processed, float64(r.processed)/secs)
+	fmt.Printf("bus drops  %10d trades  %9.2f%%\n", r.busDrops, percent(r.busDrops, r.offered))
+	fmt.Printf("queue drops%10d trades  %9.2f%%\n", r.dropped, percent(r.dropped, r.offered))
+	// what's left went at the subscriber's mailbox bound
+	sub := r.offered - r.busDrops - r.dropped - r.processed
+	fmt.Printf("sub drops  %10d trades  %9.2f%%\n", sub, percent(sub, r.offered))
+	fmt.Printf("displayed  %10d trades, %d conflated away\n", r.displayed, r.conflated)
+}
+
+func percent(n, of uint64) float64 {
+	if of == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(of)
+}
+
+func run(symbols int, duration time.Duration, rate, capacity, fps int) (result, error) {
+	if symbols < 1 {
+		return result{}, fmt.Errorf("need at least one symbol")
+	}
+	e, err := actor.NewEngine(actor.NewEngineConfig())
+	if err != nil {
+		return result{}, err
+	}
+	log := logging.Discard()
+	bus.Start(e)
+
+	pairs := make([]event.Pair, symbols)
+	for i := range pairs {
+		pairs[i] = event.Pair{Exchange: "finnhub", Symbol: fmt.Sprintf("bench:sym%d", i)}
+		e.Spawn(symbol.New(pairs[i], log), "symbol", actor.WithID(pairs[i].Symbol))
+	}
+
+	trades := queue.New[event.StockTrade]("bench-trades", capacity, queue.DropOldest)
+	bus.Func(e, "bench-trades", "trades.*", func(trade event.StockTrade) {
+		trades.Push(trade)
+	})
+	// subscribing is a message to the broker too, let it land
+	time.Sleep(100 * time.Millisecond)
+	tape := queue.NewConflater[string, event.StockTrade]("bench-tape", 100)
+	candles := candle.NewAggregator(1000)
+
+	var res result
+	var processed, displayed atomic.Uint64
+	var wg sync.WaitGroup
+
+	// the app's trade loop
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		batch := make([]event.StockTrade, 0, 512)
+		for {
+			var ok bool
+			batch, ok = trades.PopBatch(batch[:0], 512)
+			if !ok {
+				return
+			}
+			for _, trade := range batch {
+				candles.AddTrade(trade)
+				tape.Put(trade.Pair.Symbol, trade)
+			}
+			processed.Add(uint64(len(batch)))
+		}
+	}()
+
+	// the render loop
+	stopRender := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		frame := time.NewTicker(time.Second / time.Duration(max(fps, 1)))
+		defer frame.Stop()
+		for {
+			select {
+			case <-frame.C:
+			case <-stopRender:
+				return
+			}
+			for _, ts := range tape.Drain() {
+				displayed.Add(uint64(len(ts)))
+			}
+		}
+	}()
+
+	// the websocket reader
+	rng := rand.New(rand.NewSource(1))
+	price := make([]float64, symbols)
+	for i := range price {
+		price[i] = 100
+	}
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Second / time.Duration(rate)
+	}
+	start := time.Now()
+	next := start
+	for now := start; now.Sub(start) < duration; now = time.Now() {
+		if interval > 0 {
+			if now.Before(next) {
+				time.Sleep(next.Sub(now))
+			}
+			next = next.Add(interval)
+		}
+		i := rng.Intn(symbols)
+		price[i] *= 1 + (rng.Float64()-0.5)/1000
+		trade := event.StockTrade{
+			Pair:  pairs[i],
+			Price: price[i],
+			Qty:   rng.Float64() * 10,
+			IsBuy: rng.Intn(2) == 0,
+			Unix:  now.UnixMilli(),
+		}
+		res.offered++
+		if !bus.Publish(e, bus.Trades(trade.Pair), trade) {
+			res.busDrops++
+		}
+		bus.Publish(e, bus.Stats(trade.Pair), event.Stat{Pair: trade.Pair, MarkPrice: trade.Price, Unix: trade.Unix})
+	}
+
+	// let the bus hand over what it still has before stopping the trade loop
+	for bus.Mailbox.Depth() > 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	trades.Close()
+	close(stopRender)
+	wg.Wait()
+	for _, ts := range tape.Drain() {
+		displayed.Add(uint64(len(ts)))
+	}
+	res.elapsed = time.Since(start)
+
+	res.processed = processed.Load()
+	res.dropped = trades.Dropped()
+	res.conflated = tape.Conflated()
+	res.displayed = displayed.Load()
+	return res, nil
+}
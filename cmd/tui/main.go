@@ -0,0 +1,151 @@
+// tui is stockspider in the terminal, for when the raylib window can't
+// run, over ssh say. It reads the same hub as the GUI so both show the
+// same watchlist, tape, quote, ratings, metrics, market timer and bars.
+//
+//	go run ./cmd/tui
+//	NODE_LISTEN=127.0.0.1:4002 INGEST_ADDR=127.0.0.1:4000 go run ./cmd/tui
+//
+// up/down (or j/k), page up/down, home/end move through the watchlist,
+// enter picks a symbol, 1-6 switch the chart timeframe, q quits.
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Scrimzay/stockspider/actor/consumer/finnhub"
+	"github.com/Scrimzay/stockspider/actor/consumer/news"
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/corporate"
+	"github.com/Scrimzay/stockspider/hub"
+	"github.com/Scrimzay/stockspider/logging"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/node"
+	"github.com/Scrimzay/stockspider/store"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+	"github.com/gdamore/tcell/v2"
+	"github.com/joho/godotenv"
+)
+
+// how often the screen is redrawn without a key being pressed
+const refreshInterval = 250 * time.Millisecond
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "tui:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	// a missing .env is fine as long as the environment has what it needs
+	godotenv.Load(".env")
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		return fmt.Errorf("API_KEY is not set")
+	}
+
+	logCfg, err := logging.ConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("bad log config: %w", err)
+	}
+	// anything on stderr would land in the middle of the screen
+	logCfg.Stderr = false
+	logs, err := logging.New(logCfg)
+	if err != nil {
+		return fmt.Errorf("starting logging: %w", err)
+	}
+	defer logs.Close()
+	slog.SetDefault(logs.Component("actor"))
+
+	nodeCfg := node.ConfigFromEnv()
+	e, err := node.NewEngine(nodeCfg)
+	if err != nil {
+		return fmt.Errorf("starting actor engine: %w", err)
+	}
+	supervise.Start(e, logs.Component("supervisor"))
+	bus.Start(e)
+
+	cfg := FH.NewConfiguration()
+	cfg.AddDefaultHeader("X-Finnhub-Token", apiKey)
+	cfg.HTTPClient = &http.Client{Transport: metrics.Transport(nil)}
+	client := FH.NewAPIClient(cfg).DefaultApi
+
+	h := hub.New(e, client, logs.Component("hub"))
+	ticks, err := store.NewTickStore("data/ticks")
+	if err != nil {
+		return fmt.Errorf("opening tick store: %w", err)
+	}
+	defer ticks.Close()
+	h.Ticks = ticks
+	quotes, err := store.NewQuoteStore("data/quotes")
+	if err != nil {
+		return fmt.Errorf("opening quote store: %w", err)
+	}
+	defer quotes.Close()
+	h.QuoteHistory = quotes
+	corp, err := corporate.NewStore("data/corporate")
+	if err != nil {
+		return fmt.Errorf("opening corporate events store: %w", err)
+	}
+	// finnhub candles come split-adjusted, our own ticks don't
+	h.Backfill = backfill.New(
+		backfill.FinnhubSource{Client: client},
+		backfill.StoreSource{Ticks: ticks, Adjust: corp.AdjustCandles},
+	)
+	h.Start()
+	defer h.Close()
+
+	if nodeCfg.Viewer() {
+		node.StartImporter(e, nodeCfg.Ingest, node.Patterns, logs.Component("node"))
+	} else {
+		e.Spawn(finnhub.New(logs.Component("finnhub")), "finnhub", finnhub.Policy.Opts()...)
+		e.Spawn(news.New(client, logs.Component("news")), "news", news.Policy.Opts()...)
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("opening terminal: %w", err)
+	}
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("opening terminal: %w", err)
+	}
+	defer screen.Fini()
+
+	v := newView(screen, h)
+	events := make(chan tcell.Event)
+	go func() {
+		for {
+			ev := screen.PollEvent()
+			if ev == nil {
+				// Fini was called
+				return
+			}
+			events <- ev
+		}
+	}()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		v.draw(time.Now())
+		select {
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *tcell.EventResize:
+				screen.Sync()
+			case *tcell.EventKey:
+				if !v.key(ev) {
+					return nil
+				}
+			}
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/analyst"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/fundamentals"
+	"github.com/Scrimzay/stockspider/hub"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// column widths, the chart gets what's left in the middle
+const (
+	watchlistWidth = 24
+	sideWidth      = 34
+)
+
+var (
+	styleText  = tcell.StyleDefault
+	styleDim   = tcell.StyleDefault.Foreground(tcell.ColorGray)
+	styleTitle = tcell.StyleDefault.Foreground(tcell.ColorYellow).Bold(true)
+	styleUp    = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	styleDown  = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	styleWarn  = tcell.StyleDefault.Foreground(tcell.ColorOrange)
+)
+
+// the eight heights of a sparkline cell, lowest first
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+type view struct {
+	screen    tcell.Screen
+	hub       *hub.Hub
+	cursor    int // watchlist row under the cursor
+	top       int // first watchlist row on screen
+	timeframe candle.Timeframe
+}
+
+func newView(screen tcell.Screen, h *hub.Hub) *view {
+	return &view{screen: screen, hub: h, timeframe: candle.M1}
+}
+
+// key handles one key press, false means quit
+func (v *view) key(ev *tcell.EventKey) bool {
+	_, height := v.screen.Size()
+	page := max(1, v.listHeight(height)-1)
+
+	switch ev.Key() {
+	case tcell.KeyCtrlC, tcell.KeyEscape:
+		return false
+	case tcell.KeyUp:
+		v.cursor--
+	case tcell.KeyDown:
+		v.cursor++
+	case tcell.KeyPgUp:
+		v.cursor -= page
+	case tcell.KeyPgDn:
+		v.cursor += page
+	case tcell.KeyHome:
+		v.cursor = 0
+	case tcell.KeyEnd:
+		v.cursor = len(v.hub.Order) - 1
+	case tcell.KeyEnter:
+		v.hub.Select(v.hub.Order[v.cursor], v.timeframe)
+	case tcell.KeyRune:
+		switch r := ev.Rune(); {
+		case r == 'q':
+			return false
+		case r == 'k':
+			v.cursor--
+		case r == 'j':
+			v.cursor++
+		case r >= '1' && r < '1'+rune(len(candle.Timeframes)):
+			tf := candle.Timeframes[r-'1']
+			if tf != v.timeframe {
+				v.timeframe = tf
+				go v.hub.BackfillSymbol(v.hub.Selected(), tf)
+			}
+		}
+	}
+	v.cursor = min(max(v.cursor, 0), len(v.hub.Order)-1)
+	return true
+}
+
+func (v *view) listHeight(height int) int {
+	return height - 3
+}
+
+func (v *view) draw(now time.Time) {
+	v.screen.Clear()
+	width, height := v.screen.Size()
+	pair := v.hub.SelectedPair()
+
+	v.drawHeader(pair, now, width)
+	v.drawWatchlist(0, 2, v.listHeight(height))
+
+	sideX := width - sideWidth
+	chartW := sideX - watchlistWidth - 2
+	newsH := min(8, height/4)
+	chartH := height - 3 - newsH
+	v.drawChart(pair, watchlistWidth+1, 2, chartW, chartH)
+	v.drawNews(pair, watchlistWidth+1, 2+chartH, chartW, newsH)
+
+	y := 2
+	y = v.drawQuote(pair, sideX, y)
+	y = v.drawRatings(pair, sideX, y+1)
+	y = v.drawMetrics(pair, sideX, y+1)
+	v.drawTape(pair, sideX, y+1, height-1)
+
+	v.text(0, height-1, width, styleDim, "↑↓ jk pgup pgdn  enter pick  1-6 timeframe  q quit")
+	v.screen.Show()
+}
+
+// drawHeader is the selected symbol, the market timer and the venues
+func (v *view) drawHeader(pair event.Pair, now time.Time, width int) {
+	x := v.text(0, 0, 20, styleTitle, v.hub.Selected())
+
+	exchange := calendar.For(pair)
+	timer, session := hub.MarketTimer(exchange, now)
+	style := styleDown
+	switch session {
+	case calendar.Continuous, calendar.Regular:
+		style = styleUp
+	case calendar.PreMarket, calendar.AfterHours:
+		style = styleWarn
+	}
+	x = v.text(max(x+2, watchlistWidth+1), 0, width, style, timer)
+
+	isOpen, sessionName := v.hub.Status(exchange, now)
+	state := "Closed"
+	if isOpen {
+		state = "Open"
+	}
+	v.text(x+2, 0, width, styleText, fmt.Sprintf("%s: %s  Session: %s", exchange.Code, state, sessionName))
+
+	// one entry per venue, like the GUI's exchange strip
+	x = 0
+	for _, exchange := range v.hub.Exchanges {
+		isOpen, _ := v.hub.Status(exchange, now)
+		style := styleDown
+		if isOpen {
+			style = styleUp
+		}
+		x = v.text(x, 1, width, style, exchange.Code) + 2
+	}
+}
+
+func (v *view) drawWatchlist(x, y, height int) {
+	v.text(x, y, watchlistWidth, styleTitle, "Symbols")
+	y++
+	height--
+	if v.cursor < v.top {
+		v.top = v.cursor
+	}
+	if v.cursor >= v.top+height {
+		v.top = v.cursor - height + 1
+	}
+	selected := v.hub.Selected()
+	for i := v.top; i < len(v.hub.Order) && i < v.top+height; i++ {
+		symbol := v.hub.Order[i]
+		style := styleText
+		if symbol == selected {
+			style = styleUp
+		}
+		if i == v.cursor {
+			style = style.Reverse(true)
+		}
+		v.text(x, y+i-v.top, watchlistWidth-1, style, fmt.Sprintf(" %-*s", watchlistWidth-2, symbol))
+	}
+}
+
+// drawChart draws the bars as unicode candles with a sparkline of the
+// closes under them
+func (v *view) drawChart(pair event.Pair, x, y, width, height int) {
+	v.text(x, y, width, styleTitle, "Chart "+v.timeframe.Name)
+	if v.hub.Selected() == "" || width < 10 || height < 4 {
+		return
+	}
+	bars := v.hub.Candles.Bars(pair, v.timeframe)
+	if len(bars) == 0 {
+		v.text(x, y+1, width, styleDim, "Waiting for bars...")
+		return
+	}
+
+	scaleW := 10
+	cols := width - scaleW
+	rows := height - 3 // title, sparkline and a gap
+	if len(bars) > cols {
+		bars = bars[len(bars)-cols:]
+	}
+	high, low := bars[0].High, bars[0].Low
+	for _, bar := range bars {
+		high = math.Max(high, bar.High)
+		low = math.Min(low, bar.Low)
+	}
+	if high == low {
+		high += 1
+		low -= 1
+	}
+	row := func(price float64) int {
+		return y + 1 + int(math.Round((high-price)/(high-low)*float64(rows-1)))
+	}
+
+	for i, bar := range bars {
+		style := styleUp
+		if bar.Close < bar.Open {
+			style = styleDown
+		}
+		bodyTop, bodyBottom := row(math.Max(bar.Open, bar.Close)), row(math.Min(bar.Open, bar.Close))
+		for r := row(bar.High); r <= row(bar.Low); r++ {
+			ch := '│'
+			if r >= bodyTop && r <= bodyBottom {
+				ch = '┃'
+			}
+			v.screen.SetContent(x+i, r, ch, nil, style)
+		}
+	}
+
+	scaleX := x + cols + 1
+	v.text(scaleX, y+1, scaleW, styleText, fmt.Sprintf("%.2f", high))
+	v.text(scaleX, y+rows, scaleW, styleText, fmt.Sprintf("%.2f", low))
+	last := bars[len(bars)-1]
+	v.text(scaleX, row(last.Close), scaleW, styleTitle, fmt.Sprintf("%.2f", last.Close))
+
+	closes := make([]float64, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+	}
+	v.text(x, y+rows+1, cols, styleText, sparkline(closes))
+}
+
+// sparkline is one cell per value scaled between their low and high
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := len(sparks) / 2
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[i])
+	}
+	return b.String()
+}
+
+func (v *view) drawNews(pair event.Pair, x, y, width, height int) {
+	v.text(x, y, width, styleTitle, "News")
+	items := v.hub.News(pair.Symbol)
+	if v.hub.Selected() == "" {
+		items = v.hub.News("")
+	}
+	for i, item := range items {
+		if i >= height-1 {
+			break
+		}
+		age := time.Since(time.Unix(item.Unix, 0)).Truncate(time.Minute)
+		v.text(x, y+1+i, width, styleText, fmt.Sprintf("%6s %s", shortAge(age), item.Headline))
+	}
+}
+
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func (v *view) drawQuote(pair event.Pair, x, y int) int {
+	v.text(x, y, sideWidth, styleTitle, "Quote")
+	quote, ok := v.hub.Quote(pair.Symbol)
+	if !ok {
+		v.text(x, y+1, sideWidth, styleDim, "No quote yet")
+		return y + 2
+	}
+	change := quote.Current - quote.PrevClose
+	style := styleText
+	if change > 0 {
+		style = styleUp
+	} else if change < 0 {
+		style = styleDown
+	}
+	percent := 0.0
+	if quote.PrevClose != 0 {
+		percent = float64(change / quote.PrevClose * 100)
+	}
+	v.text(x, y+1, sideWidth, style, fmt.Sprintf("%.2f  %+.2f (%+.2f%%)", quote.Current, change, percent))
+	v.text(x, y+2, sideWidth, styleText, fmt.Sprintf("H %.2f  L %.2f", quote.High, quote.Low))
+	v.text(x, y+3, sideWidth, styleText, fmt.Sprintf("O %.2f  PC %.2f", quote.Open, quote.PrevClose))
+	return y + 4
+}
+
+func (v *view) drawRatings(pair event.Pair, x, y int) int {
+	v.text(x, y, sideWidth, styleTitle, "Recommendations")
+	if calendar.For(pair) == calendar.Crypto {
+		v.text(x, y+1, sideWidth, styleDim, "None for crypto")
+		return y + 2
+	}
+	latest, ok := v.hub.Ratings(pair.Symbol).Latest()
+	if !ok {
+		v.text(x, y+1, sideWidth, styleDim, "Loading...")
+		return y + 2
+	}
+	v.text(x, y+1, sideWidth, styleText, fmt.Sprintf("%s  score %.2f", latest.Period, analyst.Score(latest)))
+	counts := []struct {
+		label string
+		n     int64
+		style tcell.Style
+	}{
+		{"SB", latest.StrongBuy, styleUp},
+		{"B", latest.Buy, styleUp},
+		{"H", latest.Hold, styleText},
+		{"S", latest.Sell, styleDown},
+		{"SS", latest.StrongSell, styleDown},
+	}
+	cx := x
+	for _, c := range counts {
+		cx = v.text(cx, y+2, sideWidth, c.style, fmt.Sprintf("%s %d", c.label, c.n)) + 2
+	}
+	return y + 3
+}
+
+// how many metrics of the first category are listed
+const maxMetrics = 5
+
+func (v *view) drawMetrics(pair event.Pair, x, y int) int {
+	v.text(x, y, sideWidth, styleTitle, "Metrics")
+	data := v.hub.Fundamentals(pair.Symbol)
+	if calendar.For(pair) == calendar.Crypto || data == nil {
+		v.text(x, y+1, sideWidth, styleDim, "None loaded")
+		return y + 2
+	}
+	shown := 0
+	for _, c := range fundamentals.Categories {
+		for _, m := range data.Available(c) {
+			if shown == maxMetrics {
+				return y + 1 + shown
+			}
+			v.text(x, y+1+shown, sideWidth, styleText, fmt.Sprintf("%-20s %s", m.Label, m.Format(data.Value(m.Key))))
+			shown++
+		}
+	}
+	return y + 1 + shown
+}
+
+// drawTape is the newest trades first down to bottom
+func (v *view) drawTape(pair event.Pair, x, y, bottom int) {
+	v.text(x, y, sideWidth, styleTitle, "Trades")
+	trades := v.hub.Trades(pair.Symbol)
+	row := y + 1
+	for i := len(trades) - 1; i >= 0 && row < bottom; i-- {
+		trade := trades[i]
+		style := styleUp
+		if !trade.IsBuy {
+			style = styleDown
+		}
+		v.text(x, row, sideWidth, style, fmt.Sprintf("%.4f @ %.2f", trade.Qty, trade.Price))
+		row++
+	}
+}
+
+// text writes s at x, y cut to width cells and returns the column after it
+func (v *view) text(x, y, width int, style tcell.Style, s string) int {
+	end := x + width
+	for _, r := range s {
+		if x >= end {
+			break
+		}
+		v.screen.SetContent(x, y, r, nil, style)
+		x++
+	}
+	return x
+}
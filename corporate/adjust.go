@@ -0,0 +1,66 @@
+package corporate
+
+import (
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// Ratio is how many shares one share turns into, 4 for a 4-for-1 split
+// and 0.1 for a 1-for-10 reverse split
+func Ratio(s event.Split) float64 {
+	if s.From <= 0 || s.To <= 0 {
+		return 1
+	}
+	return s.To / s.From
+}
+
+// Factor is what a price from at has to be divided by to line up with
+// prices after every split in splits
+func Factor(splits []event.Split, at time.Time) float64 {
+	f := 1.0
+	for _, s := range splits {
+		if at.Unix() < s.Unix {
+			f *= Ratio(s)
+		}
+	}
+	return f
+}
+
+// AdjustCandles returns bars split-adjusted: prices before a split are
+// divided by its ratio and volume multiplied by it. bars is left alone
+func AdjustCandles(bars []event.Candle, splits []event.Split) []event.Candle {
+	if len(splits) == 0 {
+		return bars
+	}
+	adjusted := make([]event.Candle, len(bars))
+	for i, bar := range bars {
+		f := Factor(splits, time.Unix(bar.Unix, 0))
+		if f != 1 {
+			bar.Open /= f
+			bar.High /= f
+			bar.Low /= f
+			bar.Close /= f
+			bar.Volume *= f
+		}
+		adjusted[i] = bar
+	}
+	return adjusted
+}
+
+// AdjustTrades is AdjustCandles for raw trades
+func AdjustTrades(trades []event.StockTrade, splits []event.Split) []event.StockTrade {
+	if len(splits) == 0 {
+		return trades
+	}
+	adjusted := make([]event.StockTrade, len(trades))
+	for i, trade := range trades {
+		f := Factor(splits, time.UnixMilli(trade.Unix))
+		if f != 1 {
+			trade.Price /= f
+			trade.Qty *= f
+		}
+		adjusted[i] = trade
+	}
+	return adjusted
+}
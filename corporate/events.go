@@ -0,0 +1,159 @@
+// Package corporate tracks earnings, dividends and splits for the symbols
+// stockspider watches, keeps them on disk and adjusts prices for splits.
+package corporate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// Events is every corporate event known for one symbol, oldest first
+type Events struct {
+	Symbol    string // full finnhub symbol
+	Earnings  []event.Earnings
+	Dividends []event.Dividend
+	Splits    []event.Split
+	Updated   time.Time
+}
+
+// Stale reports whether e is missing or older than ttl
+func (e *Events) Stale(now time.Time, ttl time.Duration) bool {
+	return e == nil || now.Sub(e.Updated) > ttl
+}
+
+// Merge folds fresh into e. Events on the same key are replaced, older
+// events fresh no longer covers are kept so history builds up over time.
+func (e *Events) Merge(fresh *Events) {
+	e.Earnings = mergeBy(e.Earnings, fresh.Earnings, func(x event.Earnings) string {
+		if x.Year != 0 {
+			return fmt.Sprintf("%d-Q%d", x.Year, x.Quarter)
+		}
+		return fmt.Sprint(x.Unix)
+	})
+	e.Dividends = mergeBy(e.Dividends, fresh.Dividends, func(x event.Dividend) string {
+		return fmt.Sprint(x.Unix)
+	})
+	e.Splits = mergeBy(e.Splits, fresh.Splits, func(x event.Split) string {
+		return fmt.Sprint(x.Unix)
+	})
+	sort.SliceStable(e.Earnings, func(i, j int) bool { return e.Earnings[i].Unix < e.Earnings[j].Unix })
+	sort.SliceStable(e.Dividends, func(i, j int) bool { return e.Dividends[i].Unix < e.Dividends[j].Unix })
+	sort.SliceStable(e.Splits, func(i, j int) bool { return e.Splits[i].Unix < e.Splits[j].Unix })
+	if fresh.Updated.After(e.Updated) {
+		e.Updated = fresh.Updated
+	}
+}
+
+func mergeBy[T any](old, fresh []T, key func(T) string) []T {
+	index := make(map[string]int, len(old))
+	merged := make([]T, len(old))
+	copy(merged, old)
+	for i, x := range merged {
+		index[key(x)] = i
+	}
+	for _, x := range fresh {
+		if i, ok := index[key(x)]; ok {
+			merged[i] = x
+			continue
+		}
+		index[key(x)] = len(merged)
+		merged = append(merged, x)
+	}
+	return merged
+}
+
+// Kind is the type of a calendar item
+type Kind int
+
+const (
+	EarningsKind Kind = iota
+	DividendKind
+	SplitKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case EarningsKind:
+		return "Earnings"
+	case DividendKind:
+		return "Dividend"
+	case SplitKind:
+		return "Split"
+	}
+	return "Unknown"
+}
+
+// Item is one event flattened for the calendar and chart markers
+type Item struct {
+	Symbol string
+	Kind   Kind
+	Unix   int64
+	Label  string
+}
+
+func (i Item) Time() time.Time {
+	return time.Unix(i.Unix, 0).UTC()
+}
+
+// Items lists every event of e, oldest first
+func (e *Events) Items() []Item {
+	var items []Item
+	for _, x := range e.Earnings {
+		items = append(items, Item{Symbol: e.Symbol, Kind: EarningsKind, Unix: x.Unix, Label: describeEarnings(x)})
+	}
+	for _, x := range e.Dividends {
+		label := fmt.Sprintf("Dividend %.4g %s", x.Amount, x.Currency)
+		if x.PayUnix != 0 {
+			label += ", pays " + time.Unix(x.PayUnix, 0).UTC().Format(dayLayout)
+		}
+		items = append(items, Item{Symbol: e.Symbol, Kind: DividendKind, Unix: x.Unix, Label: label})
+	}
+	for _, x := range e.Splits {
+		label := fmt.Sprintf("Split %g:%g", x.To, x.From)
+		items = append(items, Item{Symbol: e.Symbol, Kind: SplitKind, Unix: x.Unix, Label: label})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Unix < items[j].Unix })
+	return items
+}
+
+func describeEarnings(x event.Earnings) string {
+	hour := ""
+	switch strings.ToLower(x.Hour) {
+	case "bmo":
+		hour = " pre"
+	case "amc":
+		hour = " post"
+	}
+	period := fmt.Sprintf("Q%d %d", x.Quarter, x.Year)
+	if !x.Reported {
+		return fmt.Sprintf("%s%s EPS est %.2f", period, hour, x.EPSEstimate)
+	}
+	return fmt.Sprintf("%s EPS %.2f vs %.2f (%+.1f%%)", period, x.EPSActual, x.EPSEstimate, x.SurprisePercent)
+}
+
+// Upcoming lists the events of every symbol in events from the start of
+// now's day to within after it, soonest first
+func Upcoming(events []*Events, now time.Time, within time.Duration) []Item {
+	now = now.UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := now.Add(within)
+
+	var items []Item
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		for _, item := range e.Items() {
+			t := item.Time()
+			if !t.Before(from) && t.Before(to) {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Unix < items[j].Unix })
+	return items
+}
@@ -0,0 +1,121 @@
+package corporate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+// how far around now events get fetched. splits go back further since
+// stored candles that old still need adjusting
+const (
+	historyWindow = 2 * 365 * 24 * time.Hour
+	splitWindow   = 10 * 365 * 24 * time.Hour
+	aheadWindow   = 120 * 24 * time.Hour
+)
+
+// Fetch pulls the earnings calendar, reported surprises, dividends and
+// splits of symbol. finnhub gates some of these behind paid plans, so
+// whatever did come back is returned along with the errors of what didn't
+func Fetch(ctx context.Context, client *FH.DefaultApiService, symbol string, now time.Time) (*Events, error) {
+	pair := event.Pair{Exchange: "finnhub", Symbol: symbol}
+	e := &Events{Symbol: symbol, Updated: now}
+	from := now.Add(-historyWindow).Format(dayLayout)
+	to := now.Add(aheadWindow).Format(dayLayout)
+	var errs []error
+
+	cal, _, err := client.EarningsCalendar(ctx).Symbol(symbol).From(from).To(to).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("earnings calendar for %s: %w", symbol, err))
+	}
+	for _, r := range cal.GetEarningsCalendar() {
+		unix, ok := parseDay(r.GetDate())
+		if !ok {
+			continue
+		}
+		_, reported := r.GetEpsActualOk()
+		e.Earnings = append(e.Earnings, event.Earnings{
+			Pair:            pair,
+			Unix:            unix,
+			Hour:            r.GetHour(),
+			Year:            r.GetYear(),
+			Quarter:         r.GetQuarter(),
+			EPSEstimate:     float64(r.GetEpsEstimate()),
+			EPSActual:       float64(r.GetEpsActual()),
+			RevenueEstimate: float64(r.GetRevenueEstimate()),
+			RevenueActual:   float64(r.GetRevenueActual()),
+			Reported:        reported,
+		})
+	}
+
+	// the surprise only comes from the company earnings endpoint
+	results, _, err := client.CompanyEarnings(ctx).Symbol(symbol).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("earnings surprises for %s: %w", symbol, err))
+	}
+	for _, r := range results {
+		for i := range e.Earnings {
+			x := &e.Earnings[i]
+			if x.Year == r.GetYear() && x.Quarter == r.GetQuarter() {
+				x.Surprise = float64(r.GetSurprise())
+				x.SurprisePercent = float64(r.GetSurprisePercent())
+				if _, ok := r.GetActualOk(); ok {
+					x.EPSActual = float64(r.GetActual())
+					x.Reported = true
+				}
+			}
+		}
+	}
+
+	divs, _, err := client.StockDividends(ctx).Symbol(symbol).From(from).To(to).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("dividends for %s: %w", symbol, err))
+	}
+	for _, r := range divs {
+		unix, ok := parseDay(r.GetDate())
+		if !ok {
+			continue
+		}
+		pay, _ := parseDay(r.GetPayDate())
+		e.Dividends = append(e.Dividends, event.Dividend{
+			Pair:     pair,
+			Unix:     unix,
+			PayUnix:  pay,
+			Amount:   float64(r.GetAmount()),
+			Currency: r.GetCurrency(),
+		})
+	}
+
+	splits, _, err := client.StockSplits(ctx).Symbol(symbol).From(now.Add(-splitWindow).Format(dayLayout)).To(to).Execute()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("splits for %s: %w", symbol, err))
+	}
+	for _, r := range splits {
+		unix, ok := parseDay(r.GetDate())
+		if !ok {
+			continue
+		}
+		e.Splits = append(e.Splits, event.Split{
+			Pair: pair,
+			Unix: unix,
+			From: float64(r.GetFromFactor()),
+			To:   float64(r.GetToFactor()),
+		})
+	}
+
+	return e, errors.Join(errs...)
+}
+
+// parseDay turns finnhub's "2006-01-02" into midnight utc
+func parseDay(s string) (int64, bool) {
+	t, err := time.Parse(dayLayout, s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Unix(), true
+}
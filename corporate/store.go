@@ -0,0 +1,128 @@
+package corporate
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+const dayLayout = "2006-01-02"
+
+// Store keeps each symbol's events in <dir>/<symbol>.json and caches them
+// in memory, it is safe to use from several goroutines
+type Store struct {
+	dir string
+
+	mu     sync.Mutex
+	events map[string]*Events // keyed by uppercase full symbol, nil when there's no file
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{
+		dir:    dir,
+		events: make(map[string]*Events),
+	}, nil
+}
+
+func (s *Store) path(symbol string) string {
+	name := strings.NewReplacer(":", "_", "/", "_", "\\", "_", "^", "_").Replace(strings.ToLower(symbol))
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Get returns the events of symbol, nil when nothing was ever stored for it.
+// callers must not modify what they get back
+func (s *Store) Get(symbol string) (*Events, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(symbol)
+}
+
+func (s *Store) get(symbol string) (*Events, error) {
+	key := strings.ToUpper(symbol)
+	if e, ok := s.events[key]; ok {
+		return e, nil
+	}
+
+	b, err := os.ReadFile(s.path(symbol))
+	if errors.Is(err, fs.ErrNotExist) {
+		// remembered too, the chart asks every frame. Put replaces it
+		s.events[key] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e Events
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	s.events[key] = &e
+	return &e, nil
+}
+
+// Put merges fresh into what is stored for its symbol and writes it out.
+// it returns the splits that weren't known before
+func (s *Store) Put(fresh *Events) ([]event.Split, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.get(fresh.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	merged := &Events{Symbol: fresh.Symbol}
+	known := make(map[int64]bool)
+	if old != nil {
+		merged.Merge(old)
+		for _, split := range old.Splits {
+			known[split.Unix] = true
+		}
+	}
+	merged.Merge(fresh)
+
+	var added []event.Split
+	for _, split := range merged.Splits {
+		if !known[split.Unix] {
+			added = append(added, split)
+		}
+	}
+
+	b, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	tmp := s.path(fresh.Symbol) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, s.path(fresh.Symbol)); err != nil {
+		return nil, err
+	}
+	s.events[strings.ToUpper(fresh.Symbol)] = merged
+	return added, nil
+}
+
+// Splits returns the known splits of symbol
+func (s *Store) Splits(symbol string) []event.Split {
+	e, err := s.Get(symbol)
+	if err != nil || e == nil {
+		return nil
+	}
+	return e.Splits
+}
+
+// AdjustCandles split-adjusts bars of pair with the stored splits, it fits
+// backfill.StoreSource.Adjust
+func (s *Store) AdjustCandles(pair event.Pair, bars []event.Candle) []event.Candle {
+	return AdjustCandles(bars, s.Splits(pair.Symbol))
+}
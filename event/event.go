@@ -7,12 +7,18 @@ type Pair struct{
 
 type MarketStatus struct {
 	Pair Pair
+	Exchange string // finnhub exchange code, e.g. "US", "L", "TO"
 	IsOpen bool
 	Session string
+	Holiday string
+	Timezone string
+	Unix int64
 }
 
+// RecommendationTrends is the analyst rating count for one month
 type RecommendationTrends struct {
 	Pair Pair
+	Period string // "2006-01-02", the first of the month being rated
 	Buy int64
 	Hold int64
 	Sell int64
@@ -20,12 +26,45 @@ type RecommendationTrends struct {
 	StrongSell int64
 }
 
+// PriceTarget is the analyst consensus price target
+type PriceTarget struct {
+	Pair Pair
+	High float64
+	Low float64
+	Mean float64
+	Median float64
+	Analysts int64
+	Unix int64 // when finnhub last updated it
+}
+
+// RatingChange is one analyst upgrade or downgrade
+type RatingChange struct {
+	Pair Pair
+	Unix int64
+	Company string
+	FromGrade string
+	ToGrade string
+	Action string // "up", "down", "main", "init", "reit"
+}
+
 type StockTrade struct {
 	Pair Pair
 	Price float64
 	Qty float64
 	IsBuy bool
+	Unix int64 // unix millis, as finnhub sends it
+}
+
+// Candle is one OHLCV bar, Unix is the bucket open time in seconds
+type Candle struct {
+	Pair Pair
+	Timeframe string // "1m", "5m", "1h", "1d"...
 	Unix int64
+	Open float64
+	High float64
+	Low float64
+	Close float64
+	Volume float64
 }
 
 type Quote struct {
@@ -51,4 +90,80 @@ type Stat struct {
 	MarkPrice float64
 	Funding float64
 	Unix int64
-}
\ No newline at end of file
+}
+// News is one headline, Pair.Symbol is the full finnhub symbol it was
+// fetched for or "" for general market news. Unix is in seconds
+type News struct {
+	Pair Pair
+	ID int64
+	Category string
+	Headline string
+	Summary string
+	Source string
+	URL string
+	Image string
+	Related string
+	Unix int64
+}
+
+// Earnings is one earnings release, Unix is the report day at midnight UTC.
+// actuals and the surprise stay zero until it has been reported
+type Earnings struct {
+	Pair Pair
+	Unix int64
+	Hour string // "bmo" before open, "amc" after close, "dmh" during hours
+	Year int64
+	Quarter int64
+	EPSEstimate float64
+	EPSActual float64
+	Surprise float64
+	SurprisePercent float64
+	RevenueEstimate float64
+	RevenueActual float64
+	Reported bool
+}
+
+// Dividend is one dividend, Unix is the ex-date at midnight UTC
+type Dividend struct {
+	Pair Pair
+	Unix int64
+	PayUnix int64
+	Amount float64
+	Currency string
+}
+
+// Split is one stock split, Unix is the day it takes effect at midnight UTC.
+// a 4-for-1 split has From 1 and To 4
+type Split struct {
+	Pair Pair
+	Unix int64
+	From float64
+	To float64
+}
+
+// Selected is the user picking a symbol, Pair.Symbol is the full finnhub
+// symbol and "" when nothing is picked
+type Selected struct {
+	Pair Pair
+}
+
+// Watched are the symbols on screen besides the selected one, in pinned
+// panels and grid tiles. the websocket streams their trades too
+type Watched struct {
+	Pairs []Pair
+}
+
+// BookLevel is the resting quantity at one price
+type BookLevel struct {
+	Price float64
+	Qty float64
+}
+
+// Book is an order book snapshot, bids best (highest) first and asks
+// best (lowest) first. Unix is in millis like StockTrade
+type Book struct {
+	Pair Pair
+	Bids []BookLevel
+	Asks []BookLevel
+	Unix int64
+}
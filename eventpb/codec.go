@@ -0,0 +1,203 @@
+package eventpb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Version is the wire format version written into every Envelope. it only
+// changes for what the compatibility rules in event.proto don't allow
+const Version = 1
+
+// the largest encoded event a Decoder accepts, a bad length prefix
+// shouldn't get to allocate gigabytes
+const maxFrame = 16 << 20
+
+// Wrap puts an event value in an Envelope of the current version
+func Wrap(v any) (*Envelope, error) {
+	m, ok := FromEvent(v)
+	if !ok {
+		return nil, fmt.Errorf("%T is not an event", v)
+	}
+	env := &Envelope{Version: Version}
+	name := m.ProtoReflect().Descriptor().FullName()
+	oneof := env.ProtoReflect().Descriptor().Oneofs().ByName("event")
+	for i := 0; i < oneof.Fields().Len(); i++ {
+		fd := oneof.Fields().Get(i)
+		if fd.Message().FullName() == name {
+			env.ProtoReflect().Set(fd, protoreflect.ValueOfMessage(m.ProtoReflect()))
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no envelope field", name)
+}
+
+// Unwrap is the event value in env
+func Unwrap(env *Envelope) (any, error) {
+	if env.GetVersion() == 0 || env.GetVersion() > Version {
+		return nil, fmt.Errorf("wire format version %d, this build reads up to %d", env.GetVersion(), Version)
+	}
+	r := env.ProtoReflect()
+	fd := r.WhichOneof(r.Descriptor().Oneofs().ByName("event"))
+	if fd == nil {
+		// a newer writer's event this build doesn't know about
+		return nil, ErrUnknownEvent
+	}
+	v, ok := ToEvent(r.Get(fd).Message().Interface())
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	return v, nil
+}
+
+// ErrUnknownEvent is an envelope holding no event this build knows, a
+// reader can skip it and carry on
+var ErrUnknownEvent = errors.New("unknown event type")
+
+// Codec turns event values into bytes and back. strings have to be valid
+// UTF-8, protobuf refuses anything else
+type Codec interface {
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(b []byte) (any, error)
+}
+
+var (
+	// Proto is the compact binary form, for the network and recordings
+	Proto Codec = protoCodec{}
+	// JSON is the readable form, one object per event with the field
+	// names from event.proto
+	JSON Codec = jsonCodec{}
+)
+
+// CodecFor is the codec called name, "proto" or "json"
+func CodecFor(name string) (Codec, error) {
+	switch name {
+	case "proto", "protobuf":
+		return Proto, nil
+	case "json":
+		return JSON, nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Name() string { return "proto" }
+
+func (protoCodec) Marshal(v any) ([]byte, error) {
+	env, err := Wrap(v)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(env)
+}
+
+func (protoCodec) Unmarshal(b []byte) (any, error) {
+	var env Envelope
+	if err := proto.Unmarshal(b, &env); err != nil {
+		return nil, err
+	}
+	return Unwrap(&env)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	env, err := Wrap(v)
+	if err != nil {
+		return nil, err
+	}
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(env)
+}
+
+func (jsonCodec) Unmarshal(b []byte) (any, error) {
+	var env Envelope
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(b, &env); err != nil {
+		return nil, err
+	}
+	return Unwrap(&env)
+}
+
+// Encoder writes a stream of events, JSON one per line and proto each
+// behind its uvarint length
+type Encoder struct {
+	w     io.Writer
+	codec Codec
+	buf   []byte
+}
+
+func NewEncoder(w io.Writer, codec Codec) *Encoder {
+	return &Encoder{w: w, codec: codec}
+}
+
+func (e *Encoder) Encode(v any) error {
+	b, err := e.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf = e.buf[:0]
+	if e.codec == JSON {
+		e.buf = append(append(e.buf, b...), '\n')
+	} else {
+		e.buf = append(binary.AppendUvarint(e.buf, uint64(len(b))), b...)
+	}
+	_, err = e.w.Write(e.buf)
+	return err
+}
+
+// Decoder reads what an Encoder with the same codec wrote
+type Decoder struct {
+	r     *bufio.Reader
+	codec Codec
+	buf   []byte
+}
+
+func NewDecoder(r io.Reader, codec Codec) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), codec: codec}
+}
+
+// Decode is the next event, io.EOF at the end of the stream. a frame that
+// doesn't decode is an error but the stream can be read on past it,
+// ErrUnknownEvent included
+func (d *Decoder) Decode() (any, error) {
+	if d.codec == JSON {
+		line, err := d.r.ReadBytes('\n')
+		if err == io.EOF && len(line) > 0 {
+			// a last line without its newline
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return d.codec.Unmarshal(line)
+	}
+
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxFrame {
+		return nil, fmt.Errorf("frame of %d bytes is over the %d limit", n, maxFrame)
+	}
+	if cap(d.buf) < int(n) {
+		d.buf = make([]byte, n)
+	}
+	d.buf = d.buf[:n]
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return d.codec.Unmarshal(d.buf)
+}
@@ -0,0 +1,427 @@
+package eventpb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+var codecs = []Codec{Proto, JSON}
+
+// roundTrip sends v through FromEvent/ToEvent, Wrap/Unwrap and both codecs
+// and fails on anything that comes back different. strs are v's strings,
+// protobuf only carries valid UTF-8 so inputs with anything else are
+// skipped
+func roundTrip(t *testing.T, v any, strs ...string) {
+	t.Helper()
+	for _, s := range strs {
+		if !utf8.ValidString(s) {
+			t.Skip("not UTF-8")
+		}
+	}
+
+	m, ok := FromEvent(v)
+	if !ok {
+		t.Fatalf("FromEvent(%T) not ok", v)
+	}
+	back, ok := ToEvent(m)
+	if !ok {
+		t.Fatalf("ToEvent(%T) not ok", m)
+	}
+	same(t, "ToEvent", v, back)
+
+	env, err := Wrap(v)
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	back, err = Unwrap(env)
+	if err != nil {
+		t.Fatalf("Unwrap: %v", err)
+	}
+	same(t, "Unwrap", v, back)
+
+	for _, codec := range codecs {
+		b, err := codec.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s Marshal: %v", codec.Name(), err)
+		}
+		back, err := codec.Unmarshal(b)
+		if err != nil {
+			t.Fatalf("%s Unmarshal %q: %v", codec.Name(), b, err)
+		}
+		same(t, codec.Name(), v, back)
+	}
+}
+
+// same compares by %#v, which unlike == has NaN equal to itself and
+// tells -0 from 0
+func same(t *testing.T, step string, want, got any) {
+	t.Helper()
+	if w, g := fmt.Sprintf("%#v", want), fmt.Sprintf("%#v", got); w != g {
+		t.Fatalf("%s:\nwant %s\n got %s", step, w, g)
+	}
+}
+
+func FuzzMarketStatus(f *testing.F) {
+	f.Add("finnhub", "AAPL", "US", true, "regular", "", "America/New_York", int64(1700000000))
+	f.Add("", "", "", false, "", "Christmas Day", "", int64(-1))
+	f.Fuzz(func(t *testing.T, exchange, symbol, code string, open bool, session, holiday, tz string, unix int64) {
+		v := event.MarketStatus{
+			Pair:     event.Pair{Exchange: exchange, Symbol: symbol},
+			Exchange: code,
+			IsOpen:   open,
+			Session:  session,
+			Holiday:  holiday,
+			Timezone: tz,
+			Unix:     unix,
+		}
+		roundTrip(t, v, exchange, symbol, code, session, holiday, tz)
+	})
+}
+
+func FuzzRecommendationTrends(f *testing.F) {
+	f.Add("finnhub", "AAPL", "2024-01-01", int64(10), int64(5), int64(1), int64(8), int64(0))
+	f.Fuzz(func(t *testing.T, exchange, symbol, period string, buy, hold, sell, strongBuy, strongSell int64) {
+		v := event.RecommendationTrends{
+			Pair:       event.Pair{Exchange: exchange, Symbol: symbol},
+			Period:     period,
+			Buy:        buy,
+			Hold:       hold,
+			Sell:       sell,
+			StrongBuy:  strongBuy,
+			StrongSell: strongSell,
+		}
+		roundTrip(t, v, exchange, symbol, period)
+	})
+}
+
+func FuzzPriceTarget(f *testing.F) {
+	f.Add("finnhub", "AAPL", 250.5, 150.0, 200.25, 199.0, int64(40), int64(1700000000))
+	f.Add("", "", math.NaN(), math.Inf(1), math.Inf(-1), math.Copysign(0, -1), int64(0), int64(0))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, high, low, mean, median float64, analysts, unix int64) {
+		v := event.PriceTarget{
+			Pair:     event.Pair{Exchange: exchange, Symbol: symbol},
+			High:     high,
+			Low:      low,
+			Mean:     mean,
+			Median:   median,
+			Analysts: analysts,
+			Unix:     unix,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzRatingChange(f *testing.F) {
+	f.Add("finnhub", "AAPL", int64(1700000000), "Morgan Stanley", "Hold", "Buy", "up")
+	f.Fuzz(func(t *testing.T, exchange, symbol string, unix int64, company, from, to, action string) {
+		v := event.RatingChange{
+			Pair:      event.Pair{Exchange: exchange, Symbol: symbol},
+			Unix:      unix,
+			Company:   company,
+			FromGrade: from,
+			ToGrade:   to,
+			Action:    action,
+		}
+		roundTrip(t, v, exchange, symbol, company, from, to, action)
+	})
+}
+
+func FuzzStockTrade(f *testing.F) {
+	f.Add("finnhub", "BINANCE:BTCUSDT", 43000.12, 0.015, true, int64(1700000000123))
+	f.Add("", "", math.NaN(), math.Inf(-1), false, int64(math.MinInt64))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, price, qty float64, buy bool, unix int64) {
+		v := event.StockTrade{
+			Pair:  event.Pair{Exchange: exchange, Symbol: symbol},
+			Price: price,
+			Qty:   qty,
+			IsBuy: buy,
+			Unix:  unix,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzCandle(f *testing.F) {
+	f.Add("finnhub", "AAPL", "1m", int64(1700000000), 1.0, 2.0, 0.5, 1.5, 1000.0)
+	f.Fuzz(func(t *testing.T, exchange, symbol, tf string, unix int64, open, high, low, close, volume float64) {
+		v := event.Candle{
+			Pair:      event.Pair{Exchange: exchange, Symbol: symbol},
+			Timeframe: tf,
+			Unix:      unix,
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+		}
+		roundTrip(t, v, exchange, symbol, tf)
+	})
+}
+
+func FuzzQuote(f *testing.F) {
+	f.Add("finnhub", "AAPL", float32(190.5), float32(191), float32(188.25), float32(189), float32(187.9), int64(1700000000))
+	f.Add("", "", float32(math.NaN()), float32(math.Inf(1)), float32(math.SmallestNonzeroFloat32), float32(math.MaxFloat32), float32(0), int64(0))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, current, high, low, open, prevClose float32, unix int64) {
+		v := event.Quote{
+			Pair:      event.Pair{Exchange: exchange, Symbol: symbol},
+			Current:   current,
+			High:      high,
+			Low:       low,
+			Open:      open,
+			PrevClose: prevClose,
+			Unix:      unix,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzSymbolMetric(f *testing.F) {
+	f.Add("finnhub", "AAPL", 55e6, 199.62, 164.08, 0.25)
+	f.Fuzz(func(t *testing.T, exchange, symbol string, volume, high, low, ret float64) {
+		v := event.SymbolMetric{
+			Pair:                         event.Pair{Exchange: exchange, Symbol: symbol},
+			TenDayAverageTradingVolume:   volume,
+			FiftyTwoWeekHigh:             high,
+			FiftyTwoWeekLow:              low,
+			FiftyTwoWeekPriceReturnDaily: ret,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzStat(f *testing.F) {
+	f.Add("finnhub", "BINANCE:BTCUSDT", 43000.5, 0.0001, int64(1700000000))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, mark, funding float64, unix int64) {
+		v := event.Stat{
+			Pair:      event.Pair{Exchange: exchange, Symbol: symbol},
+			MarkPrice: mark,
+			Funding:   funding,
+			Unix:      unix,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzNews(f *testing.F) {
+	f.Add("finnhub", "AAPL", int64(123), "company", "Apple does a thing", "a summary, with \"quotes\"\nand lines", "Reuters", "https://example.com/a?b=c&d", "", "AAPL,MSFT", int64(1700000000))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, id int64, category, headline, summary, source, url, image, related string, unix int64) {
+		v := event.News{
+			Pair:     event.Pair{Exchange: exchange, Symbol: symbol},
+			ID:       id,
+			Category: category,
+			Headline: headline,
+			Summary:  summary,
+			Source:   source,
+			URL:      url,
+			Image:    image,
+			Related:  related,
+			Unix:     unix,
+		}
+		roundTrip(t, v, exchange, symbol, category, headline, summary, source, url, image, related)
+	})
+}
+
+func FuzzEarnings(f *testing.F) {
+	f.Add("finnhub", "AAPL", int64(1700000000), "amc", int64(2024), int64(4), 2.1, 2.18, 0.08, 3.8, 94.5e9, 95.1e9, true)
+	f.Fuzz(func(t *testing.T, exchange, symbol string, unix int64, hour string, year, quarter int64, epsEst, epsAct, surprise, surprisePct, revEst, revAct float64, reported bool) {
+		v := event.Earnings{
+			Pair:            event.Pair{Exchange: exchange, Symbol: symbol},
+			Unix:            unix,
+			Hour:            hour,
+			Year:            year,
+			Quarter:         quarter,
+			EPSEstimate:     epsEst,
+			EPSActual:       epsAct,
+			Surprise:        surprise,
+			SurprisePercent: surprisePct,
+			RevenueEstimate: revEst,
+			RevenueActual:   revAct,
+			Reported:        reported,
+		}
+		roundTrip(t, v, exchange, symbol, hour)
+	})
+}
+
+func FuzzDividend(f *testing.F) {
+	f.Add("finnhub", "AAPL", int64(1700000000), int64(1700600000), 0.24, "USD")
+	f.Fuzz(func(t *testing.T, exchange, symbol string, unix, payUnix int64, amount float64, currency string) {
+		v := event.Dividend{
+			Pair:     event.Pair{Exchange: exchange, Symbol: symbol},
+			Unix:     unix,
+			PayUnix:  payUnix,
+			Amount:   amount,
+			Currency: currency,
+		}
+		roundTrip(t, v, exchange, symbol, currency)
+	})
+}
+
+func FuzzSplit(f *testing.F) {
+	f.Add("finnhub", "AAPL", int64(1598832000), 1.0, 4.0)
+	f.Fuzz(func(t *testing.T, exchange, symbol string, unix int64, from, to float64) {
+		v := event.Split{
+			Pair: event.Pair{Exchange: exchange, Symbol: symbol},
+			Unix: unix,
+			From: from,
+			To:   to,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+func FuzzSelected(f *testing.F) {
+	f.Add("finnhub", "AAPL")
+	f.Add("", "")
+	f.Fuzz(func(t *testing.T, exchange, symbol string) {
+		roundTrip(t, event.Selected{Pair: event.Pair{Exchange: exchange, Symbol: symbol}}, exchange, symbol)
+	})
+}
+
+// levels reads price, qty pairs out of b, 16 bytes a level. none is nil,
+// like a book that came off the wire without that side
+func levels(b []byte) []event.BookLevel {
+	var out []event.BookLevel
+	for ; len(b) >= 16; b = b[16:] {
+		out = append(out, event.BookLevel{
+			Price: math.Float64frombits(binary.LittleEndian.Uint64(b)),
+			Qty:   math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+		})
+	}
+	return out
+}
+
+func FuzzBook(f *testing.F) {
+	level := func(price, qty float64) []byte {
+		b := binary.LittleEndian.AppendUint64(nil, math.Float64bits(price))
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(qty))
+	}
+	f.Add("finnhub", "BINANCE:BTCUSDT", append(level(100, 1), level(99.5, 2)...), level(100.5, 3), int64(1700000000))
+	f.Add("", "", []byte{}, []byte{1, 2, 3}, int64(0))
+	f.Fuzz(func(t *testing.T, exchange, symbol string, bids, asks []byte, unix int64) {
+		v := event.Book{
+			Pair: event.Pair{Exchange: exchange, Symbol: symbol},
+			Bids: levels(bids),
+			Asks: levels(asks),
+			Unix: unix,
+		}
+		roundTrip(t, v, exchange, symbol)
+	})
+}
+
+// events are one of each, for the stream tests
+var events = []any{
+	event.StockTrade{Pair: event.Pair{Exchange: "finnhub", Symbol: "AAPL"}, Price: 190.5, Qty: 10, IsBuy: true, Unix: 1700000000123},
+	event.Quote{Pair: event.Pair{Exchange: "finnhub", Symbol: "AAPL"}, Current: 190.5, PrevClose: 188},
+	event.Selected{Pair: event.Pair{Exchange: "finnhub", Symbol: "MSFT"}},
+	event.News{Pair: event.Pair{Exchange: "finnhub", Symbol: "AAPL"}, ID: 1, Headline: "line\nbreak"},
+}
+
+func encodeAll(t *testing.T, codec Codec) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, codec)
+	for _, v := range events {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode %T: %v", v, err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestStream(t *testing.T) {
+	for _, codec := range codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(encodeAll(t, codec)), codec)
+			for _, want := range events {
+				got, err := dec.Decode()
+				if err != nil {
+					t.Fatalf("Decode: %v", err)
+				}
+				same(t, "Decode", want, got)
+			}
+			if _, err := dec.Decode(); err != io.EOF {
+				t.Fatalf("after the last event got %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	whole := encodeAll(t, Proto)
+	first, err := Proto.Marshal(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := len(binary.AppendUvarint(nil, uint64(len(first))))
+
+	for _, tc := range []struct {
+		name string
+		cut  int // bytes kept of the first frame
+	}{
+		{"in the payload", prefix + len(first) - 1},
+		{"right after the length", prefix},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dec := NewDecoder(bytes.NewReader(whole[:tc.cut]), Proto)
+			if _, err := dec.Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("got %v, want io.ErrUnexpectedEOF", err)
+			}
+		})
+	}
+
+	t.Run("in the length", func(t *testing.T) {
+		// a varint with its continuation bit set and nothing after
+		dec := NewDecoder(bytes.NewReader([]byte{0x80}), Proto)
+		if _, err := dec.Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("got %v, want io.ErrUnexpectedEOF", err)
+		}
+	})
+
+	t.Run("json line", func(t *testing.T) {
+		line, err := JSON.Marshal(events[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := NewDecoder(bytes.NewReader(line[:len(line)/2]), JSON)
+		if _, err := dec.Decode(); err == nil || err == io.EOF {
+			t.Fatalf("got %v, want a decode error", err)
+		}
+	})
+}
+
+func TestStreamFrameTooBig(t *testing.T) {
+	frame := binary.AppendUvarint(nil, maxFrame+1)
+	// no payload, the length alone has to be refused
+	dec := NewDecoder(bytes.NewReader(frame), Proto)
+	_, err := dec.Decode()
+	if err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Fatalf("got %v, want the frame refused", err)
+	}
+	if cap(dec.buf) > 0 {
+		t.Fatalf("allocated %d bytes for a frame it refused", cap(dec.buf))
+	}
+}
+
+func TestStreamJSONNoTrailingNewline(t *testing.T) {
+	b := bytes.TrimSuffix(encodeAll(t, JSON), []byte("\n"))
+	dec := NewDecoder(bytes.NewReader(b), JSON)
+	for _, want := range events {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		same(t, "Decode", want, got)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("after the last line got %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,330 @@
+// Package eventpb is the protobuf form of the event package, the one
+// serialization contract for events going between nodes or to disk.
+// FromEvent and ToEvent convert any event type, the Proto and JSON codecs
+// write them wrapped in a versioned Envelope. event.proto has the rules
+// for changing the schema without breaking what's already written.
+package eventpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative eventpb/event.proto
+
+import (
+	"github.com/Scrimzay/stockspider/event"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// FromEvent converts an event value to its message, ok is false for
+// anything that isn't an event type
+func FromEvent(v any) (m proto.Message, ok bool) {
+	switch v := v.(type) {
+	case event.MarketStatus:
+		return &MarketStatus{
+			Pair:     fromPair(v.Pair),
+			Exchange: v.Exchange,
+			IsOpen:   v.IsOpen,
+			Session:  v.Session,
+			Holiday:  v.Holiday,
+			Timezone: v.Timezone,
+			Unix:     v.Unix,
+		}, true
+	case event.RecommendationTrends:
+		return &RecommendationTrends{
+			Pair:       fromPair(v.Pair),
+			Period:     v.Period,
+			Buy:        v.Buy,
+			Hold:       v.Hold,
+			Sell:       v.Sell,
+			StrongBuy:  v.StrongBuy,
+			StrongSell: v.StrongSell,
+		}, true
+	case event.PriceTarget:
+		return &PriceTarget{
+			Pair:     fromPair(v.Pair),
+			High:     v.High,
+			Low:      v.Low,
+			Mean:     v.Mean,
+			Median:   v.Median,
+			Analysts: v.Analysts,
+			Unix:     v.Unix,
+		}, true
+	case event.RatingChange:
+		return &RatingChange{
+			Pair:      fromPair(v.Pair),
+			Unix:      v.Unix,
+			Company:   v.Company,
+			FromGrade: v.FromGrade,
+			ToGrade:   v.ToGrade,
+			Action:    v.Action,
+		}, true
+	case event.StockTrade:
+		return &StockTrade{
+			Pair:  fromPair(v.Pair),
+			Price: v.Price,
+			Qty:   v.Qty,
+			IsBuy: v.IsBuy,
+			Unix:  v.Unix,
+		}, true
+	case event.Candle:
+		return &Candle{
+			Pair:      fromPair(v.Pair),
+			Timeframe: v.Timeframe,
+			Unix:      v.Unix,
+			Open:      v.Open,
+			High:      v.High,
+			Low:       v.Low,
+			Close:     v.Close,
+			Volume:    v.Volume,
+		}, true
+	case event.Quote:
+		return &Quote{
+			Pair:      fromPair(v.Pair),
+			Current:   v.Current,
+			High:      v.High,
+			Low:       v.Low,
+			Open:      v.Open,
+			PrevClose: v.PrevClose,
+			Unix:      v.Unix,
+		}, true
+	case event.SymbolMetric:
+		return &SymbolMetric{
+			Pair:                         fromPair(v.Pair),
+			TenDayAverageTradingVolume:   v.TenDayAverageTradingVolume,
+			FiftyTwoWeekHigh:             v.FiftyTwoWeekHigh,
+			FiftyTwoWeekLow:              v.FiftyTwoWeekLow,
+			FiftyTwoWeekPriceReturnDaily: v.FiftyTwoWeekPriceReturnDaily,
+		}, true
+	case event.Stat:
+		return &Stat{
+			Pair:      fromPair(v.Pair),
+			MarkPrice: v.MarkPrice,
+			Funding:   v.Funding,
+			Unix:      v.Unix,
+		}, true
+	case event.News:
+		return &News{
+			Pair:     fromPair(v.Pair),
+			Id:       v.ID,
+			Category: v.Category,
+			Headline: v.Headline,
+			Summary:  v.Summary,
+			Source:   v.Source,
+			Url:      v.URL,
+			Image:    v.Image,
+			Related:  v.Related,
+			Unix:     v.Unix,
+		}, true
+	case event.Earnings:
+		return &Earnings{
+			Pair:            fromPair(v.Pair),
+			Unix:            v.Unix,
+			Hour:            v.Hour,
+			Year:            v.Year,
+			Quarter:         v.Quarter,
+			EpsEstimate:     v.EPSEstimate,
+			EpsActual:       v.EPSActual,
+			Surprise:        v.Surprise,
+			SurprisePercent: v.SurprisePercent,
+			RevenueEstimate: v.RevenueEstimate,
+			RevenueActual:   v.RevenueActual,
+			Reported:        v.Reported,
+		}, true
+	case event.Dividend:
+		return &Dividend{
+			Pair:     fromPair(v.Pair),
+			Unix:     v.Unix,
+			PayUnix:  v.PayUnix,
+			Amount:   v.Amount,
+			Currency: v.Currency,
+		}, true
+	case event.Split:
+		return &Split{
+			Pair: fromPair(v.Pair),
+			Unix: v.Unix,
+			From: v.From,
+			To:   v.To,
+		}, true
+	case event.Selected:
+		return &Selected{Pair: fromPair(v.Pair)}, true
+	case event.Book:
+		return &Book{
+			Pair: fromPair(v.Pair),
+			Bids: fromLevels(v.Bids),
+			Asks: fromLevels(v.Asks),
+			Unix: v.Unix,
+		}, true
+	}
+	return nil, false
+}
+
+// ToEvent converts a message back to its event value, ok is false for
+// messages that aren't events
+func ToEvent(m proto.Message) (v any, ok bool) {
+	switch m := m.(type) {
+	case *MarketStatus:
+		return event.MarketStatus{
+			Pair:     toPair(m.Pair),
+			Exchange: m.Exchange,
+			IsOpen:   m.IsOpen,
+			Session:  m.Session,
+			Holiday:  m.Holiday,
+			Timezone: m.Timezone,
+			Unix:     m.Unix,
+		}, true
+	case *RecommendationTrends:
+		return event.RecommendationTrends{
+			Pair:       toPair(m.Pair),
+			Period:     m.Period,
+			Buy:        m.Buy,
+			Hold:       m.Hold,
+			Sell:       m.Sell,
+			StrongBuy:  m.StrongBuy,
+			StrongSell: m.StrongSell,
+		}, true
+	case *PriceTarget:
+		return event.PriceTarget{
+			Pair:     toPair(m.Pair),
+			High:     m.High,
+			Low:      m.Low,
+			Mean:     m.Mean,
+			Median:   m.Median,
+			Analysts: m.Analysts,
+			Unix:     m.Unix,
+		}, true
+	case *RatingChange:
+		return event.RatingChange{
+			Pair:      toPair(m.Pair),
+			Unix:      m.Unix,
+			Company:   m.Company,
+			FromGrade: m.FromGrade,
+			ToGrade:   m.ToGrade,
+			Action:    m.Action,
+		}, true
+	case *StockTrade:
+		return event.StockTrade{
+			Pair:  toPair(m.Pair),
+			Price: m.Price,
+			Qty:   m.Qty,
+			IsBuy: m.IsBuy,
+			Unix:  m.Unix,
+		}, true
+	case *Candle:
+		return event.Candle{
+			Pair:      toPair(m.Pair),
+			Timeframe: m.Timeframe,
+			Unix:      m.Unix,
+			Open:      m.Open,
+			High:      m.High,
+			Low:       m.Low,
+			Close:     m.Close,
+			Volume:    m.Volume,
+		}, true
+	case *Quote:
+		return event.Quote{
+			Pair:      toPair(m.Pair),
+			Current:   m.Current,
+			High:      m.High,
+			Low:       m.Low,
+			Open:      m.Open,
+			PrevClose: m.PrevClose,
+			Unix:      m.Unix,
+		}, true
+	case *SymbolMetric:
+		return event.SymbolMetric{
+			Pair:                         toPair(m.Pair),
+			TenDayAverageTradingVolume:   m.TenDayAverageTradingVolume,
+			FiftyTwoWeekHigh:             m.FiftyTwoWeekHigh,
+			FiftyTwoWeekLow:              m.FiftyTwoWeekLow,
+			FiftyTwoWeekPriceReturnDaily: m.FiftyTwoWeekPriceReturnDaily,
+		}, true
+	case *Stat:
+		return event.Stat{
+			Pair:      toPair(m.Pair),
+			MarkPrice: m.MarkPrice,
+			Funding:   m.Funding,
+			Unix:      m.Unix,
+		}, true
+	case *News:
+		return event.News{
+			Pair:     toPair(m.Pair),
+			ID:       m.Id,
+			Category: m.Category,
+			Headline: m.Headline,
+			Summary:  m.Summary,
+			Source:   m.Source,
+			URL:      m.Url,
+			Image:    m.Image,
+			Related:  m.Related,
+			Unix:     m.Unix,
+		}, true
+	case *Earnings:
+		return event.Earnings{
+			Pair:            toPair(m.Pair),
+			Unix:            m.Unix,
+			Hour:            m.Hour,
+			Year:            m.Year,
+			Quarter:         m.Quarter,
+			EPSEstimate:     m.EpsEstimate,
+			EPSActual:       m.EpsActual,
+			Surprise:        m.Surprise,
+			SurprisePercent: m.SurprisePercent,
+			RevenueEstimate: m.RevenueEstimate,
+			RevenueActual:   m.RevenueActual,
+			Reported:        m.Reported,
+		}, true
+	case *Dividend:
+		return event.Dividend{
+			Pair:     toPair(m.Pair),
+			Unix:     m.Unix,
+			PayUnix:  m.PayUnix,
+			Amount:   m.Amount,
+			Currency: m.Currency,
+		}, true
+	case *Split:
+		return event.Split{
+			Pair: toPair(m.Pair),
+			Unix: m.Unix,
+			From: m.From,
+			To:   m.To,
+		}, true
+	case *Selected:
+		return event.Selected{Pair: toPair(m.Pair)}, true
+	case *Book:
+		return event.Book{
+			Pair: toPair(m.Pair),
+			Bids: toLevels(m.Bids),
+			Asks: toLevels(m.Asks),
+			Unix: m.Unix,
+		}, true
+	}
+	return nil, false
+}
+
+func fromPair(p event.Pair) *Pair {
+	return &Pair{Exchange: p.Exchange, Symbol: p.Symbol}
+}
+
+func toPair(p *Pair) event.Pair {
+	return event.Pair{Exchange: p.GetExchange(), Symbol: p.GetSymbol()}
+}
+
+func fromLevels(levels []event.BookLevel) []*BookLevel {
+	if levels == nil {
+		return nil
+	}
+	out := make([]*BookLevel, len(levels))
+	for i, l := range levels {
+		out[i] = &BookLevel{Price: l.Price, Qty: l.Qty}
+	}
+	return out
+}
+
+func toLevels(levels []*BookLevel) []event.BookLevel {
+	if levels == nil {
+		return nil
+	}
+	out := make([]event.BookLevel, len(levels))
+	for i, l := range levels {
+		out[i] = event.BookLevel{Price: l.GetPrice(), Qty: l.GetQty()}
+	}
+	return out
+}
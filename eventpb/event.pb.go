@@ -0,0 +1,2317 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: eventpb/event.proto
+
+package eventpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pair struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Exchange string `protobuf:"bytes,1,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	Symbol   string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+}
+
+func (x *Pair) Reset() {
+	*x = Pair{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pair) ProtoMessage() {}
+
+func (x *Pair) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pair.ProtoReflect.Descriptor instead.
+func (*Pair) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pair) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *Pair) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type MarketStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair     *Pair  `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Exchange string `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	IsOpen   bool   `protobuf:"varint,3,opt,name=is_open,json=isOpen,proto3" json:"is_open,omitempty"`
+	Session  string `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"`
+	Holiday  string `protobuf:"bytes,5,opt,name=holiday,proto3" json:"holiday,omitempty"`
+	Timezone string `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Unix     int64  `protobuf:"varint,7,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *MarketStatus) Reset() {
+	*x = MarketStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MarketStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketStatus) ProtoMessage() {}
+
+func (x *MarketStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketStatus.ProtoReflect.Descriptor instead.
+func (*MarketStatus) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MarketStatus) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *MarketStatus) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *MarketStatus) GetIsOpen() bool {
+	if x != nil {
+		return x.IsOpen
+	}
+	return false
+}
+
+func (x *MarketStatus) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *MarketStatus) GetHoliday() string {
+	if x != nil {
+		return x.Holiday
+	}
+	return ""
+}
+
+func (x *MarketStatus) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *MarketStatus) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type RecommendationTrends struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair       *Pair  `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Period     string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	Buy        int64  `protobuf:"varint,3,opt,name=buy,proto3" json:"buy,omitempty"`
+	Hold       int64  `protobuf:"varint,4,opt,name=hold,proto3" json:"hold,omitempty"`
+	Sell       int64  `protobuf:"varint,5,opt,name=sell,proto3" json:"sell,omitempty"`
+	StrongBuy  int64  `protobuf:"varint,6,opt,name=strong_buy,json=strongBuy,proto3" json:"strong_buy,omitempty"`
+	StrongSell int64  `protobuf:"varint,7,opt,name=strong_sell,json=strongSell,proto3" json:"strong_sell,omitempty"`
+}
+
+func (x *RecommendationTrends) Reset() {
+	*x = RecommendationTrends{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecommendationTrends) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendationTrends) ProtoMessage() {}
+
+func (x *RecommendationTrends) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendationTrends.ProtoReflect.Descriptor instead.
+func (*RecommendationTrends) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RecommendationTrends) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *RecommendationTrends) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *RecommendationTrends) GetBuy() int64 {
+	if x != nil {
+		return x.Buy
+	}
+	return 0
+}
+
+func (x *RecommendationTrends) GetHold() int64 {
+	if x != nil {
+		return x.Hold
+	}
+	return 0
+}
+
+func (x *RecommendationTrends) GetSell() int64 {
+	if x != nil {
+		return x.Sell
+	}
+	return 0
+}
+
+func (x *RecommendationTrends) GetStrongBuy() int64 {
+	if x != nil {
+		return x.StrongBuy
+	}
+	return 0
+}
+
+func (x *RecommendationTrends) GetStrongSell() int64 {
+	if x != nil {
+		return x.StrongSell
+	}
+	return 0
+}
+
+type PriceTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair     *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	High     float64 `protobuf:"fixed64,2,opt,name=high,proto3" json:"high,omitempty"`
+	Low      float64 `protobuf:"fixed64,3,opt,name=low,proto3" json:"low,omitempty"`
+	Mean     float64 `protobuf:"fixed64,4,opt,name=mean,proto3" json:"mean,omitempty"`
+	Median   float64 `protobuf:"fixed64,5,opt,name=median,proto3" json:"median,omitempty"`
+	Analysts int64   `protobuf:"varint,6,opt,name=analysts,proto3" json:"analysts,omitempty"`
+	Unix     int64   `protobuf:"varint,7,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *PriceTarget) Reset() {
+	*x = PriceTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PriceTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceTarget) ProtoMessage() {}
+
+func (x *PriceTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceTarget.ProtoReflect.Descriptor instead.
+func (*PriceTarget) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PriceTarget) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *PriceTarget) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *PriceTarget) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *PriceTarget) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *PriceTarget) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *PriceTarget) GetAnalysts() int64 {
+	if x != nil {
+		return x.Analysts
+	}
+	return 0
+}
+
+func (x *PriceTarget) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type RatingChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair      *Pair  `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Unix      int64  `protobuf:"varint,2,opt,name=unix,proto3" json:"unix,omitempty"`
+	Company   string `protobuf:"bytes,3,opt,name=company,proto3" json:"company,omitempty"`
+	FromGrade string `protobuf:"bytes,4,opt,name=from_grade,json=fromGrade,proto3" json:"from_grade,omitempty"`
+	ToGrade   string `protobuf:"bytes,5,opt,name=to_grade,json=toGrade,proto3" json:"to_grade,omitempty"`
+	Action    string `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (x *RatingChange) Reset() {
+	*x = RatingChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RatingChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingChange) ProtoMessage() {}
+
+func (x *RatingChange) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingChange.ProtoReflect.Descriptor instead.
+func (*RatingChange) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RatingChange) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *RatingChange) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+func (x *RatingChange) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *RatingChange) GetFromGrade() string {
+	if x != nil {
+		return x.FromGrade
+	}
+	return ""
+}
+
+func (x *RatingChange) GetToGrade() string {
+	if x != nil {
+		return x.ToGrade
+	}
+	return ""
+}
+
+func (x *RatingChange) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type StockTrade struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair  *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Price float64 `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Qty   float64 `protobuf:"fixed64,3,opt,name=qty,proto3" json:"qty,omitempty"`
+	IsBuy bool    `protobuf:"varint,4,opt,name=is_buy,json=isBuy,proto3" json:"is_buy,omitempty"`
+	Unix  int64   `protobuf:"varint,5,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *StockTrade) Reset() {
+	*x = StockTrade{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StockTrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockTrade) ProtoMessage() {}
+
+func (x *StockTrade) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockTrade.ProtoReflect.Descriptor instead.
+func (*StockTrade) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StockTrade) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *StockTrade) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *StockTrade) GetQty() float64 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+func (x *StockTrade) GetIsBuy() bool {
+	if x != nil {
+		return x.IsBuy
+	}
+	return false
+}
+
+func (x *StockTrade) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type Candle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair      *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Timeframe string  `protobuf:"bytes,2,opt,name=timeframe,proto3" json:"timeframe,omitempty"`
+	Unix      int64   `protobuf:"varint,3,opt,name=unix,proto3" json:"unix,omitempty"`
+	Open      float64 `protobuf:"fixed64,4,opt,name=open,proto3" json:"open,omitempty"`
+	High      float64 `protobuf:"fixed64,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low       float64 `protobuf:"fixed64,6,opt,name=low,proto3" json:"low,omitempty"`
+	Close     float64 `protobuf:"fixed64,7,opt,name=close,proto3" json:"close,omitempty"`
+	Volume    float64 `protobuf:"fixed64,8,opt,name=volume,proto3" json:"volume,omitempty"`
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Candle) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Candle) GetTimeframe() string {
+	if x != nil {
+		return x.Timeframe
+	}
+	return ""
+}
+
+func (x *Candle) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type Quote struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair      *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Current   float32 `protobuf:"fixed32,2,opt,name=current,proto3" json:"current,omitempty"`
+	High      float32 `protobuf:"fixed32,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low       float32 `protobuf:"fixed32,4,opt,name=low,proto3" json:"low,omitempty"`
+	Open      float32 `protobuf:"fixed32,5,opt,name=open,proto3" json:"open,omitempty"`
+	PrevClose float32 `protobuf:"fixed32,6,opt,name=prev_close,json=prevClose,proto3" json:"prev_close,omitempty"`
+	Unix      int64   `protobuf:"varint,7,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Quote) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Quote) GetCurrent() float32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *Quote) GetHigh() float32 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Quote) GetLow() float32 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Quote) GetOpen() float32 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Quote) GetPrevClose() float32 {
+	if x != nil {
+		return x.PrevClose
+	}
+	return 0
+}
+
+func (x *Quote) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type SymbolMetric struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair                         *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	TenDayAverageTradingVolume   float64 `protobuf:"fixed64,2,opt,name=ten_day_average_trading_volume,json=tenDayAverageTradingVolume,proto3" json:"ten_day_average_trading_volume,omitempty"`
+	FiftyTwoWeekHigh             float64 `protobuf:"fixed64,3,opt,name=fifty_two_week_high,json=fiftyTwoWeekHigh,proto3" json:"fifty_two_week_high,omitempty"`
+	FiftyTwoWeekLow              float64 `protobuf:"fixed64,4,opt,name=fifty_two_week_low,json=fiftyTwoWeekLow,proto3" json:"fifty_two_week_low,omitempty"`
+	FiftyTwoWeekPriceReturnDaily float64 `protobuf:"fixed64,5,opt,name=fifty_two_week_price_return_daily,json=fiftyTwoWeekPriceReturnDaily,proto3" json:"fifty_two_week_price_return_daily,omitempty"`
+}
+
+func (x *SymbolMetric) Reset() {
+	*x = SymbolMetric{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SymbolMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolMetric) ProtoMessage() {}
+
+func (x *SymbolMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolMetric.ProtoReflect.Descriptor instead.
+func (*SymbolMetric) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SymbolMetric) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *SymbolMetric) GetTenDayAverageTradingVolume() float64 {
+	if x != nil {
+		return x.TenDayAverageTradingVolume
+	}
+	return 0
+}
+
+func (x *SymbolMetric) GetFiftyTwoWeekHigh() float64 {
+	if x != nil {
+		return x.FiftyTwoWeekHigh
+	}
+	return 0
+}
+
+func (x *SymbolMetric) GetFiftyTwoWeekLow() float64 {
+	if x != nil {
+		return x.FiftyTwoWeekLow
+	}
+	return 0
+}
+
+func (x *SymbolMetric) GetFiftyTwoWeekPriceReturnDaily() float64 {
+	if x != nil {
+		return x.FiftyTwoWeekPriceReturnDaily
+	}
+	return 0
+}
+
+type Stat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair      *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	MarkPrice float64 `protobuf:"fixed64,2,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	Funding   float64 `protobuf:"fixed64,3,opt,name=funding,proto3" json:"funding,omitempty"`
+	Unix      int64   `protobuf:"varint,4,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *Stat) Reset() {
+	*x = Stat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stat) ProtoMessage() {}
+
+func (x *Stat) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stat.ProtoReflect.Descriptor instead.
+func (*Stat) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Stat) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Stat) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *Stat) GetFunding() float64 {
+	if x != nil {
+		return x.Funding
+	}
+	return 0
+}
+
+func (x *Stat) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type News struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair     *Pair  `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Id       int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Category string `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Headline string `protobuf:"bytes,4,opt,name=headline,proto3" json:"headline,omitempty"`
+	Summary  string `protobuf:"bytes,5,opt,name=summary,proto3" json:"summary,omitempty"`
+	Source   string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	Url      string `protobuf:"bytes,7,opt,name=url,proto3" json:"url,omitempty"`
+	Image    string `protobuf:"bytes,8,opt,name=image,proto3" json:"image,omitempty"`
+	Related  string `protobuf:"bytes,9,opt,name=related,proto3" json:"related,omitempty"`
+	Unix     int64  `protobuf:"varint,10,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *News) Reset() {
+	*x = News{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *News) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*News) ProtoMessage() {}
+
+func (x *News) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use News.ProtoReflect.Descriptor instead.
+func (*News) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *News) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *News) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *News) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *News) GetHeadline() string {
+	if x != nil {
+		return x.Headline
+	}
+	return ""
+}
+
+func (x *News) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *News) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *News) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *News) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *News) GetRelated() string {
+	if x != nil {
+		return x.Related
+	}
+	return ""
+}
+
+func (x *News) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type Earnings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair            *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Unix            int64   `protobuf:"varint,2,opt,name=unix,proto3" json:"unix,omitempty"`
+	Hour            string  `protobuf:"bytes,3,opt,name=hour,proto3" json:"hour,omitempty"`
+	Year            int64   `protobuf:"varint,4,opt,name=year,proto3" json:"year,omitempty"`
+	Quarter         int64   `protobuf:"varint,5,opt,name=quarter,proto3" json:"quarter,omitempty"`
+	EpsEstimate     float64 `protobuf:"fixed64,6,opt,name=eps_estimate,json=epsEstimate,proto3" json:"eps_estimate,omitempty"`
+	EpsActual       float64 `protobuf:"fixed64,7,opt,name=eps_actual,json=epsActual,proto3" json:"eps_actual,omitempty"`
+	Surprise        float64 `protobuf:"fixed64,8,opt,name=surprise,proto3" json:"surprise,omitempty"`
+	SurprisePercent float64 `protobuf:"fixed64,9,opt,name=surprise_percent,json=surprisePercent,proto3" json:"surprise_percent,omitempty"`
+	RevenueEstimate float64 `protobuf:"fixed64,10,opt,name=revenue_estimate,json=revenueEstimate,proto3" json:"revenue_estimate,omitempty"`
+	RevenueActual   float64 `protobuf:"fixed64,11,opt,name=revenue_actual,json=revenueActual,proto3" json:"revenue_actual,omitempty"`
+	Reported        bool    `protobuf:"varint,12,opt,name=reported,proto3" json:"reported,omitempty"`
+}
+
+func (x *Earnings) Reset() {
+	*x = Earnings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Earnings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Earnings) ProtoMessage() {}
+
+func (x *Earnings) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Earnings.ProtoReflect.Descriptor instead.
+func (*Earnings) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Earnings) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Earnings) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+func (x *Earnings) GetHour() string {
+	if x != nil {
+		return x.Hour
+	}
+	return ""
+}
+
+func (x *Earnings) GetYear() int64 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Earnings) GetQuarter() int64 {
+	if x != nil {
+		return x.Quarter
+	}
+	return 0
+}
+
+func (x *Earnings) GetEpsEstimate() float64 {
+	if x != nil {
+		return x.EpsEstimate
+	}
+	return 0
+}
+
+func (x *Earnings) GetEpsActual() float64 {
+	if x != nil {
+		return x.EpsActual
+	}
+	return 0
+}
+
+func (x *Earnings) GetSurprise() float64 {
+	if x != nil {
+		return x.Surprise
+	}
+	return 0
+}
+
+func (x *Earnings) GetSurprisePercent() float64 {
+	if x != nil {
+		return x.SurprisePercent
+	}
+	return 0
+}
+
+func (x *Earnings) GetRevenueEstimate() float64 {
+	if x != nil {
+		return x.RevenueEstimate
+	}
+	return 0
+}
+
+func (x *Earnings) GetRevenueActual() float64 {
+	if x != nil {
+		return x.RevenueActual
+	}
+	return 0
+}
+
+func (x *Earnings) GetReported() bool {
+	if x != nil {
+		return x.Reported
+	}
+	return false
+}
+
+type Dividend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair     *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Unix     int64   `protobuf:"varint,2,opt,name=unix,proto3" json:"unix,omitempty"`
+	PayUnix  int64   `protobuf:"varint,3,opt,name=pay_unix,json=payUnix,proto3" json:"pay_unix,omitempty"`
+	Amount   float64 `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string  `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+}
+
+func (x *Dividend) Reset() {
+	*x = Dividend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Dividend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dividend) ProtoMessage() {}
+
+func (x *Dividend) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dividend.ProtoReflect.Descriptor instead.
+func (*Dividend) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Dividend) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Dividend) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+func (x *Dividend) GetPayUnix() int64 {
+	if x != nil {
+		return x.PayUnix
+	}
+	return 0
+}
+
+func (x *Dividend) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Dividend) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Split struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair *Pair   `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Unix int64   `protobuf:"varint,2,opt,name=unix,proto3" json:"unix,omitempty"`
+	From float64 `protobuf:"fixed64,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   float64 `protobuf:"fixed64,4,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (x *Split) Reset() {
+	*x = Split{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Split) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Split) ProtoMessage() {}
+
+func (x *Split) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Split.ProtoReflect.Descriptor instead.
+func (*Split) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Split) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Split) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+func (x *Split) GetFrom() float64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *Split) GetTo() float64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type Selected struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair *Pair `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+}
+
+func (x *Selected) Reset() {
+	*x = Selected{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Selected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Selected) ProtoMessage() {}
+
+func (x *Selected) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Selected.ProtoReflect.Descriptor instead.
+func (*Selected) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Selected) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+type BookLevel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Price float64 `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Qty   float64 `protobuf:"fixed64,2,opt,name=qty,proto3" json:"qty,omitempty"`
+}
+
+func (x *BookLevel) Reset() {
+	*x = BookLevel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BookLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookLevel) ProtoMessage() {}
+
+func (x *BookLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookLevel.ProtoReflect.Descriptor instead.
+func (*BookLevel) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BookLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *BookLevel) GetQty() float64 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+type Book struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pair *Pair        `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Bids []*BookLevel `protobuf:"bytes,2,rep,name=bids,proto3" json:"bids,omitempty"`
+	Asks []*BookLevel `protobuf:"bytes,3,rep,name=asks,proto3" json:"asks,omitempty"`
+	Unix int64        `protobuf:"varint,4,opt,name=unix,proto3" json:"unix,omitempty"`
+}
+
+func (x *Book) Reset() {
+	*x = Book{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Book) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Book) ProtoMessage() {}
+
+func (x *Book) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Book.ProtoReflect.Descriptor instead.
+func (*Book) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Book) GetPair() *Pair {
+	if x != nil {
+		return x.Pair
+	}
+	return nil
+}
+
+func (x *Book) GetBids() []*BookLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *Book) GetAsks() []*BookLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *Book) GetUnix() int64 {
+	if x != nil {
+		return x.Unix
+	}
+	return 0
+}
+
+type Envelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Types that are assignable to Event:
+	//	*Envelope_MarketStatus
+	//	*Envelope_RecommendationTrends
+	//	*Envelope_PriceTarget
+	//	*Envelope_RatingChange
+	//	*Envelope_StockTrade
+	//	*Envelope_Candle
+	//	*Envelope_Quote
+	//	*Envelope_SymbolMetric
+	//	*Envelope_Stat
+	//	*Envelope_News
+	//	*Envelope_Earnings
+	//	*Envelope_Dividend
+	//	*Envelope_Split
+	//	*Envelope_Selected
+	//	*Envelope_Book
+	Event isEnvelope_Event `protobuf_oneof:"event"`
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eventpb_event_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_eventpb_event_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_eventpb_event_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Envelope) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (m *Envelope) GetEvent() isEnvelope_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Envelope) GetMarketStatus() *MarketStatus {
+	if x, ok := x.GetEvent().(*Envelope_MarketStatus); ok {
+		return x.MarketStatus
+	}
+	return nil
+}
+
+func (x *Envelope) GetRecommendationTrends() *RecommendationTrends {
+	if x, ok := x.GetEvent().(*Envelope_RecommendationTrends); ok {
+		return x.RecommendationTrends
+	}
+	return nil
+}
+
+func (x *Envelope) GetPriceTarget() *PriceTarget {
+	if x, ok := x.GetEvent().(*Envelope_PriceTarget); ok {
+		return x.PriceTarget
+	}
+	return nil
+}
+
+func (x *Envelope) GetRatingChange() *RatingChange {
+	if x, ok := x.GetEvent().(*Envelope_RatingChange); ok {
+		return x.RatingChange
+	}
+	return nil
+}
+
+func (x *Envelope) GetStockTrade() *StockTrade {
+	if x, ok := x.GetEvent().(*Envelope_StockTrade); ok {
+		return x.StockTrade
+	}
+	return nil
+}
+
+func (x *Envelope) GetCandle() *Candle {
+	if x, ok := x.GetEvent().(*Envelope_Candle); ok {
+		return x.Candle
+	}
+	return nil
+}
+
+func (x *Envelope) GetQuote() *Quote {
+	if x, ok := x.GetEvent().(*Envelope_Quote); ok {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *Envelope) GetSymbolMetric() *SymbolMetric {
+	if x, ok := x.GetEvent().(*Envelope_SymbolMetric); ok {
+		return x.SymbolMetric
+	}
+	return nil
+}
+
+func (x *Envelope) GetStat() *Stat {
+	if x, ok := x.GetEvent().(*Envelope_Stat); ok {
+		return x.Stat
+	}
+	return nil
+}
+
+func (x *Envelope) GetNews() *News {
+	if x, ok := x.GetEvent().(*Envelope_News); ok {
+		return x.News
+	}
+	return nil
+}
+
+func (x *Envelope) GetEarnings() *Earnings {
+	if x, ok := x.GetEvent().(*Envelope_Earnings); ok {
+		return x.Earnings
+	}
+	return nil
+}
+
+func (x *Envelope) GetDividend() *Dividend {
+	if x, ok := x.GetEvent().(*Envelope_Dividend); ok {
+		return x.Dividend
+	}
+	return nil
+}
+
+func (x *Envelope) GetSplit() *Split {
+	if x, ok := x.GetEvent().(*Envelope_Split); ok {
+		return x.Split
+	}
+	return nil
+}
+
+func (x *Envelope) GetSelected() *Selected {
+	if x, ok := x.GetEvent().(*Envelope_Selected); ok {
+		return x.Selected
+	}
+	return nil
+}
+
+func (x *Envelope) GetBook() *Book {
+	if x, ok := x.GetEvent().(*Envelope_Book); ok {
+		return x.Book
+	}
+	return nil
+}
+
+type isEnvelope_Event interface {
+	isEnvelope_Event()
+}
+
+type Envelope_MarketStatus struct {
+	MarketStatus *MarketStatus `protobuf:"bytes,2,opt,name=market_status,json=marketStatus,proto3,oneof"`
+}
+
+type Envelope_RecommendationTrends struct {
+	RecommendationTrends *RecommendationTrends `protobuf:"bytes,3,opt,name=recommendation_trends,json=recommendationTrends,proto3,oneof"`
+}
+
+type Envelope_PriceTarget struct {
+	PriceTarget *PriceTarget `protobuf:"bytes,4,opt,name=price_target,json=priceTarget,proto3,oneof"`
+}
+
+type Envelope_RatingChange struct {
+	RatingChange *RatingChange `protobuf:"bytes,5,opt,name=rating_change,json=ratingChange,proto3,oneof"`
+}
+
+type Envelope_StockTrade struct {
+	StockTrade *StockTrade `protobuf:"bytes,6,opt,name=stock_trade,json=stockTrade,proto3,oneof"`
+}
+
+type Envelope_Candle struct {
+	Candle *Candle `protobuf:"bytes,7,opt,name=candle,proto3,oneof"`
+}
+
+type Envelope_Quote struct {
+	Quote *Quote `protobuf:"bytes,8,opt,name=quote,proto3,oneof"`
+}
+
+type Envelope_SymbolMetric struct {
+	SymbolMetric *SymbolMetric `protobuf:"bytes,9,opt,name=symbol_metric,json=symbolMetric,proto3,oneof"`
+}
+
+type Envelope_Stat struct {
+	Stat *Stat `protobuf:"bytes,10,opt,name=stat,proto3,oneof"`
+}
+
+type Envelope_News struct {
+	News *News `protobuf:"bytes,11,opt,name=news,proto3,oneof"`
+}
+
+type Envelope_Earnings struct {
+	Earnings *Earnings `protobuf:"bytes,12,opt,name=earnings,proto3,oneof"`
+}
+
+type Envelope_Dividend struct {
+	Dividend *Dividend `protobuf:"bytes,13,opt,name=dividend,proto3,oneof"`
+}
+
+type Envelope_Split struct {
+	Split *Split `protobuf:"bytes,14,opt,name=split,proto3,oneof"`
+}
+
+type Envelope_Selected struct {
+	Selected *Selected `protobuf:"bytes,15,opt,name=selected,proto3,oneof"`
+}
+
+type Envelope_Book struct {
+	Book *Book `protobuf:"bytes,16,opt,name=book,proto3,oneof"`
+}
+
+func (*Envelope_MarketStatus) isEnvelope_Event() {}
+
+func (*Envelope_RecommendationTrends) isEnvelope_Event() {}
+
+func (*Envelope_PriceTarget) isEnvelope_Event() {}
+
+func (*Envelope_RatingChange) isEnvelope_Event() {}
+
+func (*Envelope_StockTrade) isEnvelope_Event() {}
+
+func (*Envelope_Candle) isEnvelope_Event() {}
+
+func (*Envelope_Quote) isEnvelope_Event() {}
+
+func (*Envelope_SymbolMetric) isEnvelope_Event() {}
+
+func (*Envelope_Stat) isEnvelope_Event() {}
+
+func (*Envelope_News) isEnvelope_Event() {}
+
+func (*Envelope_Earnings) isEnvelope_Event() {}
+
+func (*Envelope_Dividend) isEnvelope_Event() {}
+
+func (*Envelope_Split) isEnvelope_Event() {}
+
+func (*Envelope_Selected) isEnvelope_Event() {}
+
+func (*Envelope_Book) isEnvelope_Event() {}
+
+var File_eventpb_event_proto protoreflect.FileDescriptor
+
+var file_eventpb_event_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64,
+	0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x3a, 0x0a, 0x04, 0x50, 0x61, 0x69, 0x72,
+	0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x22, 0xd4, 0x01, 0x0a, 0x0c, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65,
+	0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61,
+	0x69, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x69, 0x73, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x69, 0x73, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x6c, 0x69, 0x64, 0x61, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x74,
+	0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74,
+	0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22, 0xd5, 0x01, 0x0a, 0x14,
+	0x52, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72,
+	0x65, 0x6e, 0x64, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x75, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x62, 0x75, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x68, 0x6f, 0x6c, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x65, 0x6c, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73,
+	0x65, 0x6c, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x62, 0x75,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x42,
+	0x75, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x6c,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x73, 0x74, 0x72, 0x6f, 0x6e, 0x67, 0x53,
+	0x65, 0x6c, 0x6c, 0x22, 0xbc, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x54, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04,
+	0x68, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x74, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x74, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e,
+	0x69, 0x78, 0x22, 0xbb, 0x01, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x75, 0x6e, 0x69, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x47, 0x72, 0x61, 0x64, 0x65, 0x12, 0x19, 0x0a,
+	0x08, 0x74, 0x6f, 0x5f, 0x67, 0x72, 0x61, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x74, 0x6f, 0x47, 0x72, 0x61, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x8c, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12,
+	0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x71, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x71, 0x74, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x73, 0x42, 0x75, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x6e, 0x69, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22,
+	0xcf, 0x01, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61,
+	0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69,
+	0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x66,
+	0x72, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x70, 0x65,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6f, 0x70, 0x65, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x68, 0x69, 0x67,
+	0x68, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6c, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x22, 0xbb, 0x01, 0x0a, 0x05, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x70,
+	0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61,
+	0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x04, 0x68, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x70, 0x65, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x6f, 0x70, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x72, 0x65, 0x76, 0x5f, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x09, 0x70, 0x72, 0x65, 0x76, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x6e, 0x69, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22,
+	0xa4, 0x02, 0x0a, 0x0c, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x42, 0x0a,
+	0x1e, 0x74, 0x65, 0x6e, 0x5f, 0x64, 0x61, 0x79, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x1a, 0x74, 0x65, 0x6e, 0x44, 0x61, 0x79, 0x41, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x54, 0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x56, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x12, 0x2d, 0x0a, 0x13, 0x66, 0x69, 0x66, 0x74, 0x79, 0x5f, 0x74, 0x77, 0x6f, 0x5f, 0x77,
+	0x65, 0x65, 0x6b, 0x5f, 0x68, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10,
+	0x66, 0x69, 0x66, 0x74, 0x79, 0x54, 0x77, 0x6f, 0x57, 0x65, 0x65, 0x6b, 0x48, 0x69, 0x67, 0x68,
+	0x12, 0x2b, 0x0a, 0x12, 0x66, 0x69, 0x66, 0x74, 0x79, 0x5f, 0x74, 0x77, 0x6f, 0x5f, 0x77, 0x65,
+	0x65, 0x6b, 0x5f, 0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x66, 0x69,
+	0x66, 0x74, 0x79, 0x54, 0x77, 0x6f, 0x57, 0x65, 0x65, 0x6b, 0x4c, 0x6f, 0x77, 0x12, 0x47, 0x0a,
+	0x21, 0x66, 0x69, 0x66, 0x74, 0x79, 0x5f, 0x74, 0x77, 0x6f, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x5f,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x64, 0x61, 0x69,
+	0x6c, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x1c, 0x66, 0x69, 0x66, 0x74, 0x79, 0x54,
+	0x77, 0x6f, 0x57, 0x65, 0x65, 0x6b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x52, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x22, 0x80, 0x01, 0x0a, 0x04, 0x53, 0x74, 0x61, 0x74, 0x12,
+	0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6d, 0x61, 0x72, 0x6b, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x09, 0x6d, 0x61, 0x72, 0x6b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x66,
+	0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x66, 0x75,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22, 0x83, 0x02, 0x0a, 0x04, 0x4e, 0x65,
+	0x77, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x68,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68,
+	0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x6e, 0x69, 0x78, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22,
+	0x84, 0x03, 0x0a, 0x08, 0x45, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2b, 0x0a, 0x04,
+	0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50,
+	0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x6f, 0x75, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x75,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x72, 0x74, 0x65, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x71, 0x75, 0x61, 0x72, 0x74, 0x65, 0x72, 0x12,
+	0x21, 0x0a, 0x0c, 0x65, 0x70, 0x73, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x65, 0x70, 0x73, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x70, 0x73, 0x5f, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x65, 0x70, 0x73, 0x41, 0x63, 0x74, 0x75, 0x61,
+	0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x72, 0x70, 0x72, 0x69, 0x73, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x08, 0x73, 0x75, 0x72, 0x70, 0x72, 0x69, 0x73, 0x65, 0x12, 0x29, 0x0a,
+	0x10, 0x73, 0x75, 0x72, 0x70, 0x72, 0x69, 0x73, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x73, 0x75, 0x72, 0x70, 0x72, 0x69, 0x73,
+	0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x76, 0x65,
+	0x6e, 0x75, 0x65, 0x5f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0f, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x5f, 0x61,
+	0x63, 0x74, 0x75, 0x61, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x72, 0x65, 0x76,
+	0x65, 0x6e, 0x75, 0x65, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x22, 0x9a, 0x01, 0x0a, 0x08, 0x44, 0x69, 0x76, 0x69, 0x64,
+	0x65, 0x6e, 0x64, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x75, 0x6e, 0x69, 0x78, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x5f, 0x75, 0x6e, 0x69, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x61, 0x79, 0x55, 0x6e, 0x69, 0x78, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x22, 0x6c, 0x0a, 0x05, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x2b, 0x0a, 0x04,
+	0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50,
+	0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x02, 0x74,
+	0x6f, 0x22, 0x37, 0x0a, 0x08, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x2b, 0x0a,
+	0x04, 0x70, 0x61, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x50, 0x61, 0x69, 0x72, 0x52, 0x04, 0x70, 0x61, 0x69, 0x72, 0x22, 0x33, 0x0a, 0x09, 0x42, 0x6f,
+	0x6f, 0x6b, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x71, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x71, 0x74, 0x79, 0x22,
+	0xab, 0x01, 0x0a, 0x04, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x69, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70,
+	0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x52,
+	0x04, 0x70, 0x61, 0x69, 0x72, 0x12, 0x30, 0x0a, 0x04, 0x62, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65,
+	0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x52, 0x04, 0x62, 0x69, 0x64, 0x73, 0x12, 0x30, 0x0a, 0x04, 0x61, 0x73, 0x6b, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69,
+	0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x52, 0x04, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x78, 0x22, 0xc3, 0x07,
+	0x0a, 0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x46, 0x0a, 0x0d, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0c,
+	0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x5e, 0x0a, 0x15,
+	0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74,
+	0x72, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x52, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72,
+	0x65, 0x6e, 0x64, 0x73, 0x48, 0x00, 0x52, 0x14, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x65, 0x6e, 0x64, 0x73, 0x12, 0x43, 0x0a, 0x0c,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x72, 0x69, 0x63, 0x65, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x46, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x5f, 0x74, 0x72, 0x61, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x64, 0x65, 0x48, 0x00, 0x52,
+	0x0a, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x63,
+	0x61, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x06, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x12, 0x30, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x48, 0x00, 0x52, 0x05, 0x71, 0x75, 0x6f,
+	0x74, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x5f, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x74,
+	0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x48, 0x00, 0x52, 0x04, 0x73, 0x74, 0x61, 0x74, 0x12, 0x2d, 0x0a, 0x04, 0x6e, 0x65, 0x77,
+	0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73,
+	0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x4e, 0x65, 0x77, 0x73,
+	0x48, 0x00, 0x52, 0x04, 0x6e, 0x65, 0x77, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x65, 0x61, 0x72, 0x6e,
+	0x69, 0x6e, 0x67, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x6f,
+	0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45,
+	0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x48, 0x00, 0x52, 0x08, 0x65, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69,
+	0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65,
+	0x6e, 0x64, 0x48, 0x00, 0x52, 0x08, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x12, 0x30,
+	0x0a, 0x05, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x48, 0x00, 0x52, 0x05, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x12, 0x39, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72,
+	0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48,
+	0x00, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x62,
+	0x6f, 0x6f, 0x6b, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x73, 0x74, 0x6f, 0x63,
+	0x6b, 0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x42, 0x6f,
+	0x6f, 0x6b, 0x48, 0x00, 0x52, 0x04, 0x62, 0x6f, 0x6f, 0x6b, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x53, 0x63, 0x72, 0x69, 0x6d, 0x7a, 0x61, 0x79, 0x2f, 0x73, 0x74, 0x6f, 0x63, 0x6b,
+	0x73, 0x70, 0x69, 0x64, 0x65, 0x72, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_eventpb_event_proto_rawDescOnce sync.Once
+	file_eventpb_event_proto_rawDescData = file_eventpb_event_proto_rawDesc
+)
+
+func file_eventpb_event_proto_rawDescGZIP() []byte {
+	file_eventpb_event_proto_rawDescOnce.Do(func() {
+		file_eventpb_event_proto_rawDescData = protoimpl.X.CompressGZIP(file_eventpb_event_proto_rawDescData)
+	})
+	return file_eventpb_event_proto_rawDescData
+}
+
+var file_eventpb_event_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_eventpb_event_proto_goTypes = []interface{}{
+	(*Pair)(nil),                 // 0: stockspider.event.Pair
+	(*MarketStatus)(nil),         // 1: stockspider.event.MarketStatus
+	(*RecommendationTrends)(nil), // 2: stockspider.event.RecommendationTrends
+	(*PriceTarget)(nil),          // 3: stockspider.event.PriceTarget
+	(*RatingChange)(nil),         // 4: stockspider.event.RatingChange
+	(*StockTrade)(nil),           // 5: stockspider.event.StockTrade
+	(*Candle)(nil),               // 6: stockspider.event.Candle
+	(*Quote)(nil),                // 7: stockspider.event.Quote
+	(*SymbolMetric)(nil),         // 8: stockspider.event.SymbolMetric
+	(*Stat)(nil),                 // 9: stockspider.event.Stat
+	(*News)(nil),                 // 10: stockspider.event.News
+	(*Earnings)(nil),             // 11: stockspider.event.Earnings
+	(*Dividend)(nil),             // 12: stockspider.event.Dividend
+	(*Split)(nil),                // 13: stockspider.event.Split
+	(*Selected)(nil),             // 14: stockspider.event.Selected
+	(*BookLevel)(nil),            // 15: stockspider.event.BookLevel
+	(*Book)(nil),                 // 16: stockspider.event.Book
+	(*Envelope)(nil),             // 17: stockspider.event.Envelope
+}
+var file_eventpb_event_proto_depIdxs = []int32{
+	0,  // 0: stockspider.event.MarketStatus.pair:type_name -> stockspider.event.Pair
+	0,  // 1: stockspider.event.RecommendationTrends.pair:type_name -> stockspider.event.Pair
+	0,  // 2: stockspider.event.PriceTarget.pair:type_name -> stockspider.event.Pair
+	0,  // 3: stockspider.event.RatingChange.pair:type_name -> stockspider.event.Pair
+	0,  // 4: stockspider.event.StockTrade.pair:type_name -> stockspider.event.Pair
+	0,  // 5: stockspider.event.Candle.pair:type_name -> stockspider.event.Pair
+	0,  // 6: stockspider.event.Quote.pair:type_name -> stockspider.event.Pair
+	0,  // 7: stockspider.event.SymbolMetric.pair:type_name -> stockspider.event.Pair
+	0,  // 8: stockspider.event.Stat.pair:type_name -> stockspider.event.Pair
+	0,  // 9: stockspider.event.News.pair:type_name -> stockspider.event.Pair
+	0,  // 10: stockspider.event.Earnings.pair:type_name -> stockspider.event.Pair
+	0,  // 11: stockspider.event.Dividend.pair:type_name -> stockspider.event.Pair
+	0,  // 12: stockspider.event.Split.pair:type_name -> stockspider.event.Pair
+	0,  // 13: stockspider.event.Selected.pair:type_name -> stockspider.event.Pair
+	0,  // 14: stockspider.event.Book.pair:type_name -> stockspider.event.Pair
+	15, // 15: stockspider.event.Book.bids:type_name -> stockspider.event.BookLevel
+	15, // 16: stockspider.event.Book.asks:type_name -> stockspider.event.BookLevel
+	1,  // 17: stockspider.event.Envelope.market_status:type_name -> stockspider.event.MarketStatus
+	2,  // 18: stockspider.event.Envelope.recommendation_trends:type_name -> stockspider.event.RecommendationTrends
+	3,  // 19: stockspider.event.Envelope.price_target:type_name -> stockspider.event.PriceTarget
+	4,  // 20: stockspider.event.Envelope.rating_change:type_name -> stockspider.event.RatingChange
+	5,  // 21: stockspider.event.Envelope.stock_trade:type_name -> stockspider.event.StockTrade
+	6,  // 22: stockspider.event.Envelope.candle:type_name -> stockspider.event.Candle
+	7,  // 23: stockspider.event.Envelope.quote:type_name -> stockspider.event.Quote
+	8,  // 24: stockspider.event.Envelope.symbol_metric:type_name -> stockspider.event.SymbolMetric
+	9,  // 25: stockspider.event.Envelope.stat:type_name -> stockspider.event.Stat
+	10, // 26: stockspider.event.Envelope.news:type_name -> stockspider.event.News
+	11, // 27: stockspider.event.Envelope.earnings:type_name -> stockspider.event.Earnings
+	12, // 28: stockspider.event.Envelope.dividend:type_name -> stockspider.event.Dividend
+	13, // 29: stockspider.event.Envelope.split:type_name -> stockspider.event.Split
+	14, // 30: stockspider.event.Envelope.selected:type_name -> stockspider.event.Selected
+	16, // 31: stockspider.event.Envelope.book:type_name -> stockspider.event.Book
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_eventpb_event_proto_init() }
+func file_eventpb_event_proto_init() {
+	if File_eventpb_event_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_eventpb_event_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pair); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MarketStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecommendationTrends); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PriceTarget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RatingChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StockTrade); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Candle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Quote); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SymbolMetric); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Stat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*News); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Earnings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Dividend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Split); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Selected); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BookLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Book); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eventpb_event_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Envelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_eventpb_event_proto_msgTypes[17].OneofWrappers = []interface{}{
+		(*Envelope_MarketStatus)(nil),
+		(*Envelope_RecommendationTrends)(nil),
+		(*Envelope_PriceTarget)(nil),
+		(*Envelope_RatingChange)(nil),
+		(*Envelope_StockTrade)(nil),
+		(*Envelope_Candle)(nil),
+		(*Envelope_Quote)(nil),
+		(*Envelope_SymbolMetric)(nil),
+		(*Envelope_Stat)(nil),
+		(*Envelope_News)(nil),
+		(*Envelope_Earnings)(nil),
+		(*Envelope_Dividend)(nil),
+		(*Envelope_Split)(nil),
+		(*Envelope_Selected)(nil),
+		(*Envelope_Book)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_eventpb_event_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_eventpb_event_proto_goTypes,
+		DependencyIndexes: file_eventpb_event_proto_depIdxs,
+		MessageInfos:      file_eventpb_event_proto_msgTypes,
+	}.Build()
+	File_eventpb_event_proto = out.File
+	file_eventpb_event_proto_rawDesc = nil
+	file_eventpb_event_proto_goTypes = nil
+	file_eventpb_event_proto_depIdxs = nil
+}
@@ -0,0 +1,233 @@
+// Package export writes trades, quotes and bars of a set of symbols over
+// a time range as CSV, JSON lines or Parquet, flat files pandas and DuckDB
+// read as they are. Times are UTC, in RFC 3339 for CSV and JSON lines and
+// millisecond timestamps for Parquet.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/store"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type Format string
+
+const (
+	CSV     Format = "csv"
+	JSONL   Format = "jsonl"
+	Parquet Format = "parquet"
+)
+
+var Formats = []Format{CSV, JSONL, Parquet}
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSONL, Parquet:
+		return f, nil
+	case "ndjson", "json":
+		return JSONL, nil
+	}
+	return "", fmt.Errorf("unknown export format %q, want csv, jsonl or parquet", s)
+}
+
+// FormatOf is the format a file name's extension asks for
+func FormatOf(path string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Kind is what gets exported
+type Kind string
+
+const (
+	Trades  Kind = "trades"
+	Quotes  Kind = "quotes"
+	Candles Kind = "candles"
+)
+
+var Kinds = []Kind{Trades, Quotes, Candles}
+
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(s)); k {
+	case Trades, Quotes, Candles:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown export %q, want trades, quotes or candles", s)
+}
+
+// Request is one export, every pair over [From, To)
+type Request struct {
+	Kind      Kind
+	Format    Format
+	Pairs     []event.Pair
+	From, To  time.Time
+	Timeframe candle.Timeframe // candles only
+}
+
+// FileName is a default name for req's file, like
+// aapl_candles_5m_20240603T1330-20240603T2000.parquet
+func (req Request) FileName() string {
+	symbols := make([]string, len(req.Pairs))
+	for i, p := range req.Pairs {
+		symbols[i] = strings.NewReplacer(":", "_", "/", "_", "\\", "_", "^", "_").Replace(strings.ToLower(p.Symbol))
+	}
+	name := strings.Join(symbols, "+") + "_" + string(req.Kind)
+	if req.Kind == Candles {
+		name += "_" + req.Timeframe.Name
+	}
+	const layout = "20060102T1504"
+	return fmt.Sprintf("%s_%s-%s.%s", name, req.From.UTC().Format(layout), req.To.UTC().Format(layout), req.Format)
+}
+
+// Sources are where an export reads from, the stores the app records into
+// and the bar sources asked in order like backfill does
+type Sources struct {
+	Ticks  *store.TickStore
+	Quotes *store.QuoteStore
+	Bars   []backfill.Source
+}
+
+// Write exports req to w and returns how many rows it wrote
+func (s Sources) Write(ctx context.Context, w io.Writer, req Request) (int, error) {
+	if len(req.Pairs) == 0 {
+		return 0, errors.New("nothing to export, no symbols")
+	}
+	if !req.From.Before(req.To) {
+		return 0, fmt.Errorf("empty range, %s isn't before %s", req.From.Format(time.RFC3339), req.To.Format(time.RFC3339))
+	}
+
+	switch req.Kind {
+	case Trades:
+		if s.Ticks == nil {
+			return 0, errors.New("no tick store to export trades from")
+		}
+		var rows []TradeRow
+		for _, p := range req.Pairs {
+			trades, err := s.Ticks.Trades(p, req.From, req.To)
+			if err != nil {
+				return 0, fmt.Errorf("reading trades of %s: %w", p.Symbol, err)
+			}
+			for _, t := range trades {
+				rows = append(rows, tradeRow(t))
+			}
+		}
+		return len(rows), write(w, req.Format, rows)
+
+	case Quotes:
+		if s.Quotes == nil {
+			return 0, errors.New("no quote store to export quotes from")
+		}
+		var rows []QuoteRow
+		for _, p := range req.Pairs {
+			quotes, err := s.Quotes.Quotes(p, req.From, req.To)
+			if err != nil {
+				return 0, fmt.Errorf("reading quotes of %s: %w", p.Symbol, err)
+			}
+			for _, q := range quotes {
+				rows = append(rows, quoteRow(q))
+			}
+		}
+		return len(rows), write(w, req.Format, rows)
+
+	case Candles:
+		if len(s.Bars) == 0 {
+			return 0, errors.New("no bar sources to export candles from")
+		}
+		if req.Timeframe.Duration == 0 {
+			return 0, errors.New("candles need a timeframe")
+		}
+		var rows []CandleRow
+		for _, p := range req.Pairs {
+			bars, err := backfill.Candles(ctx, s.Bars, p, req.Timeframe, req.From, req.To)
+			if err != nil {
+				return 0, fmt.Errorf("fetching bars of %s: %w", p.Symbol, err)
+			}
+			for _, bar := range bars {
+				rows = append(rows, candleRow(bar, req.Timeframe))
+			}
+		}
+		return len(rows), write(w, req.Format, rows)
+	}
+	return 0, fmt.Errorf("unknown export %q", req.Kind)
+}
+
+// WriteFile exports req to path. the file only shows up once it's
+// complete, a failed export leaves nothing behind
+func (s Sources) WriteFile(ctx context.Context, path string, req Request) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := s.Write(ctx, tmp, req)
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	return n, os.Rename(tmp.Name(), path)
+}
+
+// row is one line of an export, the csv side of it. json and parquet
+// go by the struct tags
+type row interface {
+	header() []string
+	record() []string
+}
+
+func write[T row](w io.Writer, f Format, rows []T) error {
+	switch f {
+	case CSV:
+		var zero T
+		cw := csv.NewWriter(w)
+		if err := cw.Write(zero.header()); err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if err := cw.Write(r.record()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case JSONL:
+		enc := json.NewEncoder(w)
+		for _, r := range rows {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Parquet:
+		return parquet.Write(w, rows, parquet.Compression(&parquet.Zstd))
+	}
+	return fmt.Errorf("unknown export format %q", f)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type TradeRow struct {
+	Time     time.Time `json:"time" parquet:"time,timestamp(millisecond)"`
+	Exchange string    `json:"exchange" parquet:"exchange,dict"`
+	Symbol   string    `json:"symbol" parquet:"symbol,dict"`
+	Price    float64   `json:"price" parquet:"price"`
+	Qty      float64   `json:"qty" parquet:"qty"`
+	Side     string    `json:"side" parquet:"side,dict"` // "buy" or "sell"
+}
+
+func tradeRow(t event.StockTrade) TradeRow {
+	side := "sell"
+	if t.IsBuy {
+		side = "buy"
+	}
+	return TradeRow{
+		Time:     time.UnixMilli(t.Unix).UTC(),
+		Exchange: t.Pair.Exchange,
+		Symbol:   t.Pair.Symbol,
+		Price:    t.Price,
+		Qty:      t.Qty,
+		Side:     side,
+	}
+}
+
+func (TradeRow) header() []string {
+	return []string{"time", "exchange", "symbol", "price", "qty", "side"}
+}
+
+func (r TradeRow) record() []string {
+	return []string{formatTime(r.Time), r.Exchange, r.Symbol, formatFloat(r.Price), formatFloat(r.Qty), r.Side}
+}
+
+type QuoteRow struct {
+	Time      time.Time `json:"time" parquet:"time,timestamp(millisecond)"`
+	Exchange  string    `json:"exchange" parquet:"exchange,dict"`
+	Symbol    string    `json:"symbol" parquet:"symbol,dict"`
+	Current   float64   `json:"current" parquet:"current"`
+	Open      float64   `json:"open" parquet:"open"`
+	High      float64   `json:"high" parquet:"high"`
+	Low       float64   `json:"low" parquet:"low"`
+	PrevClose float64   `json:"prev_close" parquet:"prev_close"`
+}
+
+func quoteRow(q event.Quote) QuoteRow {
+	return QuoteRow{
+		Time:      time.Unix(q.Unix, 0).UTC(),
+		Exchange:  q.Pair.Exchange,
+		Symbol:    q.Pair.Symbol,
+		Current:   float64(q.Current),
+		Open:      float64(q.Open),
+		High:      float64(q.High),
+		Low:       float64(q.Low),
+		PrevClose: float64(q.PrevClose),
+	}
+}
+
+func (QuoteRow) header() []string {
+	return []string{"time", "exchange", "symbol", "current", "open", "high", "low", "prev_close"}
+}
+
+func (r QuoteRow) record() []string {
+	return []string{
+		formatTime(r.Time), r.Exchange, r.Symbol,
+		formatFloat(r.Current), formatFloat(r.Open), formatFloat(r.High), formatFloat(r.Low), formatFloat(r.PrevClose),
+	}
+}
+
+// CandleRow is one bar, Time is when it opened
+type CandleRow struct {
+	Time      time.Time `json:"time" parquet:"time,timestamp(millisecond)"`
+	Exchange  string    `json:"exchange" parquet:"exchange,dict"`
+	Symbol    string    `json:"symbol" parquet:"symbol,dict"`
+	Timeframe string    `json:"timeframe" parquet:"timeframe,dict"`
+	Open      float64   `json:"open" parquet:"open"`
+	High      float64   `json:"high" parquet:"high"`
+	Low       float64   `json:"low" parquet:"low"`
+	Close     float64   `json:"close" parquet:"close"`
+	Volume    float64   `json:"volume" parquet:"volume"`
+}
+
+func candleRow(bar event.Candle, tf candle.Timeframe) CandleRow {
+	return CandleRow{
+		Time:      time.Unix(bar.Unix, 0).UTC(),
+		Exchange:  bar.Pair.Exchange,
+		Symbol:    bar.Pair.Symbol,
+		Timeframe: tf.Name,
+		Open:      bar.Open,
+		High:      bar.High,
+		Low:       bar.Low,
+		Close:     bar.Close,
+		Volume:    bar.Volume,
+	}
+}
+
+func (CandleRow) header() []string {
+	return []string{"time", "exchange", "symbol", "timeframe", "open", "high", "low", "close", "volume"}
+}
+
+func (r CandleRow) record() []string {
+	return []string{
+		formatTime(r.Time), r.Exchange, r.Symbol, r.Timeframe,
+		formatFloat(r.Open), formatFloat(r.High), formatFloat(r.Low), formatFloat(r.Close), formatFloat(r.Volume),
+	}
+}
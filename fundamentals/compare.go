@@ -0,0 +1,71 @@
+package fundamentals
+
+import (
+	"math"
+	"sort"
+)
+
+// PeerMetrics are the columns of the default peer comparison
+var PeerMetrics = []string{"marketCapitalization", "peTTM", "netProfitMarginTTM", "revenueGrowthTTMYoy"}
+
+// Comparison lines a symbol up against its peers on a few metrics
+type Comparison struct {
+	Metrics []Metric
+	Rows    []Row
+	Median  []float64 // per metric, NaN when nobody has it
+}
+
+// Row is one company in a comparison, Values line up with Metrics
+type Row struct {
+	Symbol string
+	Values []float64
+}
+
+// Compare builds a comparison of companies on keys, companies without
+// data yet get a row of NaN so the table shows them loading
+func Compare(symbols []string, data map[string]*Fundamentals, keys ...string) Comparison {
+	if len(keys) == 0 {
+		keys = PeerMetrics
+	}
+
+	c := Comparison{}
+	for _, key := range keys {
+		m, ok := Lookup(key)
+		if !ok {
+			m = Metric{Key: key, Label: key}
+		}
+		c.Metrics = append(c.Metrics, m)
+	}
+
+	for _, symbol := range symbols {
+		row := Row{Symbol: symbol, Values: make([]float64, len(keys))}
+		for i, key := range keys {
+			row.Values[i] = data[symbol].Value(key)
+		}
+		c.Rows = append(c.Rows, row)
+	}
+
+	c.Median = make([]float64, len(keys))
+	for i := range keys {
+		var values []float64
+		for _, row := range c.Rows {
+			if !math.IsNaN(row.Values[i]) {
+				values = append(values, row.Values[i])
+			}
+		}
+		c.Median[i] = median(values)
+	}
+	return c
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
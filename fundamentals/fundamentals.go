@@ -0,0 +1,172 @@
+// Package fundamentals turns finnhub's basic financials into the metrics
+// the fundamentals panel shows: valuation, profitability and balance sheet
+// figures with their annual and quarterly history, and how a symbol
+// compares to its peers.
+package fundamentals
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+// Point is one period of a series, Period is the fiscal period end
+type Point struct {
+	Period time.Time
+	Value  float64
+}
+
+// Series holds the annual and quarterly history of one metric, oldest first
+type Series struct {
+	Annual    []Point
+	Quarterly []Point
+}
+
+// Fundamentals is everything finnhub's basic financials returned for a symbol
+type Fundamentals struct {
+	Symbol  string
+	Values  map[string]float64
+	Series  map[string]Series // keyed by finnhub series name, "eps", "netMargin"...
+	Updated time.Time
+}
+
+// Value returns the metric's value, NaN when finnhub didn't send it
+func (f *Fundamentals) Value(key string) float64 {
+	if f == nil {
+		return math.NaN()
+	}
+	if v, ok := f.Values[key]; ok {
+		return v
+	}
+	return math.NaN()
+}
+
+// Available lists the known metrics of c that have a value
+func (f *Fundamentals) Available(c Category) []Metric {
+	var metrics []Metric
+	for _, m := range InCategory(c) {
+		if !math.IsNaN(f.Value(m.Key)) {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+// SeriesNames lists the series with any data, sorted
+func (f *Fundamentals) SeriesNames() []string {
+	names := make([]string, 0, len(f.Series))
+	for name, s := range f.Series {
+		if len(s.Annual) > 0 || len(s.Quarterly) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stale reports whether f is missing or older than ttl
+func (f *Fundamentals) Stale(now time.Time, ttl time.Duration) bool {
+	return f == nil || now.Sub(f.Updated) > ttl
+}
+
+// Parse turns finnhub's loosely typed response into Fundamentals
+func Parse(symbol string, res FH.BasicFinancials, now time.Time) *Fundamentals {
+	f := &Fundamentals{
+		Symbol:  symbol,
+		Values:  make(map[string]float64),
+		Series:  make(map[string]Series),
+		Updated: now,
+	}
+
+	if res.Metric != nil {
+		for key, raw := range *res.Metric {
+			// dates like 52WeekHighDate come as strings, skip them
+			if v, ok := raw.(float64); ok {
+				f.Values[key] = v
+			}
+		}
+	}
+
+	if res.Series != nil {
+		for period, raw := range *res.Series {
+			byName, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for name, rawPoints := range byName {
+				points := parsePoints(rawPoints)
+				s := f.Series[name]
+				switch period {
+				case "annual":
+					s.Annual = points
+				case "quarterly":
+					s.Quarterly = points
+				default:
+					continue
+				}
+				f.Series[name] = s
+			}
+		}
+	}
+	return f
+}
+
+// parsePoints reads [{"period": "2023-09-30", "v": 1.2}, ...]
+func parsePoints(raw interface{}) []Point {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	points := make([]Point, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		periodStr, _ := m["period"].(string)
+		period, err := time.Parse("2006-01-02", periodStr)
+		if err != nil {
+			continue
+		}
+		v, ok := m["v"].(float64)
+		if !ok {
+			continue
+		}
+		points = append(points, Point{Period: period, Value: v})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Period.Before(points[j].Period)
+	})
+	return points
+}
+
+// Fetch gets the full metric set and series for symbol
+func Fetch(ctx context.Context, client *FH.DefaultApiService, symbol string) (*Fundamentals, error) {
+	res, _, err := client.CompanyBasicFinancials(ctx).Symbol(symbol).Metric("all").Execute()
+	if err != nil {
+		return nil, fmt.Errorf("basic financials for %s: %w", symbol, err)
+	}
+	return Parse(symbol, res, time.Now()), nil
+}
+
+// Peers gets the companies finnhub groups with symbol, without symbol itself
+func Peers(ctx context.Context, client *FH.DefaultApiService, symbol string) ([]string, error) {
+	res, _, err := client.CompanyPeers(ctx).Symbol(symbol).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("peers for %s: %w", symbol, err)
+	}
+
+	peers := make([]string, 0, len(res))
+	for _, peer := range res {
+		if peer != "" && !strings.EqualFold(peer, symbol) {
+			peers = append(peers, peer)
+		}
+	}
+	return peers, nil
+}
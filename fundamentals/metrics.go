@@ -0,0 +1,167 @@
+package fundamentals
+
+import (
+	"fmt"
+	"math"
+)
+
+// Category groups metrics the way the fundamentals panel tabs them
+type Category int
+
+const (
+	Valuation Category = iota
+	Profitability
+	Growth
+	Dividends
+	Health
+	Trading
+)
+
+var Categories = []Category{Valuation, Profitability, Growth, Dividends, Health, Trading}
+
+func (c Category) String() string {
+	switch c {
+	case Valuation:
+		return "Valuation"
+	case Profitability:
+		return "Margins"
+	case Growth:
+		return "Growth"
+	case Dividends:
+		return "Dividends"
+	case Health:
+		return "Health"
+	case Trading:
+		return "Trading"
+	}
+	return "Unknown"
+}
+
+// Unit is how a metric's value should be read
+type Unit int
+
+const (
+	Ratio    Unit = iota
+	Percent       // finnhub already sends these multiplied by 100
+	Millions      // market cap, enterprise value
+	Price
+	Volume // millions of shares
+)
+
+// Metric describes one key of finnhub's basic financials
+type Metric struct {
+	Key      string
+	Label    string
+	Category Category
+	Unit     Unit
+}
+
+// Format renders v in the metric's unit
+func (m Metric) Format(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	switch m.Unit {
+	case Percent:
+		return fmt.Sprintf("%.2f%%", v)
+	case Millions:
+		return formatMillions(v)
+	case Price:
+		return fmt.Sprintf("%.2f", v)
+	case Volume:
+		return fmt.Sprintf("%.2fM", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+func formatMillions(v float64) string {
+	switch {
+	case math.Abs(v) >= 1e6:
+		return fmt.Sprintf("%.2fT", v/1e6)
+	case math.Abs(v) >= 1e3:
+		return fmt.Sprintf("%.2fB", v/1e3)
+	}
+	return fmt.Sprintf("%.2fM", v)
+}
+
+// Metrics is every metric the model knows, in panel order. finnhub sends
+// more than this, anything else still ends up in Fundamentals.Values
+var Metrics = []Metric{
+	{"marketCapitalization", "Market Cap", Valuation, Millions},
+	{"enterpriseValue", "Enterprise Value", Valuation, Millions},
+	{"peTTM", "P/E (TTM)", Valuation, Ratio},
+	{"peAnnual", "P/E (Annual)", Valuation, Ratio},
+	{"forwardPE", "Forward P/E", Valuation, Ratio},
+	{"pegTTM", "PEG (TTM)", Valuation, Ratio},
+	{"pbQuarterly", "P/B", Valuation, Ratio},
+	{"psTTM", "P/S (TTM)", Valuation, Ratio},
+	{"pfcfShareTTM", "P/FCF (TTM)", Valuation, Ratio},
+	{"evEbitdaTTM", "EV/EBITDA (TTM)", Valuation, Ratio},
+	{"epsTTM", "EPS (TTM)", Valuation, Price},
+	{"bookValuePerShareQuarterly", "Book Value/Share", Valuation, Price},
+
+	{"grossMarginTTM", "Gross Margin", Profitability, Percent},
+	{"operatingMarginTTM", "Operating Margin", Profitability, Percent},
+	{"pretaxMarginTTM", "Pretax Margin", Profitability, Percent},
+	{"netProfitMarginTTM", "Net Margin", Profitability, Percent},
+	{"roeTTM", "ROE (TTM)", Profitability, Percent},
+	{"roaTTM", "ROA (TTM)", Profitability, Percent},
+	{"roiTTM", "ROI (TTM)", Profitability, Percent},
+	{"grossMargin5Y", "Gross Margin 5Y", Profitability, Percent},
+
+	{"revenueGrowthTTMYoy", "Revenue Growth YoY", Growth, Percent},
+	{"revenueGrowthQuarterlyYoy", "Revenue Growth Qtr", Growth, Percent},
+	{"revenueGrowth3Y", "Revenue Growth 3Y", Growth, Percent},
+	{"revenueGrowth5Y", "Revenue Growth 5Y", Growth, Percent},
+	{"epsGrowthTTMYoy", "EPS Growth YoY", Growth, Percent},
+	{"epsGrowthQuarterlyYoy", "EPS Growth Qtr", Growth, Percent},
+	{"epsGrowth3Y", "EPS Growth 3Y", Growth, Percent},
+	{"epsGrowth5Y", "EPS Growth 5Y", Growth, Percent},
+
+	{"dividendYieldIndicatedAnnual", "Dividend Yield", Dividends, Percent},
+	{"currentDividendYieldTTM", "Dividend Yield (TTM)", Dividends, Percent},
+	{"dividendPerShareTTM", "Dividend/Share (TTM)", Dividends, Price},
+	{"payoutRatioTTM", "Payout Ratio", Dividends, Percent},
+	{"dividendGrowthRate5Y", "Dividend Growth 5Y", Dividends, Percent},
+
+	{"currentRatioQuarterly", "Current Ratio", Health, Ratio},
+	{"quickRatioQuarterly", "Quick Ratio", Health, Ratio},
+	{"totalDebt/totalEquityQuarterly", "Debt/Equity", Health, Ratio},
+	{"longTermDebt/equityQuarterly", "LT Debt/Equity", Health, Ratio},
+	{"netInterestCoverageTTM", "Interest Coverage", Health, Ratio},
+	{"assetTurnoverTTM", "Asset Turnover", Health, Ratio},
+
+	{"beta", "Beta", Trading, Ratio},
+	{"52WeekHigh", "52-Week High", Trading, Price},
+	{"52WeekLow", "52-Week Low", Trading, Price},
+	{"52WeekPriceReturnDaily", "52-Week Return", Trading, Percent},
+	{"26WeekPriceReturnDaily", "26-Week Return", Trading, Percent},
+	{"13WeekPriceReturnDaily", "13-Week Return", Trading, Percent},
+	{"10DayAverageTradingVolume", "10-Day Avg. Volume", Trading, Volume},
+	{"3MonthAverageTradingVolume", "3-Month Avg. Volume", Trading, Volume},
+}
+
+var byKey = func() map[string]Metric {
+	m := make(map[string]Metric, len(Metrics))
+	for _, metric := range Metrics {
+		m[metric.Key] = metric
+	}
+	return m
+}()
+
+// Lookup finds a known metric by its finnhub key
+func Lookup(key string) (Metric, bool) {
+	m, ok := byKey[key]
+	return m, ok
+}
+
+// InCategory lists the known metrics of c in panel order
+func InCategory(c Category) []Metric {
+	var metrics []Metric
+	for _, m := range Metrics {
+		if m.Category == c {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
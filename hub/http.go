@@ -0,0 +1,28 @@
+package hub
+
+import (
+	"net/http"
+
+	"github.com/Scrimzay/stockspider/eventpb"
+)
+
+// ServeQuotes writes the hub's quotes as eventpb JSON lines, the ones of
+// each ?symbol= (display or full) or every one without any
+func (h *Hub) ServeQuotes(w http.ResponseWriter, r *http.Request) {
+	quotes := h.Quotes()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := eventpb.NewEncoder(w, eventpb.JSON)
+
+	symbols := r.URL.Query()["symbol"]
+	if len(symbols) == 0 {
+		for _, q := range quotes {
+			enc.Encode(q)
+		}
+		return
+	}
+	for _, symbol := range symbols {
+		if q, ok := quotes[h.FullSymbol(symbol)]; ok {
+			enc.Encode(q)
+		}
+	}
+}
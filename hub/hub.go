@@ -0,0 +1,400 @@
+// Package hub is the data layer the front ends share. It takes trades,
+// quotes and news off the bus, polls finnhub for what only comes over
+// REST and keeps the latest of everything for the selected symbol, so
+// the raylib GUI and the terminal UI show the same thing from the same
+// place. Everything on a Hub is safe to call from any goroutine.
+package hub
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/analyst"
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/fundamentals"
+	"github.com/Scrimzay/stockspider/queue"
+	"github.com/Scrimzay/stockspider/store"
+	"github.com/Scrimzay/stockspider/symbolArray"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+	"github.com/anthdm/hollywood/actor"
+)
+
+// how many bars each live series keeps
+const maxBars = 1000
+
+// MaxTrades is how many trades are kept per symbol for the tape
+const MaxTrades = 100
+
+// how many trades the trade loop takes off the queue at once
+const tradeBatch = 512
+
+// how many headlines are kept per symbol
+const maxNews = 100
+
+type Hub struct {
+	// Symbols maps display names to full finnhub symbols, Order is the
+	// full symbols sorted and Exchanges the venues they trade on in
+	// calendar order. none of them change after New
+	Symbols   map[string]string
+	Order     []string
+	Exchanges []*calendar.Exchange
+
+	// Candles are the live bars of every symbol, backfilled on selection
+	Candles *candle.Aggregator
+
+	// set before Start, all optional
+	Ticks *store.TickStore
+	// QuoteHistory records every quote that comes in, for export
+	QuoteHistory *store.QuoteStore
+	Backfill     *backfill.Service
+	// OnTrade sees every trade after the candles, on the trade loop
+	OnTrade func(event.StockTrade)
+	// Holdings are extra full symbols to keep quotes for
+	Holdings func() []string
+
+	engine     *actor.Engine
+	client     *FH.DefaultApiService
+	log        *slog.Logger
+	tradeQueue *queue.Queue[event.StockTrade]
+	tape       *queue.Conflater[string, event.StockTrade] // trades not read yet, by lowercase symbol
+
+	mu           sync.RWMutex
+	selected     string
+	watched      []string                      // full symbols on screen besides the selected one, sorted
+	trades       map[string][]event.StockTrade // keyed by lowercase full symbol
+	quotes       map[string]event.Quote        // keyed by full symbol
+	marketStatus map[string]event.MarketStatus // keyed by finnhub exchange code
+	ratings      map[string]*analyst.Ratings   // keyed by full symbol
+	fundamentals map[string]*fundamentals.Fundamentals
+	peers        map[string][]string
+	news         map[string][]event.News // keyed by full symbol, "" is market news, newest first
+}
+
+// New is a hub reading e's bus. client is what the pollers ask, nil
+// leaves quotes to the bus and everything else empty
+func New(e *actor.Engine, client *FH.DefaultApiService, log *slog.Logger) *Hub {
+	order := make([]string, 0, len(symbolArray.Symbols))
+	for _, fullSymbol := range symbolArray.Symbols {
+		order = append(order, fullSymbol)
+	}
+	sort.Strings(order)
+
+	// collect the venues the symbol list trades on, in calendar order
+	present := make(map[*calendar.Exchange]bool)
+	for _, fullSymbol := range order {
+		present[calendar.For(event.Pair{Exchange: "finnhub", Symbol: fullSymbol})] = true
+	}
+	exchanges := make([]*calendar.Exchange, 0, len(present))
+	for _, exchange := range calendar.Exchanges {
+		if present[exchange] {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+
+	return &Hub{
+		Symbols:   symbolArray.Symbols,
+		Order:     order,
+		Exchanges: exchanges,
+		Candles:   candle.NewAggregator(maxBars),
+		engine:    e,
+		client:    client,
+		log:       log,
+		// the websocket never waits on the hub, if the trade loop falls
+		// this far behind the oldest trades go and /metrics counts them
+		tradeQueue:   queue.New[event.StockTrade]("trades", 1<<16, queue.DropOldest),
+		tape:         queue.NewConflater[string, event.StockTrade]("tape", MaxTrades),
+		trades:       make(map[string][]event.StockTrade),
+		quotes:       make(map[string]event.Quote),
+		marketStatus: make(map[string]event.MarketStatus),
+		ratings:      make(map[string]*analyst.Ratings),
+		fundamentals: make(map[string]*fundamentals.Fundamentals),
+		peers:        make(map[string][]string),
+		news:         make(map[string][]event.News),
+	}
+}
+
+// Start subscribes to the bus and starts the trade loop and the pollers
+func (h *Hub) Start() {
+	go h.tradeLoop()
+
+	bus.Func(h.engine, "trades", "trades.*", func(trade event.StockTrade) {
+		h.tradeQueue.Push(trade)
+	})
+	// the news consumer already dropped anything seen before
+	bus.Func(h.engine, "news", "news.*", h.addNews)
+	bus.Func(h.engine, "quotes", "quotes.*", h.setQuote)
+
+	if h.client == nil {
+		return
+	}
+	go h.pollQuotes()
+	go h.pollMarketStatus()
+	go h.pollRatings()
+	go h.pollFundamentals()
+}
+
+// Close stops the trade loop
+func (h *Hub) Close() {
+	h.tradeQueue.Close()
+}
+
+// every trade goes into the candles, the tick store and OnTrade, the
+// tape only needs what the next read shows
+func (h *Hub) tradeLoop() {
+	batch := make([]event.StockTrade, 0, tradeBatch)
+	for {
+		var ok bool
+		batch, ok = h.tradeQueue.PopBatch(batch[:0], tradeBatch)
+		if !ok {
+			return
+		}
+		for _, trade := range batch {
+			symbol := trade.Pair.Symbol
+			h.log.Debug("received trade", "symbol", symbol, "price", trade.Price, "qty", trade.Qty)
+
+			for _, bar := range h.Candles.AddTrade(trade) {
+				bus.Publish(h.engine, bus.Candles(bar.Timeframe, bar.Pair), bar)
+			}
+			if h.OnTrade != nil {
+				h.OnTrade(trade)
+			}
+			if h.Ticks != nil {
+				if err := h.Ticks.Append(trade); err != nil {
+					h.log.Error("storing trade", "symbol", symbol, "err", err)
+				}
+			}
+			h.tape.Put(strings.ToLower(symbol), trade)
+		}
+	}
+}
+
+// FullSymbol is the full finnhub symbol for a display name, symbols that
+// are already full come back as they are
+func (h *Hub) FullSymbol(symbol string) string {
+	if full, ok := h.Symbols[symbol]; ok {
+		return full
+	}
+	return symbol
+}
+
+// Selected is the symbol picked in the watchlist, "" for none
+func (h *Hub) Selected() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.selected
+}
+
+// SelectedPair is the selected symbol as the full finnhub pair, which is
+// what the calendar needs to tell crypto from stocks
+func (h *Hub) SelectedPair() event.Pair {
+	return event.Pair{Exchange: "finnhub", Symbol: h.FullSymbol(h.Selected())}
+}
+
+// Select picks symbol and tells the finnhub and news consumers, the tape
+// of the new symbol starts over and its history is backfilled at tf.
+// false when it was already picked
+func (h *Hub) Select(symbol string, tf candle.Timeframe) bool {
+	h.mu.Lock()
+	if symbol == h.selected {
+		h.mu.Unlock()
+		return false
+	}
+	h.log.Info("switching symbol", "from", h.selected, "to", symbol)
+	h.selected = symbol
+	// finnhub wants the full symbol, "ETH" means nothing to it
+	fullSymbol := h.FullSymbol(symbol)
+	delete(h.trades, strings.ToLower(fullSymbol))
+	h.mu.Unlock()
+
+	bus.Publish(h.engine, bus.Selected, event.Selected{
+		Pair: event.Pair{Exchange: "finnhub", Symbol: fullSymbol},
+	})
+	go h.BackfillSymbol(symbol, tf)
+	return true
+}
+
+// Watch says which full symbols views show besides the selected one, the
+// websocket streams their trades and the pollers keep their quotes,
+// ratings and fundamentals. only a change is passed on
+func (h *Hub) Watch(fullSymbols []string) {
+	selected := h.SelectedPair().Symbol
+	seen := make(map[string]bool, len(fullSymbols))
+	watched := make([]string, 0, len(fullSymbols))
+	for _, s := range fullSymbols {
+		if s != "" && s != selected && !seen[s] {
+			seen[s] = true
+			watched = append(watched, s)
+		}
+	}
+	sort.Strings(watched)
+
+	h.mu.Lock()
+	if slices.Equal(watched, h.watched) {
+		h.mu.Unlock()
+		return
+	}
+	h.watched = watched
+	h.mu.Unlock()
+
+	h.log.Info("watching symbols", "symbols", watched)
+	msg := event.Watched{Pairs: make([]event.Pair, len(watched))}
+	for i, s := range watched {
+		msg.Pairs[i] = event.Pair{Exchange: "finnhub", Symbol: s}
+	}
+	bus.Publish(h.engine, bus.Watched, msg)
+}
+
+// Watched are the full symbols passed to Watch, minus the selected one
+func (h *Hub) Watched() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return slices.Clone(h.watched)
+}
+
+// BackfillSymbol loads history for symbol so the chart isn't empty until
+// enough live trades come in
+func (h *Hub) BackfillSymbol(symbol string, tf candle.Timeframe) {
+	if h.Backfill == nil || symbol == "" {
+		return
+	}
+	pair := event.Pair{Exchange: "finnhub", Symbol: h.FullSymbol(symbol)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	gaps, err := h.Backfill.Backfill(ctx, h.Candles, pair, tf, time.Now())
+	if err != nil {
+		h.log.Error("backfill failed", "symbol", symbol, "timeframe", tf.Name, "err", err)
+		return
+	}
+	for _, gap := range gaps {
+		h.log.Warn("gap in bars", "symbol", symbol, "timeframe", tf.Name, "from", gap.From, "to", gap.To)
+	}
+}
+
+// Trades are the last MaxTrades trades of a full symbol, oldest first
+func (h *Hub) Trades(fullSymbol string) []event.StockTrade {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// move what came in since the last read onto the tape
+	for symbol, trades := range h.tape.Drain() {
+		all := append(h.trades[symbol], trades...)
+		if len(all) > MaxTrades {
+			all = all[len(all)-MaxTrades:]
+		}
+		h.trades[symbol] = all
+	}
+	return append([]event.StockTrade(nil), h.trades[strings.ToLower(fullSymbol)]...)
+}
+
+// Quote is the latest quote of a full symbol
+func (h *Hub) Quote(fullSymbol string) (event.Quote, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	q, ok := h.quotes[fullSymbol]
+	return q, ok
+}
+
+// Quotes is a copy of every quote, keyed by full symbol
+func (h *Hub) Quotes() map[string]event.Quote {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	quotes := make(map[string]event.Quote, len(h.quotes))
+	for k, v := range h.quotes {
+		quotes[k] = v
+	}
+	return quotes
+}
+
+func (h *Hub) setQuote(quote event.Quote) {
+	h.mu.Lock()
+	h.quotes[quote.Pair.Symbol] = quote
+	h.mu.Unlock()
+
+	if h.QuoteHistory != nil {
+		if err := h.QuoteHistory.Append(quote); err != nil {
+			h.log.Error("storing quote", "symbol", quote.Pair.Symbol, "err", err)
+		}
+	}
+}
+
+// MarketStatus is finnhub's status of an exchange code, when it has one
+func (h *Hub) MarketStatus(code string) (event.MarketStatus, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	s, ok := h.marketStatus[code]
+	return s, ok
+}
+
+// Ratings are the analyst ratings of a full symbol, nil until fetched
+func (h *Hub) Ratings(fullSymbol string) *analyst.Ratings {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.ratings[fullSymbol]
+}
+
+// Fundamentals of a full symbol, nil until fetched
+func (h *Hub) Fundamentals(fullSymbol string) *fundamentals.Fundamentals {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.fundamentals[fullSymbol]
+}
+
+// Peers of a full symbol and whether they've been fetched
+func (h *Hub) Peers(fullSymbol string) ([]string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	peers, ok := h.peers[fullSymbol]
+	return peers, ok
+}
+
+// Compare is fundamentals.Compare over what the hub has loaded
+func (h *Hub) Compare(symbols []string, keys ...string) fundamentals.Comparison {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return fundamentals.Compare(symbols, h.fundamentals, keys...)
+}
+
+// News are the headlines of a full symbol, "" for market news, newest first
+func (h *Hub) News(fullSymbol string) []event.News {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]event.News(nil), h.news[fullSymbol]...)
+}
+
+// addNews files item under its symbol, newest first
+func (h *Hub) addNews(item event.News) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := item.Pair.Symbol
+	items := append(h.news[key], item)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Unix > items[j].Unix
+	})
+	if len(items) > maxNews {
+		items = items[:maxNews]
+	}
+	h.news[key] = items
+}
@@ -0,0 +1,42 @@
+package hub
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/logging"
+)
+
+// quotes for holdings come in on the bus subscriber's goroutine while the
+// render loop values the portfolio, run with -race
+func TestQuotesWhileUpdating(t *testing.T) {
+	h := New(nil, nil, logging.Discard())
+	const symbols, updates = 8, 500
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < updates; i++ {
+			symbol := fmt.Sprintf("TEST:%d", i%symbols)
+			h.setQuote(event.Quote{Pair: event.Pair{Exchange: "finnhub", Symbol: symbol}, Current: float32(i)})
+		}
+	}()
+	for i := 0; i < updates; i++ {
+		quotes := h.Quotes()
+		// the copy is the caller's, writing to it can't touch the hub's
+		quotes["TEST:mine"] = event.Quote{}
+		h.Quote("TEST:0")
+	}
+	wg.Wait()
+
+	quotes := h.Quotes()
+	if len(quotes) != symbols {
+		t.Fatalf("%d quotes, want %d", len(quotes), symbols)
+	}
+	if q, ok := h.Quote(fmt.Sprintf("TEST:%d", (updates-1)%symbols)); !ok || q.Current != updates-1 {
+		t.Fatalf("last quote %+v %v, want the last update", q, ok)
+	}
+}
@@ -0,0 +1,221 @@
+package hub
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Scrimzay/stockspider/analyst"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/fundamentals"
+
+	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
+)
+
+// how often the selected symbol's quote is asked for
+const quoteInterval = 2 * time.Second
+
+// market status barely changes, no need to burn the rate limit on it
+const marketStatusInterval = 30 * time.Second
+
+// analysts update their ratings monthly, targets and changes a bit more often
+const ratingsTTL = time.Hour
+
+// fundamentals barely move during the day
+const fundamentalsTTL = 15 * time.Minute
+
+// pollQuotes publishes the selected symbol's quote, one watched symbol's
+// and one holding's per round on bus.Quotes, setQuote picks them up from
+// there like any other
+func (h *Hub) pollQuotes() {
+	holdingIndex, watchedIndex := 0, 0
+	for {
+		if h.Selected() != "" {
+			fullSymbol := h.SelectedPair().Symbol
+			quote, err := FetchQuote(h.client, fullSymbol)
+			if err != nil {
+				h.log.Error("fetching quote", "symbol", fullSymbol, "err", err)
+			} else {
+				bus.Publish(h.engine, bus.Quotes(quote.Pair), quote)
+			}
+		}
+
+		if watched := h.Watched(); len(watched) > 0 {
+			watchedIndex = (watchedIndex + 1) % len(watched)
+			fullSymbol := watched[watchedIndex]
+			quote, err := FetchQuote(h.client, fullSymbol)
+			if err != nil {
+				h.log.Error("fetching watched quote", "symbol", fullSymbol, "err", err)
+			} else {
+				bus.Publish(h.engine, bus.Quotes(quote.Pair), quote)
+			}
+		}
+
+		// refresh one portfolio holding per round so a big portfolio
+		// doesn't eat the rate limit
+		if h.Holdings != nil {
+			if holdings := h.Holdings(); len(holdings) > 0 {
+				holdingIndex = (holdingIndex + 1) % len(holdings)
+				fullSymbol := holdings[holdingIndex]
+				quote, err := FetchQuote(h.client, fullSymbol)
+				if err != nil {
+					h.log.Error("fetching holding quote", "symbol", fullSymbol, "err", err)
+				} else {
+					bus.Publish(h.engine, bus.Quotes(quote.Pair), quote)
+				}
+			}
+		}
+
+		time.Sleep(quoteInterval)
+	}
+}
+
+// pollMarketStatus asks finnhub about every venue in the symbol list.
+// crypto has no finnhub status, the calendar covers it.
+func (h *Hub) pollMarketStatus() {
+	for {
+		for _, exchange := range h.Exchanges {
+			if exchange.AlwaysOpen {
+				continue
+			}
+
+			res, _, err := h.client.MarketStatus(context.Background()).Exchange(exchange.Code).Execute()
+			if err != nil {
+				// keep whatever we had before, the calendar fills in until then
+				h.log.Warn("fetching market status", "exchange", exchange.Code, "err", err)
+				continue
+			}
+
+			h.mu.Lock()
+			h.marketStatus[exchange.Code] = event.MarketStatus{
+				Pair: event.Pair{
+					Exchange: "finnhub",
+					Symbol:   "MarketStatus",
+				},
+				Exchange: exchange.Code,
+				IsOpen:   res.GetIsOpen(),
+				Session:  res.GetSession(),
+				Holiday:  res.GetHoliday(),
+				Timezone: res.GetTimezone(),
+				Unix:     res.GetT(),
+			}
+			h.mu.Unlock()
+		}
+
+		time.Sleep(marketStatusInterval)
+	}
+}
+
+// pollRatings keeps ratings of the selected symbol loaded, then of the
+// watched ones, one fetch per round
+func (h *Hub) pollRatings() {
+	for {
+		pair, ok := h.nextStale(func(symbol string) bool {
+			return h.Ratings(symbol).Stale(time.Now(), ratingsTTL)
+		})
+		if ok {
+			ratings, err := analyst.Fetch(context.Background(), h.client, pair.Symbol, time.Now())
+			if err != nil {
+				// the target and changes need a paid plan, keep the trend anyway
+				h.log.Warn("fetching analyst ratings", "symbol", pair.Symbol, "err", err)
+				if len(ratings.Trends) == 0 {
+					// nothing came back at all, try again in a minute
+					ratings.Updated = time.Now().Add(time.Minute - ratingsTTL)
+				}
+			}
+			h.mu.Lock()
+			h.ratings[pair.Symbol] = ratings
+			h.mu.Unlock()
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// pollFundamentals keeps the selected symbol's fundamentals and peers
+// loaded, filling in one peer or watched symbol per round so the rate
+// limit survives
+func (h *Hub) pollFundamentals() {
+	fetch := func(symbol string) {
+		data, err := fundamentals.Fetch(context.Background(), h.client, symbol)
+		if err != nil {
+			h.log.Error("fetching fundamentals", "symbol", symbol, "err", err)
+			return
+		}
+		h.mu.Lock()
+		h.fundamentals[symbol] = data
+		h.mu.Unlock()
+	}
+
+	for {
+		now := time.Now()
+		stale := func(symbol string) bool {
+			return h.Fundamentals(symbol).Stale(now, fundamentalsTTL)
+		}
+		pair := h.SelectedPair()
+		selected := h.Selected() != "" && calendar.For(pair) != calendar.Crypto
+		peers, havePeers := h.Peers(pair.Symbol)
+		stalePeer := slices.IndexFunc(peers, stale)
+		switch {
+		case selected && stale(pair.Symbol):
+			fetch(pair.Symbol)
+		case selected && !havePeers:
+			peers, err := fundamentals.Peers(context.Background(), h.client, pair.Symbol)
+			if err != nil {
+				h.log.Error("fetching peers", "symbol", pair.Symbol, "err", err)
+			} else {
+				h.mu.Lock()
+				h.peers[pair.Symbol] = peers
+				h.mu.Unlock()
+			}
+		case selected && stalePeer >= 0:
+			fetch(peers[stalePeer])
+		default:
+			// the selected symbol is done, on to whatever else is on screen
+			if pair, ok := h.nextStale(stale); ok {
+				fetch(pair.Symbol)
+			}
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// nextStale is the selected symbol when stale says so, otherwise the first
+// watched one that is. crypto has no ratings or fundamentals
+func (h *Hub) nextStale(stale func(fullSymbol string) bool) (event.Pair, bool) {
+	symbols := h.Watched()
+	if h.Selected() != "" {
+		symbols = append([]string{h.SelectedPair().Symbol}, symbols...)
+	}
+	for _, symbol := range symbols {
+		pair := event.Pair{Exchange: "finnhub", Symbol: symbol}
+		if calendar.For(pair) != calendar.Crypto && stale(symbol) {
+			return pair, true
+		}
+	}
+	return event.Pair{}, false
+}
+
+// FetchQuote asks finnhub for the quote of a full symbol
+func FetchQuote(client *FH.DefaultApiService, fullSymbol string) (event.Quote, error) {
+	quote, _, err := client.Quote(context.Background()).Symbol(fullSymbol).Execute()
+	if err != nil {
+		return event.Quote{}, err
+	}
+	return event.Quote{
+		Pair: event.Pair{
+			Exchange: "finnhub",
+			Symbol:   fullSymbol,
+		},
+		Current:   quote.GetC(),
+		High:      quote.GetH(),
+		Low:       quote.GetL(),
+		Open:      quote.GetO(),
+		PrevClose: quote.GetPc(),
+		// the client's model drops finnhub's t, when we asked is the
+		// closest we have
+		Unix: time.Now().Unix(),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package hub
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+)
+
+// Status is whether exchange is open and in which session. finnhub's
+// status wins when we have one since it knows about unscheduled closures,
+// otherwise it falls back to the calendar.
+func (h *Hub) Status(exchange *calendar.Exchange, now time.Time) (isOpen bool, session string) {
+	isOpen = exchange.IsOpen(now)
+	session = exchange.SessionAt(now).String()
+	if status, ok := h.MarketStatus(exchange.Code); ok {
+		isOpen = status.IsOpen
+		if status.Session != "" {
+			session = status.Session
+		}
+	}
+	return isOpen, session
+}
+
+// MarketTimer is the countdown line for exchange, to the close while it's
+// open and to the next open while it isn't
+func MarketTimer(exchange *calendar.Exchange, now time.Time) (string, calendar.Session) {
+	session := exchange.SessionAt(now)
+	switch session {
+	case calendar.Continuous:
+		return fmt.Sprintf("%s market is open 24/7.", exchange.Name), session
+	case calendar.Regular:
+		timer := fmt.Sprintf("Time until close: %s", FormatCountdown(exchange.NextClose(now).Sub(now)))
+		if exchange.IsHalfDay(now) {
+			timer += " (early close)"
+		}
+		return timer, session
+	case calendar.PreMarket:
+		return fmt.Sprintf("Pre-market, opens in %s", FormatCountdown(exchange.NextOpen(now).Sub(now))), session
+	case calendar.AfterHours:
+		return fmt.Sprintf("After hours, opens in %s", FormatCountdown(exchange.NextOpen(now).Sub(now))), session
+	}
+	if holiday, ok := exchange.Holiday(now); ok {
+		return fmt.Sprintf("Closed for %s", holiday), session
+	}
+	return fmt.Sprintf("Closed, opens in %s", FormatCountdown(exchange.NextOpen(now).Sub(now))), session
+}
+
+// FormatCountdown formats a countdown as hh:mm:ss, with days in front once
+// it passes a day
+func FormatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	days := int(d.Hours()) / 24
+	clock := fmt.Sprintf("%02d:%02d:%02d",
+		int(d.Hours())%24,
+		int(d.Minutes())%60,
+		int(d.Seconds())%60,
+	)
+	if days > 0 {
+		return fmt.Sprintf("%dd %s", days, clock)
+	}
+	return clock
+}
@@ -0,0 +1,404 @@
+// Package importer reads historical trades and bars from CSV files made
+// elsewhere, Yahoo, Stooq, exchange dumps or stockspider's own exports,
+// so they can go into the tick store. Which column is which is a Mapping,
+// Presets has the common ones.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type Kind string
+
+const (
+	Trades  Kind = "trades"
+	Candles Kind = "candles"
+)
+
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(s)); k {
+	case Trades, Candles:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown import %q, want trades or candles", s)
+}
+
+// Mapping names the column of each field, by header name, or by 0-based
+// index when the file has no header. empty fields aren't in the file
+type Mapping struct {
+	// Time is the timestamp, or Date and Clock when it's split in two
+	Time  string
+	Date  string
+	Clock string
+	// Symbol, when set, says whose row it is, otherwise it's Config.Pair's
+	Symbol string
+
+	// trades
+	Price string
+	Qty   string
+	Side  string
+
+	// candles
+	Open   string
+	High   string
+	Low    string
+	Close  string
+	Volume string
+}
+
+// Set sets field to column, field is a Mapping field's name in lowercase
+func (m *Mapping) Set(field, column string) error {
+	fields := map[string]*string{
+		"time": &m.Time, "date": &m.Date, "clock": &m.Clock, "symbol": &m.Symbol,
+		"price": &m.Price, "qty": &m.Qty, "side": &m.Side,
+		"open": &m.Open, "high": &m.High, "low": &m.Low, "close": &m.Close, "volume": &m.Volume,
+	}
+	p, ok := fields[strings.ToLower(field)]
+	if !ok {
+		return fmt.Errorf("unknown field %q, want time, date, clock, symbol, price, qty, side, open, high, low, close or volume", field)
+	}
+	*p = column
+	return nil
+}
+
+type Config struct {
+	Kind    Kind
+	Mapping Mapping
+	// Pair is whose rows they are when the mapping has no symbol column
+	Pair event.Pair
+	// NoHeader says the first row is data, columns are then indexes
+	NoHeader bool
+	Comma    rune
+	// TimeFormat is a Go time layout, "unix" or "unixms". empty guesses
+	// from the common layouts and takes plain numbers as unix seconds or
+	// millis by their size
+	TimeFormat string
+	// Location is the timezone of times without an offset. dates without
+	// a time are days, not instants, and always taken as UTC days
+	Location *time.Location
+	// Timeframe of the bars, candles only
+	Timeframe candle.Timeframe
+	// BuyIsMaker says the side column is true when the buyer was the
+	// maker, which means the trade was a sell, like binance's dumps
+	BuyIsMaker bool
+}
+
+// Result is what Read made of a file
+type Result struct {
+	Trades []event.StockTrade
+	Bars   []event.Candle
+	Rows   int
+	// Duplicates are rows repeating an earlier row of the file
+	Duplicates int
+	// Skipped rows couldn't be read, Errors says why for the first few
+	Skipped int
+	Errors  []string
+}
+
+// how many bad rows Result.Errors describes
+const maxErrors = 10
+
+func (res *Result) skip(line int, err error) {
+	res.Skipped++
+	if len(res.Errors) < maxErrors {
+		res.Errors = append(res.Errors, fmt.Sprintf("line %d: %v", line, err))
+	}
+}
+
+// Read reads every row of r. bad rows are skipped and counted, Read only
+// fails when the file itself can't be read or doesn't fit the mapping
+func Read(r io.Reader, cfg Config) (*Result, error) {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Kind == Candles && cfg.Timeframe.Duration == 0 {
+		return nil, errors.New("importing candles needs their timeframe")
+	}
+
+	cr := csv.NewReader(r)
+	if cfg.Comma != 0 {
+		cr.Comma = cfg.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	var header []string
+	line := 0
+	if !cfg.NoHeader {
+		row, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading header: %w", err)
+		}
+		line++
+		header = append([]string(nil), row...)
+		// excel likes to start files with a byte order mark
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		}
+	}
+	cols, err := resolve(cfg, header)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	tradeSeen := make(map[event.StockTrade]bool)
+	barSeen := make(map[event.Pair]map[int64]bool)
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return res, nil
+		}
+		line++
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				res.Rows++
+				res.skip(line, err)
+				continue
+			}
+			return nil, err
+		}
+		res.Rows++
+
+		switch cfg.Kind {
+		case Trades:
+			trade, err := cols.trade(row, cfg)
+			if err != nil {
+				res.skip(line, err)
+				continue
+			}
+			if tradeSeen[trade] {
+				res.Duplicates++
+				continue
+			}
+			tradeSeen[trade] = true
+			res.Trades = append(res.Trades, trade)
+		case Candles:
+			bar, err := cols.bar(row, cfg)
+			if err != nil {
+				res.skip(line, err)
+				continue
+			}
+			if barSeen[bar.Pair] == nil {
+				barSeen[bar.Pair] = make(map[int64]bool)
+			}
+			if barSeen[bar.Pair][bar.Unix] {
+				res.Duplicates++
+				continue
+			}
+			barSeen[bar.Pair][bar.Unix] = true
+			res.Bars = append(res.Bars, bar)
+		}
+	}
+}
+
+// columns are the mapping's fields as row indexes, -1 for not in the file
+type columns struct {
+	time, date, clock, symbol      int
+	price, qty, side               int
+	open, high, low, close, volume int
+}
+
+func resolve(cfg Config, header []string) (columns, error) {
+	index := func(field, name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		if header == nil {
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 {
+				return -1, fmt.Errorf("%s: without a header columns are 0-based indexes, not %q", field, name)
+			}
+			return i, nil
+		}
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("%s: no column %q in the header %q", field, name, strings.Join(header, ","))
+	}
+
+	m := cfg.Mapping
+	c := columns{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
+	var errs []error
+	for _, f := range []struct {
+		kind        Kind // "" for both
+		field, name string
+		i           *int
+	}{
+		{"", "time", m.Time, &c.time}, {"", "date", m.Date, &c.date}, {"", "clock", m.Clock, &c.clock}, {"", "symbol", m.Symbol, &c.symbol},
+		{Trades, "price", m.Price, &c.price}, {Trades, "qty", m.Qty, &c.qty}, {Trades, "side", m.Side, &c.side},
+		{Candles, "open", m.Open, &c.open}, {Candles, "high", m.High, &c.high}, {Candles, "low", m.Low, &c.low},
+		{Candles, "close", m.Close, &c.close}, {Candles, "volume", m.Volume, &c.volume},
+	} {
+		// a mapping can cover both kinds, only this one's columns have to be there
+		if f.kind != "" && f.kind != cfg.Kind {
+			continue
+		}
+		i, err := index(f.field, f.name)
+		errs = append(errs, err)
+		*f.i = i
+	}
+	if err := errors.Join(errs...); err != nil {
+		return c, err
+	}
+
+	if c.time < 0 && c.date < 0 {
+		return c, errors.New("the mapping needs a time or a date column")
+	}
+	if c.symbol < 0 && cfg.Pair.Symbol == "" {
+		return c, errors.New("no symbol column, say whose rows they are")
+	}
+	switch cfg.Kind {
+	case Trades:
+		if c.price < 0 {
+			return c, errors.New("trades need a price column")
+		}
+	case Candles:
+		if c.close < 0 {
+			return c, errors.New("candles need at least a close column")
+		}
+	default:
+		return c, fmt.Errorf("unknown import %q", cfg.Kind)
+	}
+	return c, nil
+}
+
+func field(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func (c columns) pair(row []string, cfg Config) (event.Pair, error) {
+	if c.symbol < 0 {
+		return cfg.Pair, nil
+	}
+	symbol := field(row, c.symbol)
+	if symbol == "" {
+		return event.Pair{}, errors.New("no symbol")
+	}
+	return event.Pair{Exchange: cfg.Pair.Exchange, Symbol: strings.ToUpper(symbol)}, nil
+}
+
+func (c columns) when(row []string, cfg Config) (time.Time, error) {
+	if c.time >= 0 {
+		return parseTime(field(row, c.time), cfg)
+	}
+	s := field(row, c.date)
+	if clock := field(row, c.clock); clock != "" {
+		s += " " + clock
+	}
+	return parseTime(s, cfg)
+}
+
+func (c columns) trade(row []string, cfg Config) (event.StockTrade, error) {
+	pair, err := c.pair(row, cfg)
+	if err != nil {
+		return event.StockTrade{}, err
+	}
+	t, err := c.when(row, cfg)
+	if err != nil {
+		return event.StockTrade{}, err
+	}
+	price, err := number(field(row, c.price), "price")
+	if err != nil {
+		return event.StockTrade{}, err
+	}
+	if price <= 0 {
+		return event.StockTrade{}, fmt.Errorf("price %v isn't positive", price)
+	}
+	qty := 0.0
+	if c.qty >= 0 {
+		if qty, err = number(field(row, c.qty), "qty"); err != nil {
+			return event.StockTrade{}, err
+		}
+	}
+	isBuy := false
+	if c.side >= 0 {
+		if isBuy, err = parseSide(field(row, c.side)); err != nil {
+			return event.StockTrade{}, err
+		}
+		if cfg.BuyIsMaker {
+			isBuy = !isBuy
+		}
+	}
+	return event.StockTrade{Pair: pair, Price: price, Qty: qty, IsBuy: isBuy, Unix: t.UnixMilli()}, nil
+}
+
+func (c columns) bar(row []string, cfg Config) (event.Candle, error) {
+	pair, err := c.pair(row, cfg)
+	if err != nil {
+		return event.Candle{}, err
+	}
+	t, err := c.when(row, cfg)
+	if err != nil {
+		return event.Candle{}, err
+	}
+	closePrice, err := number(field(row, c.close), "close")
+	if err != nil {
+		return event.Candle{}, err
+	}
+	if closePrice <= 0 {
+		// yahoo writes "null" rows for days without data, stooq zeros
+		return event.Candle{}, fmt.Errorf("close %v isn't positive", closePrice)
+	}
+	bar := event.Candle{
+		Pair:      pair,
+		Timeframe: cfg.Timeframe.Name,
+		Unix:      cfg.Timeframe.Bucket(t.Unix()),
+		Open:      closePrice,
+		High:      closePrice,
+		Low:       closePrice,
+		Close:     closePrice,
+	}
+	// files with only a close are lines, not bars, the rest defaults to it
+	for _, f := range []struct {
+		i    int
+		name string
+		v    *float64
+	}{{c.open, "open", &bar.Open}, {c.high, "high", &bar.High}, {c.low, "low", &bar.Low}, {c.volume, "volume", &bar.Volume}} {
+		if f.i < 0 {
+			continue
+		}
+		if *f.v, err = number(field(row, f.i), f.name); err != nil {
+			return event.Candle{}, err
+		}
+	}
+	if bar.High < bar.Low {
+		return event.Candle{}, fmt.Errorf("high %v is below low %v", bar.High, bar.Low)
+	}
+	return bar, nil
+}
+
+func number(s, name string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%s %q isn't a number", name, s)
+	}
+	return v, nil
+}
+
+func parseSide(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "buy", "b", "bid", "true", "1":
+		return true, nil
+	case "sell", "s", "ask", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("side %q isn't buy or sell", s)
+}
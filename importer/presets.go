@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Preset is the layout of a common kind of file
+type Preset struct {
+	Help       string
+	Mapping    Mapping
+	NoHeader   bool
+	TimeFormat string
+	// Location is the zone the source writes its times in, "" for UTC or
+	// times with an offset
+	Location   string
+	BuyIsMaker bool
+}
+
+var Presets = map[string]Preset{
+	// stockspider's own export, trades and candles
+	"stockspider": {
+		Help: "stockspider export csv, trades or candles",
+		Mapping: Mapping{
+			Time: "time", Symbol: "symbol",
+			Price: "price", Qty: "qty", Side: "side",
+			Open: "open", High: "high", Low: "low", Close: "close", Volume: "volume",
+		},
+	},
+	// Date,Open,High,Low,Close,Adj Close,Volume, prices split-adjusted.
+	// intraday downloads call the column Datetime and carry an offset
+	"yahoo": {
+		Help:    "Yahoo Finance history download, daily candles",
+		Mapping: Mapping{Time: "Date", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume"},
+	},
+	"stooq": {
+		Help:    "Stooq daily download, candles",
+		Mapping: Mapping{Date: "Date", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume"},
+	},
+	// stooq's intraday files split date and time and are in Warsaw time
+	"stooq-intraday": {
+		Help:     "Stooq intraday download, candles, times in Europe/Warsaw",
+		Mapping:  Mapping{Date: "Date", Clock: "Time", Open: "Open", High: "High", Low: "Low", Close: "Close", Volume: "Volume"},
+		Location: "Europe/Warsaw",
+	},
+	// data.binance.vision trades: id,price,qty,quote_qty,time,is_buyer_maker,is_best_match
+	"binance-trades": {
+		Help:       "Binance public trades dump, no header",
+		Mapping:    Mapping{Time: "4", Price: "1", Qty: "2", Side: "5"},
+		NoHeader:   true,
+		BuyIsMaker: true,
+	},
+	// data.binance.vision klines: open_time,open,high,low,close,volume,close_time,...
+	"binance-klines": {
+		Help:     "Binance public klines dump, candles, no header",
+		Mapping:  Mapping{Time: "0", Open: "1", High: "2", Low: "3", Close: "4", Volume: "5"},
+		NoHeader: true,
+	},
+}
+
+// PresetNames are the presets, sorted
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply sets cfg up for the preset called name
+func Apply(cfg *Config, name string) error {
+	p, ok := Presets[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown preset %q, have %s", name, strings.Join(PresetNames(), ", "))
+	}
+	cfg.Mapping = p.Mapping
+	cfg.NoHeader = p.NoHeader
+	cfg.TimeFormat = p.TimeFormat
+	cfg.BuyIsMaker = p.BuyIsMaker
+	if p.Location != "" {
+		loc, err := time.LoadLocation(p.Location)
+		if err != nil {
+			return err
+		}
+		cfg.Location = loc
+	}
+	return nil
+}
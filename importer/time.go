@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layouts with an offset, they say when they are on their own
+var zonedLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05-0700",
+}
+
+// layouts without one, Config.Location says where they are
+var localLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"20060102 150405",
+	"20060102 15:04:05",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+}
+
+// dates alone
+var dateLayouts = []string{
+	"2006-01-02",
+	"20060102",
+	"01/02/2006",
+	"2006/01/02",
+}
+
+// parseTime reads s the way cfg says, see Config.TimeFormat
+func parseTime(s string, cfg Config) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("no time")
+	}
+	switch cfg.TimeFormat {
+	case "":
+	case "unix", "unixms":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("time %q isn't a number", s)
+		}
+		if cfg.TimeFormat == "unixms" {
+			return time.UnixMilli(int64(n)), nil
+		}
+		return time.Unix(0, int64(n*1e9)), nil
+	default:
+		return parseLayout(s, cfg.TimeFormat, cfg.Location)
+	}
+
+	// plain numbers are unix times, anything past 1e11 seconds (year 5138)
+	// must be millis and past 1e14 micros, binance switched to those
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && len(s) > 8 {
+		switch {
+		case n > 1e14:
+			return time.UnixMicro(n), nil
+		case n > 1e11:
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, cfg.Location); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time, set the time format", s)
+}
+
+// parseLayout reads s in a layout given by hand. layouts without an hour
+// are dates and taken as UTC days like the guessed ones, layouts with an
+// offset bring their own zone
+func parseLayout(s, layout string, loc *time.Location) (time.Time, error) {
+	if !strings.Contains(layout, "15") && !strings.Contains(layout, "3") {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time %q doesn't fit %q", s, layout)
+	}
+	return t, nil
+}
@@ -0,0 +1,162 @@
+// Package keys maps keyboard chords to the GUI's actions. Bindings live
+// in a JSON file of action to chords, {"palette": ["ctrl+k"]}, anything in
+// it replaces the default for that action so keys can be rebound without
+// a rebuild. It knows nothing about raylib, key names are for main to
+// turn into key codes.
+package keys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Chord is a key with the modifiers that have to be held for it
+type Chord struct {
+	Ctrl  bool
+	Shift bool
+	Alt   bool
+	// Key is the key's lowercase name: "k", "1", "f2", "up", "pagedown"...
+	Key string
+}
+
+// Parse reads "ctrl+shift+k" style chords, modifiers first
+func Parse(s string) (Chord, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "+")
+	var c Chord
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.TrimSpace(mod) {
+		case "ctrl", "control", "cmd":
+			c.Ctrl = true
+		case "shift":
+			c.Shift = true
+		case "alt", "option":
+			c.Alt = true
+		default:
+			return Chord{}, fmt.Errorf("%q: unknown modifier %q, want ctrl, shift or alt", s, mod)
+		}
+	}
+	c.Key = strings.TrimSpace(parts[len(parts)-1])
+	if c.Key == "" {
+		return Chord{}, fmt.Errorf("%q has no key", s)
+	}
+	return c, nil
+}
+
+func (c Chord) String() string {
+	var b strings.Builder
+	for _, mod := range []struct {
+		on   bool
+		name string
+	}{{c.Ctrl, "ctrl+"}, {c.Shift, "shift+"}, {c.Alt, "alt+"}} {
+		if mod.on {
+			b.WriteString(mod.name)
+		}
+	}
+	b.WriteString(c.Key)
+	return b.String()
+}
+
+// Bindings are the chords of each action, any of them sets it off
+type Bindings map[string][]Chord
+
+// Must builds bindings from chord strings, for defaults known to parse
+func Must(chords map[string][]string) Bindings {
+	b, err := parse(chords)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func parse(chords map[string][]string) (Bindings, error) {
+	b := make(Bindings, len(chords))
+	var errs []error
+	for action, list := range chords {
+		for _, s := range list {
+			c, err := Parse(s)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", action, err))
+				continue
+			}
+			b[action] = append(b[action], c)
+		}
+		if len(list) == 0 {
+			// an empty list unbinds the action
+			b[action] = []Chord{}
+		}
+	}
+	return b, errors.Join(errs...)
+}
+
+// Help is how the first chord of action reads, "" when it has none
+func (b Bindings) Help(action string) string {
+	if len(b[action]) == 0 {
+		return ""
+	}
+	return b[action][0].String()
+}
+
+// Load is defaults with whatever the file at path rebinds. a missing file
+// gets the defaults written to it so there's something to edit. chords
+// that don't parse are left out and returned as the error, the rest
+// still load
+func Load(path string, defaults Bindings) (Bindings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return defaults, Save(path, defaults)
+	}
+	if err != nil {
+		return defaults, err
+	}
+
+	var chords map[string][]string
+	if err := json.Unmarshal(data, &chords); err != nil {
+		return defaults, fmt.Errorf("reading %s: %w", path, err)
+	}
+	overrides, err := parse(chords)
+	b := make(Bindings, len(defaults)+len(overrides))
+	for action, list := range defaults {
+		b[action] = list
+	}
+	for action, list := range overrides {
+		b[action] = list
+	}
+	return b, err
+}
+
+// Save writes b to path, one action per line in name order
+func Save(path string, b Bindings) error {
+	actions := make([]string, 0, len(b))
+	for action := range b {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	var out strings.Builder
+	out.WriteString("{\n")
+	for i, action := range actions {
+		chords := make([]string, len(b[action]))
+		for j, c := range b[action] {
+			chords[j] = c.String()
+		}
+		name, _ := json.Marshal(action)
+		list, _ := json.Marshal(chords)
+		fmt.Fprintf(&out, "  %s: %s", name, list)
+		if i < len(actions)-1 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("}\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(out.String()), 0644)
+}
@@ -0,0 +1,189 @@
+// Package layout arranges the GUI's panels: where they are, how big,
+// which is on top, which are minimized and which window edges they're
+// docked to. It knows nothing about raylib, main feeds it the mouse and
+// draws what it says. Layouts are saved by name as JSON together with the
+// window size.
+package layout
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TitleHeight is the height of a panel's title bar, it's where panels are
+// dragged from and all that's left of a minimized one
+const TitleHeight = 24
+
+// the smallest a panel can be resized to
+const (
+	MinWidth  = 120
+	MinHeight = 60
+)
+
+type Rect struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	W float32 `json:"w"`
+	H float32 `json:"h"`
+}
+
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && x <= r.X+r.W && y >= r.Y && y <= r.Y+r.H
+}
+
+// Edge is a set of window edges
+type Edge uint8
+
+const (
+	Left Edge = 1 << iota
+	Right
+	Top
+	Bottom
+)
+
+type Panel struct {
+	// ID tells instances of the same kind apart, "chart", "chart-2"...
+	ID string `json:"id"`
+	// Kind is what the panel shows, main knows what to draw for each
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	Rect
+	Minimized bool `json:"minimized,omitempty"`
+	// Dock are the edges of the work area the panel sticks to when the
+	// window is resized, set when it's dropped against them
+	Dock Edge `json:"dock,omitempty"`
+
+	// Symbol pins the panel to a full symbol, "" follows its link group
+	Symbol string `json:"symbol,omitempty"`
+	// Link is the group whose symbol the panel shows and sets, "" is the
+	// main selection
+	Link string `json:"link,omitempty"`
+	// GridSize and Tiles are a grid panel's tiles per side and their
+	// symbols, row by row
+	GridSize int      `json:"grid_size,omitempty"`
+	Tiles    []string `json:"tiles,omitempty"`
+}
+
+// Links are the groups a panel can link into, in the order the link
+// button goes through them
+var Links = []string{"red", "green", "blue", "yellow"}
+
+// NextLink is the group after link, "" after the last one
+func NextLink(link string) string {
+	for i, l := range Links {
+		if l == link {
+			if i+1 < len(Links) {
+				return Links[i+1]
+			}
+			return ""
+		}
+	}
+	return Links[0]
+}
+
+// Bounds is what the panel covers on screen, just the title bar when
+// it's minimized
+func (p *Panel) Bounds() Rect {
+	if p.Minimized {
+		return Rect{p.X, p.Y, p.W, TitleHeight}
+	}
+	return p.Rect
+}
+
+// Body is the panel below the title bar
+func (p *Panel) Body() Rect {
+	return Rect{p.X, p.Y + TitleHeight, p.W, p.H - TitleHeight}
+}
+
+// title bar buttons, from the right edge
+func (p *Panel) CloseButton() Rect {
+	return Rect{p.X + p.W - 20, p.Y + 4, 16, 16}
+}
+
+func (p *Panel) MinimizeButton() Rect {
+	return Rect{p.X + p.W - 40, p.Y + 4, 16, 16}
+}
+
+func (p *Panel) PinButton() Rect {
+	return Rect{p.X + p.W - 60, p.Y + 4, 16, 16}
+}
+
+func (p *Panel) LinkButton() Rect {
+	return Rect{p.X + p.W - 80, p.Y + 4, 16, 16}
+}
+
+// TitleButton is a title bar button the layout leaves to main, it closes
+// and minimizes panels itself
+type TitleButton int
+
+const (
+	NoButton TitleButton = iota
+	Pin
+	Link
+)
+
+type Layout struct {
+	Name string `json:"name"`
+	// Width and Height are the window size the layout was saved with
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Panels go back to front, the last one is drawn on top
+	Panels []*Panel `json:"panels"`
+	// Links are the symbol of each link group
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// Find is the panel with id, nil if there's none
+func (l *Layout) Find(id string) *Panel {
+	for _, p := range l.Panels {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// At is the topmost panel at x, y
+func (l *Layout) At(x, y float32) *Panel {
+	for i := len(l.Panels) - 1; i >= 0; i-- {
+		if l.Panels[i].Bounds().Contains(x, y) {
+			return l.Panels[i]
+		}
+	}
+	return nil
+}
+
+// Raise puts p on top
+func (l *Layout) Raise(p *Panel) {
+	for i, q := range l.Panels {
+		if q == p {
+			copy(l.Panels[i:], l.Panels[i+1:])
+			l.Panels[len(l.Panels)-1] = p
+			return
+		}
+	}
+}
+
+// Add puts a new panel of kind on top, with an ID no other panel has
+func (l *Layout) Add(kind, title string, r Rect) *Panel {
+	id := kind
+	for n := 2; l.Find(id) != nil; n++ {
+		id = kind + "-" + strconv.Itoa(n)
+	}
+	if id != kind {
+		title = fmt.Sprintf("%s (%s)", title, id[len(kind)+1:])
+	}
+	p := &Panel{ID: id, Kind: kind, Title: title, Rect: r}
+	l.Panels = append(l.Panels, p)
+	return p
+}
+
+// Remove closes p
+func (l *Layout) Remove(p *Panel) {
+	for i, q := range l.Panels {
+		if q == p {
+			l.Panels = append(l.Panels[:i], l.Panels[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package layout
+
+import "math"
+
+// Input is the mouse as of this frame
+type Input struct {
+	X, Y     float32
+	Pressed  bool // left button went down this frame
+	Down     bool
+	Released bool
+}
+
+type mode int
+
+const (
+	idle mode = iota
+	moving
+	resizing
+)
+
+// Manager moves, resizes, snaps, docks, raises, minimizes and closes the
+// panels of a layout as the mouse says
+type Manager struct {
+	Layout *Layout
+	// Area is where panels live, the window minus the header
+	Area Rect
+	// Grid is the grid panels snap to, 0 for none
+	Grid float32
+	// SnapDistance is how close an edge has to come to snap
+	SnapDistance float32
+
+	// Hover is the topmost panel under the mouse, the only one that should
+	// take input this frame
+	Hover *Panel
+	// Clicked is the panel whose body or one of main's title buttons was
+	// clicked this frame, and where
+	Clicked        *Panel
+	ClickX, ClickY float32
+	Button         TitleButton
+
+	mode    mode
+	active  *Panel
+	resize  Edge // which edges a resize moves
+	offsetX float32
+	offsetY float32
+}
+
+func NewManager(l *Layout, area Rect) *Manager {
+	return &Manager{Layout: l, Area: area, Grid: 10, SnapDistance: 8}
+}
+
+// how far from a panel's edge a press starts a resize
+const handle = 6
+
+// Busy is true while a panel is being moved or resized
+func (m *Manager) Busy() bool {
+	return m.mode != idle
+}
+
+// Update takes this frame's mouse. blocked means something drawn over
+// the panels, a menu, has the mouse
+func (m *Manager) Update(in Input, blocked bool) {
+	m.Clicked, m.Button = nil, NoButton
+	m.Hover = nil
+	if !blocked || m.mode != idle {
+		m.Hover = m.Layout.At(in.X, in.Y)
+	}
+
+	switch m.mode {
+	case moving:
+		r := m.active.Rect
+		r.X, r.Y = in.X-m.offsetX, in.Y-m.offsetY
+		m.active.Rect = m.snapMove(m.active, r)
+		m.Hover = m.active
+	case resizing:
+		m.active.Rect = m.snapResize(m.active, in.X, in.Y)
+		m.Hover = m.active
+	}
+
+	if in.Released && m.mode != idle {
+		if m.mode == moving {
+			m.drop(m.active, in)
+		}
+		m.active.Dock = m.docked(m.active)
+		m.mode, m.active = idle, nil
+		return
+	}
+	if !in.Pressed || m.Hover == nil || blocked {
+		return
+	}
+
+	p := m.Hover
+	m.Layout.Raise(p)
+	switch {
+	case p.CloseButton().Contains(in.X, in.Y):
+		m.Layout.Remove(p)
+		m.Hover = nil
+	case p.MinimizeButton().Contains(in.X, in.Y):
+		p.Minimized = !p.Minimized
+	case p.PinButton().Contains(in.X, in.Y):
+		m.Clicked, m.Button = p, Pin
+	case p.LinkButton().Contains(in.X, in.Y):
+		m.Clicked, m.Button = p, Link
+	case !p.Minimized && m.edgeAt(p, in.X, in.Y) != 0:
+		m.mode, m.active = resizing, p
+		m.resize = m.edgeAt(p, in.X, in.Y)
+	case in.Y <= p.Y+TitleHeight:
+		m.mode, m.active = moving, p
+		m.offsetX, m.offsetY = in.X-p.X, in.Y-p.Y
+	default:
+		m.Clicked = p
+		m.ClickX, m.ClickY = in.X-p.X, in.Y-p.Y
+	}
+}
+
+// edgeAt are the edges of p the point is on, for resizing. the title bar
+// only resizes from its sides so it can still be dragged
+func (m *Manager) edgeAt(p *Panel, x, y float32) Edge {
+	var e Edge
+	if x >= p.X+p.W-handle {
+		e |= Right
+	} else if x <= p.X+handle {
+		e |= Left
+	}
+	if y >= p.Y+p.H-handle {
+		e |= Bottom
+	}
+	return e
+}
+
+// Cursor is which edges the mouse would resize, to pick the cursor shape
+func (m *Manager) Cursor(x, y float32) Edge {
+	if m.mode == resizing {
+		return m.resize
+	}
+	if m.mode != idle || m.Hover == nil || m.Hover.Minimized {
+		return 0
+	}
+	return m.edgeAt(m.Hover, x, y)
+}
+
+// snap moves v onto the closest target within the snap distance
+func (m *Manager) snap(v float32, targets []float32) float32 {
+	best, bestDist := v, m.SnapDistance+1
+	for _, t := range targets {
+		if d := float32(math.Abs(float64(v - t))); d <= m.SnapDistance && d < bestDist {
+			best, bestDist = t, d
+		}
+	}
+	if bestDist <= m.SnapDistance {
+		return best
+	}
+	if m.Grid > 0 {
+		if g := float32(math.Round(float64(v/m.Grid))) * m.Grid; float32(math.Abs(float64(v-g))) <= m.SnapDistance/2 {
+			return g
+		}
+	}
+	return v
+}
+
+// edges are the x and y edges of the area and every other panel
+func (m *Manager) edges(p *Panel) (xs, ys []float32) {
+	xs = []float32{m.Area.X, m.Area.X + m.Area.W}
+	ys = []float32{m.Area.Y, m.Area.Y + m.Area.H}
+	for _, q := range m.Layout.Panels {
+		if q == p {
+			continue
+		}
+		b := q.Bounds()
+		xs = append(xs, b.X, b.X+b.W)
+		ys = append(ys, b.Y, b.Y+b.H)
+	}
+	return xs, ys
+}
+
+func (m *Manager) snapMove(p *Panel, r Rect) Rect {
+	xs, ys := m.edges(p)
+	h := p.Bounds().H
+	// whichever of the two edges snaps, the left/top one wins a tie
+	if x := m.snap(r.X, xs); x != r.X {
+		r.X = x
+	} else if x := m.snap(r.X+r.W, xs); x != r.X+r.W {
+		r.X = x - r.W
+	}
+	if y := m.snap(r.Y, ys); y != r.Y {
+		r.Y = y
+	} else if y := m.snap(r.Y+h, ys); y != r.Y+h {
+		r.Y = y - h
+	}
+	return m.clamp(r, h)
+}
+
+func (m *Manager) snapResize(p *Panel, x, y float32) Rect {
+	xs, ys := m.edges(p)
+	r := p.Rect
+	if m.resize&Right != 0 {
+		r.W = max(MinWidth, m.snap(min(x, m.Area.X+m.Area.W), xs)-r.X)
+	}
+	if m.resize&Left != 0 {
+		right := r.X + r.W
+		r.X = min(right-MinWidth, m.snap(max(x, m.Area.X), xs))
+		r.W = right - r.X
+	}
+	if m.resize&Bottom != 0 {
+		r.H = max(MinHeight, m.snap(min(y, m.Area.Y+m.Area.H), ys)-r.Y)
+	}
+	return r
+}
+
+// clamp keeps a panel h tall inside the area
+func (m *Manager) clamp(r Rect, h float32) Rect {
+	r.X = max(m.Area.X, min(r.X, m.Area.X+m.Area.W-r.W))
+	r.Y = max(m.Area.Y, min(r.Y, m.Area.Y+m.Area.H-h))
+	return r
+}
+
+// drop docks a panel let go of with the mouse against the left or right
+// edge of the area into a full height column there
+func (m *Manager) drop(p *Panel, in Input) {
+	const edge = 3
+	switch {
+	case in.X <= m.Area.X+edge:
+		p.X = m.Area.X
+	case in.X >= m.Area.X+m.Area.W-edge:
+		p.X = m.Area.X + m.Area.W - p.W
+	default:
+		return
+	}
+	p.Y, p.H, p.Minimized = m.Area.Y, m.Area.H, false
+}
+
+// docked are the area edges p touches
+func (m *Manager) docked(p *Panel) Edge {
+	b := p.Bounds()
+	var e Edge
+	if b.X <= m.Area.X {
+		e |= Left
+	}
+	if b.X+b.W >= m.Area.X+m.Area.W {
+		e |= Right
+	}
+	if b.Y <= m.Area.Y {
+		e |= Top
+	}
+	if !p.Minimized && b.Y+b.H >= m.Area.Y+m.Area.H {
+		e |= Bottom
+	}
+	return e
+}
+
+// Resize moves the area, when the window changes size. panels docked to
+// the right or bottom edge follow it, ones docked to both sides stretch,
+// the rest stay where they are as long as they fit
+func (m *Manager) Resize(area Rect) {
+	dw := (area.X + area.W) - (m.Area.X + m.Area.W)
+	dh := (area.Y + area.H) - (m.Area.Y + m.Area.H)
+	m.Area = area
+	for _, p := range m.Layout.Panels {
+		switch {
+		case p.Dock&Left != 0 && p.Dock&Right != 0:
+			p.W = max(MinWidth, p.W+dw)
+		case p.Dock&Right != 0:
+			p.X += dw
+		}
+		switch {
+		case p.Dock&Top != 0 && p.Dock&Bottom != 0:
+			p.H = max(MinHeight, p.H+dh)
+		case p.Dock&Bottom != 0:
+			p.Y += dh
+		}
+		p.W = min(p.W, area.W)
+		p.H = min(p.H, area.H)
+		p.Rect = m.clamp(p.Rect, p.Bounds().H)
+	}
+}
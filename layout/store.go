@@ -0,0 +1,111 @@
+package layout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// layouts are kept as <dir>/<name>.json, the one in use is named in
+// <dir>/current
+const currentFile = "current"
+
+func path(dir, name string) string {
+	return filepath.Join(dir, name+".json")
+}
+
+// ValidName says whether name can be a layout's file name
+func ValidName(name string) error {
+	if name == "" || name == currentFile || strings.ContainsAny(name, `/\:*?"<>|`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%q can't be a layout name", name)
+	}
+	return nil
+}
+
+// Save writes l under its name and makes it the current one
+func Save(dir string, l *Layout) error {
+	if err := ValidName(l.Name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path(dir, l.Name) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path(dir, l.Name)); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, currentFile), []byte(l.Name), 0644)
+}
+
+// Load reads the layout called name
+func Load(dir, name string) (*Layout, error) {
+	if err := ValidName(name); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	var l Layout
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("reading layout %s: %w", name, err)
+	}
+	l.Name = name
+	return &l, nil
+}
+
+// Current is the name of the layout last saved or picked, "" for none
+func Current(dir string) string {
+	b, err := os.ReadFile(filepath.Join(dir, currentFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// SetCurrent makes name the layout the app starts with
+func SetCurrent(dir, name string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, currentFile), []byte(name), 0644)
+}
+
+// Names are the saved layouts, sorted
+func Names(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes the layout called name
+func Delete(dir, name string) error {
+	if err := ValidName(name); err != nil {
+		return err
+	}
+	return os.Remove(path(dir, name))
+}
@@ -0,0 +1,201 @@
+// Package logging builds the one structured logger the app shares. Every
+// component gets its own child logger with its own level, all writing
+// key/value records to a rotating file.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Path       string
+	Format     string                // "json" or "text"
+	Level      slog.Level            // default for components without their own
+	Levels     map[string]slog.Level // per component, "finnhub" -> debug
+	MaxSize    int64                 // bytes before the file rotates, 0 never
+	MaxAge     time.Duration         // age before the file rotates, 0 never
+	MaxBackups int                   // rotated files kept, 0 keeps all
+	Stderr     bool                  // also write to stderr
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Path:       filepath.Join("logs", "stockspider.log"),
+		Format:     "json",
+		Level:      slog.LevelInfo,
+		Levels:     make(map[string]slog.Level),
+		MaxSize:    20 << 20,
+		MaxAge:     24 * time.Hour,
+		MaxBackups: 7,
+	}
+}
+
+// ConfigFromEnv is DefaultConfig overridden by
+//
+//	LOG_FILE=logs/stockspider.log
+//	LOG_FORMAT=json|text
+//	LOG_LEVEL=info
+//	LOG_LEVELS=finnhub=debug,news=warn
+//	LOG_MAX_SIZE_MB=20
+//	LOG_MAX_AGE=24h
+//	LOG_MAX_BACKUPS=7
+//	LOG_STDERR=true
+func ConfigFromEnv() (Config, error) {
+	cfg := DefaultConfig()
+	if v := os.Getenv("LOG_FILE"); v != "" {
+		cfg.Path = v
+	}
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		cfg.Format = v
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := cfg.Level.UnmarshalText([]byte(v)); err != nil {
+			return cfg, fmt.Errorf("LOG_LEVEL: %w", err)
+		}
+	}
+	if v := os.Getenv("LOG_LEVELS"); v != "" {
+		levels, err := ParseLevels(v)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_LEVELS: %w", err)
+		}
+		cfg.Levels = levels
+	}
+	if v := os.Getenv("LOG_MAX_SIZE_MB"); v != "" {
+		mb, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_MAX_SIZE_MB: %w", err)
+		}
+		cfg.MaxSize = int64(mb * (1 << 20))
+	}
+	if v := os.Getenv("LOG_MAX_AGE"); v != "" {
+		age, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_MAX_AGE: %w", err)
+		}
+		cfg.MaxAge = age
+	}
+	if v := os.Getenv("LOG_MAX_BACKUPS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_MAX_BACKUPS: %w", err)
+		}
+		cfg.MaxBackups = n
+	}
+	if v := os.Getenv("LOG_STDERR"); v != "" {
+		stderr, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("LOG_STDERR: %w", err)
+		}
+		cfg.Stderr = stderr
+	}
+	return cfg, nil
+}
+
+// ParseLevels reads "finnhub=debug,news=warn"
+func ParseLevels(s string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		component, level, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not component=level", part)
+		}
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+			return nil, fmt.Errorf("%s: %w", component, err)
+		}
+		levels[strings.TrimSpace(component)] = l
+	}
+	return levels, nil
+}
+
+// Logging owns the log file and hands out component loggers
+type Logging struct {
+	cfg     Config
+	handler slog.Handler
+	closer  io.Closer
+}
+
+func New(cfg Config) (*Logging, error) {
+	w, err := NewRotatingWriter(cfg.Path, cfg.MaxSize, cfg.MaxAge, cfg.MaxBackups)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	var out io.Writer = w
+	if cfg.Stderr {
+		out = io.MultiWriter(w, os.Stderr)
+	}
+
+	// the handler lets everything through, component loggers do the filtering
+	opts := &slog.HandlerOptions{Level: slog.Level(-8)}
+	var handler slog.Handler
+	switch cfg.Format {
+	case "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json", "":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		w.Close()
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	return &Logging{cfg: cfg, handler: handler, closer: w}, nil
+}
+
+// Component returns the logger for one part of the app, records carry a
+// component field and are filtered at that component's level
+func (l *Logging) Component(name string) *slog.Logger {
+	level, ok := l.cfg.Levels[name]
+	if !ok {
+		level = l.cfg.Level
+	}
+	return slog.New(levelHandler{
+		Handler: l.handler.WithAttrs([]slog.Attr{slog.String("component", name)}),
+		level:   level,
+	})
+}
+
+func (l *Logging) Close() error {
+	return l.closer.Close()
+}
+
+// levelHandler drops records below its level
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// Discard is a logger that drops everything, what packages use until
+// they're handed a real one
+func Discard() *slog.Logger {
+	return slog.New(discardHandler{})
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// millis so two rotations in the same second don't overwrite each other
+const backupLayout = "2006-01-02T15-04-05.000"
+
+// RotatingWriter is a log file that moves itself aside once it gets too
+// big or too old, keeping the newest MaxBackups of the old ones.
+// It is safe for concurrent use.
+type RotatingWriter struct {
+	path       string
+	maxSize    int64         // bytes, 0 never rotates on size
+	maxAge     time.Duration // 0 never rotates on age
+	maxBackups int           // 0 keeps every backup
+
+	mu     sync.Mutex
+	f      *os.File // nil after a rotation that couldn't open the new file
+	closed bool
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+// rename moves the log aside, tests swap it to make rotation fail
+var rename = os.Rename
+
+func NewRotatingWriter(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	w := &RotatingWriter{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = info.Size()
+	// an existing file counts from when it was last written, close enough
+	// to when it was started for age based rotation
+	w.opened = w.now()
+	if w.size > 0 {
+		w.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	// the last rotation lost the file, have another go at it
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	var rotateErr error
+	if w.due(int64(len(p))) {
+		rotateErr = w.rotate()
+		if w.f == nil {
+			return 0, rotateErr
+		}
+	}
+	// a failed rotation still reports its error, after p made it into
+	// whichever file is open
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// due reports whether writing n more bytes should go to a fresh file
+func (w *RotatingWriter) due(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	return w.maxAge > 0 && w.now().Sub(w.opened) >= w.maxAge
+}
+
+// rotate moves the log aside and opens a fresh one. when that fails
+// halfway w.f is either the old file opened again or nil, for Write to
+// reopen, never a closed file
+func (w *RotatingWriter) rotate() error {
+	err := w.f.Close()
+	w.f = nil
+	if err != nil {
+		return err
+	}
+	ext := filepath.Ext(w.path)
+	backup := fmt.Sprintf("%s.%s%s", strings.TrimSuffix(w.path, ext), w.now().Format(backupLayout), ext)
+	if err := rename(w.path, backup); err != nil {
+		// keep logging where we were, the size and age start over so the
+		// next try is another maxSize bytes or maxAge away, not every write
+		if openErr := w.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		w.size = 0
+		w.opened = w.now()
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.opened = w.now()
+	return w.prune()
+}
+
+// prune deletes the oldest backups past maxBackups
+func (w *RotatingWriter) prune() error {
+	if w.maxBackups <= 0 {
+		return nil
+	}
+	ext := filepath.Ext(w.path)
+	pattern := strings.TrimSuffix(w.path, ext) + ".*" + ext
+	backups, err := filepath.Glob(pattern)
+	if err != nil {
+		return err
+	}
+	// the timestamp layout sorts oldest first
+	sort.Strings(backups)
+	for len(backups) > w.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWriter(t *testing.T, maxSize int64) (*RotatingWriter, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotatingWriter(path, maxSize, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every rotation gets its own backup name
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { now = now.Add(time.Second); return now }
+	t.Cleanup(func() { w.Close() })
+	return w, path
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ".log") + ".*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestRotateOnSize(t *testing.T) {
+	w, path := newTestWriter(t, 10)
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := read(t, path); got != "dddddddd\n" {
+		t.Fatalf("log holds %q, want only the last line", got)
+	}
+	// four files' worth, the oldest pruned past two backups
+	if got := backups(t, path); len(got) != 2 {
+		t.Fatalf("%d backups %v, want 2", len(got), got)
+	}
+}
+
+func TestRotateRenameFails(t *testing.T) {
+	w, path := newTestWriter(t, 10)
+	rename = func(string, string) error { return errors.New("rename failed") }
+	t.Cleanup(func() { rename = os.Rename })
+
+	w.Write([]byte("aaaaaaaa\n"))
+	n, err := w.Write([]byte("bbbbbbbb\n"))
+	if err == nil || n != 9 {
+		t.Fatalf("wrote %d %v, want the line written and the rename error", n, err)
+	}
+	if got := read(t, path); got != "aaaaaaaa\nbbbbbbbb\n" {
+		t.Fatalf("log holds %q, want both lines in the same file", got)
+	}
+
+	// once renames work again it rotates as usual
+	rename = os.Rename
+	if _, err := w.Write([]byte("cccccccc\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, path); got != "cccccccc\n" {
+		t.Fatalf("log holds %q after rotating, want only the last line", got)
+	}
+	if got := backups(t, path); len(got) != 1 {
+		t.Fatalf("%d backups, want 1", len(got))
+	}
+}
+
+func TestRotateRenameFailsRetriesLater(t *testing.T) {
+	w, path := newTestWriter(t, 20)
+	renames := 0
+	rename = func(string, string) error { renames++; return errors.New("rename failed") }
+	t.Cleanup(func() { rename = os.Rename })
+
+	// the third line is due and fails, every try after waits until
+	// another 20 bytes went in
+	var failed []int
+	for i := 1; i <= 7; i++ {
+		if _, err := w.Write([]byte("aaaaaaaa\n")); err != nil {
+			failed = append(failed, i)
+		}
+	}
+	if want := []int{3, 5, 7}; renames != 3 || !slices.Equal(failed, want) {
+		t.Fatalf("%d renames, writes %v failed, want 3 renames failing writes %v", renames, failed, want)
+	}
+	if got := read(t, path); got != strings.Repeat("aaaaaaaa\n", 7) {
+		t.Fatalf("log holds %q, want every line", got)
+	}
+}
+
+func TestRotateOpenFails(t *testing.T) {
+	w, path := newTestWriter(t, 10)
+	// the rename goes through but leaves a directory where the new log
+	// should be opened
+	rename = func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		return os.Mkdir(from, 0755)
+	}
+	t.Cleanup(func() { rename = os.Rename })
+
+	w.Write([]byte("aaaaaaaa\n"))
+	if n, err := w.Write([]byte("bbbbbbbb\n")); err == nil || n != 0 {
+		t.Fatalf("wrote %d %v with no file to write to", n, err)
+	}
+
+	// the way is clear again, the next write opens a fresh log
+	rename = os.Rename
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("cccccccc\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, path); got != "cccccccc\n" {
+		t.Fatalf("log holds %q, want the write after recovering", got)
+	}
+
+	w.Close()
+	if _, err := w.Write([]byte("dddddddd\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("write after close got %v, want os.ErrClosed", err)
+	}
+}
@@ -3,35 +3,61 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"github.com/Scrimzay/stockspider/actor/consumer/finnhub"
+	"github.com/Scrimzay/stockspider/alert"
+	"github.com/Scrimzay/stockspider/actor/consumer/news"
+	"github.com/Scrimzay/stockspider/analyst"
+	"github.com/Scrimzay/stockspider/backfill"
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/cli"
+	"github.com/Scrimzay/stockspider/corporate"
 	"github.com/Scrimzay/stockspider/event"
-	"github.com/Scrimzay/stockspider/symbolArray"
+	"github.com/Scrimzay/stockspider/export"
+	"github.com/Scrimzay/stockspider/fundamentals"
+	"github.com/Scrimzay/stockspider/hub"
+	"github.com/Scrimzay/stockspider/keys"
+	"github.com/Scrimzay/stockspider/layout"
+	"github.com/Scrimzay/stockspider/logging"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/node"
+	"github.com/Scrimzay/stockspider/paper"
+	"github.com/Scrimzay/stockspider/portfolio"
+	"github.com/Scrimzay/stockspider/store"
+	"github.com/Scrimzay/stockspider/supervise"
+	"github.com/Scrimzay/stockspider/theme"
+	"github.com/Scrimzay/stockspider/strategy"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	FH "github.com/Finnhub-Stock-API/finnhub-go/v2"
-	"github.com/Scrimzay/loglogger"
 	"github.com/anthdm/hollywood/actor"
 	gui "github.com/gen2brain/raylib-go/raygui"
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/joho/godotenv"
 )
 
-var log *logger.Logger
+// log is the app's logger, main swaps in the real one once the config
+// from .env is loaded
+var log = logging.Discard()
 
-func init() {
-	var err error
-	log, err = logger.New("mainPackage.txt")
-	if err != nil {
-		log.Fatalf("Could not start new logger in main: %v", err)
-	}
-
-	err = godotenv.Load(".env")
-	if err != nil {
-		log.Fatalf("Error loading .env file")
-	}
+// fatal logs msg and exits, log.Fatal for slog
+func fatal(msg string, args ...any) {
+	log.Error(msg, args...)
+	os.Exit(1)
 }
 
 type FinnhubClientCFG struct {
@@ -45,6 +71,8 @@ func NewFinnhubClient(apiKey string) (*FinnhubClientCFG, error) {
 
     cfg := FH.NewConfiguration()
     cfg.AddDefaultHeader("X-Finnhub-Token", os.Getenv("API_KEY"))
+    // counts every call per endpoint for /metrics
+    cfg.HTTPClient = &http.Client{Transport: metrics.Transport(nil)}
     
     return &FinnhubClientCFG{
         Client: FH.NewAPIClient(cfg).DefaultApi,
@@ -52,207 +80,206 @@ func NewFinnhubClient(apiKey string) (*FinnhubClientCFG, error) {
 }
 
 type App struct {
-	tradeCh chan event.StockTrade
+	hub *hub.Hub // everything shown that isn't the GUI's own state
 	engine *actor.Engine
 
 	//prevTrade event.StockTrade
-	trades map[string][]event.StockTrade
-	quotes map[string]event.Quote
-	marketStatus map[string]event.MarketStatus
-	recommendationTrends map[string]event.RecommendationTrends
-	symbolMetrics map[string]event.SymbolMetric
-
-	panel *Panel
-	panel2 *Panel
-	panel3 *Panel
-	panel4 *Panel
-	panel5 *Panel
-
-	availableSymbols map[string]string // display name -> full symbol name
-	symbolOrder []string  // maintain stable order of symbols
-	selectedSymbol string
-	finnhubClient *actor.PID // store reference to finnhub actor
+	ratingsTab int32
+	fundamentalsTab int32
+	fundamentalsScroll float32
+	seriesIndex int
+	seriesQuarterly bool
+	timeframe candle.Timeframe // timeframe shown on the chart
+	paper *paper.Engine
+	orderForm orderForm
+	strategy *strategy.Live // optional strategy trading the paper account
+	portfolios []*portfolio.Portfolio
+	activePortfolio int32
+	holdings atomic.Pointer[[]string] // the active portfolio's symbols, read by the hub's poller
+	portfolioMessage string
+	newsTab int32
+	newsScroll float32
+	selectedNews string // news.Key of the headline showing its summary
+	corporate *corporate.Store
+	calendarFilter int32
+	export export.Sources
+	exportMenu string // id of the chart whose export menu is open
+	exportStatus atomic.Pointer[string] // set by the export goroutine
+
+	panels *layout.Manager
+	panelMenu bool
+	layoutMenu bool
+	viewMenu bool
+	settings theme.Settings // theme, font and zoom
+	layoutNames []string // saved layouts, read when the menu opens
+	layoutName string // the save as box in the layouts menu
+	layoutNameEditing bool
+	layoutMessage string
+	cursor int32
+	backfilled map[string]bool // symbol|timeframe already backfilled for a chart or tile
+	chartRanges map[string][2]time.Time // what each chart shows, by panel id
+
+	keys keys.Bindings
+	palette palette
+	typeAhead string // letters typed to jump to a symbol
+	typeAheadAt time.Time
+	quit bool
+	alerts *alert.Book
+	alertMessage atomic.Pointer[string] // the last alert to go off, set from the trade loop
+
+	unrendered atomic.Int64 // unix nanos the oldest trade not drawn yet traded at, 0 when drawn
 	scrollOffset float32
 }
 
-func NewApp(tradeCh chan event.StockTrade, engine *actor.Engine) *App {
-	// Create a stable order for symbols
-	symbolOrder := make([]string, 0, len(symbolArray.Symbols))
-	for _, fullSymbol := range symbolArray.Symbols {
-		symbolOrder = append(symbolOrder, fullSymbol)
-	}
-	// Sort to ensure consistent order
-	sort.Strings(symbolOrder)
-
+func NewApp(engine *actor.Engine, h *hub.Hub) *App {
 	app := &App{
-		trades: make(map[string][]event.StockTrade),
-		tradeCh: tradeCh,
-		quotes: make(map[string]event.Quote),
-		marketStatus: make(map[string]event.MarketStatus),
-		recommendationTrends: make(map[string]event.RecommendationTrends),
-		symbolMetrics: make(map[string]event.SymbolMetric),
+		hub: h,
+		timeframe: candle.M1,
 		engine: engine,
-		availableSymbols: symbolArray.Symbols,
-		symbolOrder: symbolOrder,
-		selectedSymbol: "", // DEFAULT
+		backfilled: make(map[string]bool),
+		chartRanges: make(map[string][2]time.Time),
 	}
 
-	// i'm pretty sure this allows clicking the symbols in the panel
-	// since without it, clicking isnt possible? oh well
-	app.panel = NewPanel(10, 80, 300, 700)
-	app.panel.title = "Symbols - Finnhub"
-
-	app.panel2 = NewPanel(900, 300, 300, 300)
-	app.panel2.title = "Trades - Finnhub"
-
-	app.panel3 = NewPanel(900, 700, 300, 200)
-	app.panel3.title = "Quotes - Finnhub"
-
-	app.panel4 = NewPanel(600, 700, 300, 200)
-	app.panel4.title = "Recommendation Trends - Finnhub"
-
-	app.panel5 = NewPanel(600, 400, 300, 200)
-	app.panel5.title = "Symbol Metrics - Finnhub"
-
-	// yea see its right here (refer to line 90)
-	app.panel.onClick = app.handleSymbolClick
-	app.panel2.onClick = app.panel2.HandlePanelDrag
-	app.panel3.onClick = app.panel3.HandlePanelDrag
-	app.panel4.onClick = app.panel4.HandlePanelDrag
-	app.panel5.onClick = app.panel5.HandlePanelDrag
-
 	return app
 }
 
 func (app *App) start() {
-	// handle trades
-	go func() {
-		for trade := range app.tradeCh {
-			symbol := trade.Pair.Symbol
-			log.Printf("Received trade for %s: Price %.2f, Qty %.4f", 
-                symbol, trade.Price, trade.Qty)
-
-			if app.trades[symbol] == nil {
-				app.trades[symbol] = make([]event.StockTrade, 0)
-			}
-			app.trades[symbol] = append(app.trades[symbol], trade)
-			// Keep only last N trades
-			if len(app.trades[symbol]) > 100 {
-				app.trades[symbol] = app.trades[symbol][1:]
+	// the trade loop is the hub's, the paper account and strategy ride along
+	app.hub.OnTrade = func(trade event.StockTrade) {
+		if trade.Unix > 0 {
+			app.unrendered.CompareAndSwap(0, time.UnixMilli(trade.Unix).UnixNano())
+		}
+		if app.paper != nil {
+			if _, err := app.paper.OnTrade(trade); err != nil {
+				log.Error("saving paper account after a trade", "err", err)
 			}
 		}
-	}()
+		if app.strategy != nil {
+			app.strategy.OnTrade(trade)
+		}
+		if app.alerts != nil {
+			app.checkAlerts(trade)
+		}
+	}
+	app.hub.Holdings = app.holdingSymbols
+	app.hub.Start()
 
-	go app.handleQuotes()
-	go app.handleMarketStatus()
-	go app.handleRecommendationTrends()
-	go app.handleSymbolMetric()
+	go app.handlePaperExpiry()
+	go app.handleCorporateEvents()
 }
 
-var color = rl.Green
+var color = colors.Up
 
 func (app *App) render() {
 	rl.BeginDrawing()
-    rl.ClearBackground(rl.Black)
-
-	app.panel.update()
-	app.panel.render()
-	app.handlePanel1Logic()
+    rl.ClearBackground(colors.Background)
 
-	app.panel2.update()
-	app.panel2.render()
-	// DONT TOUCH, this handles the trades, it must be strings.ToLower
-	app.handlePanel2Logic(app.trades[strings.ToLower(app.selectedSymbol)])
-
-	app.panel3.update()
-	app.panel3.render()
-	if quote, ok := app.quotes[app.selectedSymbol]; ok {
-		app.handlePanel3Logic(quote)
+	if app.updateScale() || rl.IsWindowResized() {
+		app.panels.Resize(panelArea())
 	}
-
-	// render market status at the top right
-	if status, ok := app.marketStatus["US"]; ok {
-		statusStr := fmt.Sprintf("Market: %s", 
-        map[bool]string{true: "Open", false: "Closed"}[status.IsOpen])
-		rl.DrawText(statusStr, 1000, 7, 20, rl.Green)
-		if !status.IsOpen {
-			rl.DrawText(statusStr, 1000, 7, 20, rl.Red)
+	// everything below is in layout pixels
+	rl.BeginMode2D(rl.Camera2D{Zoom: scale})
+	mouse := rl.GetMousePosition()
+	app.panels.Update(layout.Input{
+		X: mouse.X,
+		Y: mouse.Y,
+		Pressed: rl.IsMouseButtonPressed(rl.MouseLeftButton),
+		Down: rl.IsMouseButtonDown(rl.MouseLeftButton),
+		Released: rl.IsMouseButtonReleased(rl.MouseLeftButton),
+	}, app.overMenu(mouse))
+	if p := app.panels.Clicked; p != nil {
+		switch app.panels.Button {
+		case layout.Pin:
+			app.togglePin(p)
+		case layout.Link:
+			p.Link = layout.NextLink(p.Link)
+		default:
+			if symbol, ok := app.symbolAt(app.panels.ClickY); ok && p.Kind == "symbols" {
+				app.selectFor(p, symbol)
+			}
 		}
-
-		sessionStr := fmt.Sprintf("Session: %s", status.Session)
-		rl.DrawText(sessionStr, 980, 25, 20, rl.White)
 	}
+	app.watchSymbols()
+	app.handleKeys()
 
-	app.panel4.update()
-	app.panel4.render()
-	if trends, ok := app.recommendationTrends[app.selectedSymbol]; ok {
-		app.handlePanel4Logic(trends)
+	// displays the current ticker for ease of view
+	currentTicker := fmt.Sprint(app.hub.Selected())
+	drawText(currentTicker, 20, 20, textTicker, colors.Accent)
+	if message := app.alertMessage.Load(); message != nil {
+		drawText(*message, 20, 62, textBody, colors.Warning)
 	}
-
-	handleMarketTimer()
-
-	app.panel5.update()
-	app.panel5.render()
-	selectedMetrics, exists := app.symbolMetrics[app.selectedSymbol]
-	if exists {
-		app.handlePanel5Logic(selectedMetrics)
+	// render market status at the top right
+	app.renderMarketStatus()
+	app.handleMarketTimer()
+	app.handleDroppedFiles()
+
+	// back to front, only the panel under the mouse takes clicks
+	for _, p := range app.panels.Layout.Panels {
+		app.renderPanelFrame(p)
+		if p.Minimized {
+			continue
+		}
+		if !app.hovered(p) {
+			gui.Lock()
+		}
+		if kind, ok := panelKindOf(p.Kind); ok {
+			kind.render(app, p)
+		}
+		gui.Unlock()
 	}
+	app.handleMenus()
+	app.handlePalette()
+	app.setCursor(mouse)
 
+	rl.EndMode2D()
 	rl.EndDrawing()
-}
 
-func (app *App) handlePanel1Logic() {
-    mouseX, mouseY := rl.GetMousePosition().X, rl.GetMousePosition().Y
+	// trades that came in since the last frame are on screen now
+	if traded := app.unrendered.Swap(0); traded != 0 {
+		metrics.Rendered(time.Unix(0, traded))
+	}
+}
 
-    // Height of the panel title
-    titleHeight := float32(25)
+// the symbol list's rows, from the top of its panel
+const (
+	symbolListTop = 25
+	symbolRowHeight = 25
+)
 
-    // Adjust scroll offset based on mouse wheel movement
-    if rl.GetMouseWheelMove() != 0 {
+func (app *App) handlePanel1Logic(p *layout.Panel) {
+    // Adjust scroll offset based on mouse wheel movement, only when
+    // hovering so the news panel can scroll too
+    if app.hovered(p) && rl.GetMouseWheelMove() != 0 {
         app.scrollOffset -= rl.GetMouseWheelMove() * 20 // Adjust scroll speed as needed
     }
 
-    // Calculate the total content height
-    totalContentHeight := float32(len(app.symbolOrder)*25) + titleHeight
-
     // Clamp scroll offset to ensure all symbols are visible
-    maxOffset := max(0, int(totalContentHeight) - int(app.panel.height))
-    if app.scrollOffset < 0 {
-        app.scrollOffset = 0
-    } else if app.scrollOffset > float32(maxOffset) {
-        app.scrollOffset = float32(maxOffset)
-    }
+    app.clampSymbolScroll(p)
 
     // Begin scissor mode to clip rendering within the panel
-    rl.BeginScissorMode(
-        int32(app.panel.position.X),
-        int32(app.panel.position.Y+titleHeight), // Start below the title
-        int32(app.panel.width),
-        int32(app.panel.height-titleHeight),    // Adjust height to exclude the title
+    beginScissor(
+        p.X,
+        p.Y+symbolListTop, // Start below the title
+        p.W,
+        p.H-symbolListTop,    // Adjust height to exclude the title
     )
 
     // Render symbols with scrolling
-    y := app.panel.position.Y + titleHeight - app.scrollOffset
+    y := p.Y + symbolListTop - app.scrollOffset
 
-    for _, fullSymbol := range app.symbolOrder {
-        color := rl.White
-        if fullSymbol == app.selectedSymbol {
-            color = rl.Green
+    selected := app.symbolFor(p)
+    for _, fullSymbol := range app.hub.Order {
+        color := colors.Text
+        if fullSymbol == selected {
+            color = colors.Up
         }
 
         // Render the symbol if it's within the visible area
-        if y >= app.panel.position.Y+titleHeight && y <= app.panel.position.Y+app.panel.height {
-            rl.DrawText(fullSymbol, int32(app.panel.position.X+10), int32(y), 17, color)
+        if y >= p.Y+symbolListTop && y <= p.Y+p.H {
+            drawText(fullSymbol, int32(p.X+10), int32(y), textLabel, color)
         }
 
-        // Check for click events on each symbol
-        if rl.IsMouseButtonPressed(rl.MouseLeftButton) &&
-            mouseX >= app.panel.position.X && mouseX <= app.panel.position.X+app.panel.width &&
-            mouseY >= y && mouseY <= y+20 { // Assuming 20 is the height of a symbol row
-            app.selectedSymbol = fullSymbol
-        }
-
-        y += 25
+        y += symbolRowHeight
     }
 
     // End scissor mode
@@ -276,48 +303,44 @@ func (app *App) handlePanel1Logic() {
 	// }
 	// lastTradeStr := fmt.Sprint(lastTrade.Price)
 	// rl.DrawText(lastTradeStr, 20, 20, 40, rl.Yellow)
-
-	// displays the current ticker for ease of view
-	currentTicker := fmt.Sprint(app.selectedSymbol)
-	rl.DrawText(currentTicker, 20, 20, 40, rl.Yellow)
 }
 
-func (app *App) handlePanel2Logic(symbolTrades []event.StockTrade) {
+func (app *App) handlePanel2Logic(p *layout.Panel, symbolTrades []event.StockTrade) {
 	if len(symbolTrades) > 0 {
 		// Get panel2's position and bounds
-		panelX := app.panel2.position.X
-		panelY := app.panel2.position.Y
+		panelX := p.X
+		panelY := p.Y
 		y := panelY + 30 // start below panel title
 
 		for i := len(symbolTrades) - 1; i >= max(0, len(symbolTrades)-10); i-- {
 			trade := symbolTrades[i]
 			tradeStr := fmt.Sprintf("%.4f @ %.2f", trade.Qty, trade.Price)
-			color = rl.Green
+			color = colors.Up
 			if !trade.IsBuy {
-				color = rl.Red
+				color = colors.Down
 			}
-			rl.DrawText(tradeStr, int32(panelX + 20), int32(y), 20, color)
+			drawText(tradeStr, int32(panelX + 20), int32(y), textTitle, color)
 			y += 25
 		}
 	}
 }
 
-func (app *App) handlePanel3Logic(quote event.Quote) {
-    panelX := app.panel3.position.X
-    panelY := app.panel3.position.Y
+func (app *App) handlePanel3Logic(p *layout.Panel, quote event.Quote) {
+    panelX := p.X
+    panelY := p.Y
     y := panelY + 30 // Start below panel title
 
     // Current price with color based on comparison to PrevClose
-    currentColor := rl.White
+    currentColor := colors.Text
     if quote.Current > quote.PrevClose {
-        currentColor = rl.Green
+        currentColor = colors.Up
     } else if quote.Current < quote.PrevClose {
-        currentColor = rl.Red
+        currentColor = colors.Down
     }
 
     // Draw the current price
     currentStr := fmt.Sprintf("Current: %.2f", quote.Current)
-    rl.DrawText(currentStr, int32(panelX+20), int32(y), 24, currentColor)
+    drawText(currentStr, int32(panelX+20), int32(y), textBig, currentColor)
     y += 35
 
     // Draw other quote information
@@ -326,460 +349,2804 @@ func (app *App) handlePanel3Logic(quote event.Quote) {
         value float64
         color rl.Color
     }{
-        {"High", float64(quote.High), rl.Green},
-        {"Low", float64(quote.Low), rl.Red},
-        {"Open", float64(quote.Open), rl.White},
-        {"Prev Close", float64(quote.PrevClose), rl.White},
+        {"High", float64(quote.High), colors.Up},
+        {"Low", float64(quote.Low), colors.Down},
+        {"Open", float64(quote.Open), colors.Text},
+        {"Prev Close", float64(quote.PrevClose), colors.Text},
     }
 
     for _, stat := range stats {
         statStr := fmt.Sprintf("%s: %.2f", stat.label, stat.value)
-        rl.DrawText(statStr, int32(panelX+20), int32(y), 20, stat.color)
+        drawText(statStr, int32(panelX+20), int32(y), textTitle, stat.color)
         y += 25
     }
 
     // Calculate and display price change
     change := quote.Current - quote.PrevClose
     changePercent := (change / quote.PrevClose) * 100
-    changeColor := rl.White
+    changeColor := colors.Text
     if change > 0 {
-        changeColor = rl.Green
+        changeColor = colors.Up
     } else if change < 0 {
-        changeColor = rl.Red
+        changeColor = colors.Down
     }
 
     changeStr := fmt.Sprintf("Change: %.2f (%.2f%%)", change, changePercent)
     y += 10 // Add space before the change
-    rl.DrawText(changeStr, int32(panelX+20), int32(y), 20, changeColor)
+    drawText(changeStr, int32(panelX+20), int32(y), textTitle, changeColor)
 }
 
-func (app *App) handlePanel4Logic(trends event.RecommendationTrends) {
-    panelX := app.panel4.position.X
-    panelY := app.panel4.position.Y
-    y := panelY + 30 // Start below panel title
+// ratingColors go from strong buy to strong sell in the current theme
+func ratingColors() []rl.Color {
+	return []rl.Color{colors.Up, colors.MildUp, colors.Muted, colors.MildDown, colors.Down}
+}
+
+func ratingCounts(t event.RecommendationTrends) []int64 {
+	return []int64{t.StrongBuy, t.Buy, t.Hold, t.Sell, t.StrongSell}
+}
 
-    currentColor := rl.White
-    if trends.Buy > trends.Sell {
-        currentColor = rl.Green
-    } else if trends.Buy < trends.Sell {
-        currentColor = rl.Red
-    } else if trends.StrongBuy > trends.StrongSell {
-        currentColor = rl.Green
-    } else if trends.StrongBuy < trends.StrongSell {
-        currentColor = rl.Red
+func (app *App) handlePanel4Logic(p *layout.Panel, ratings *analyst.Ratings) {
+    panelX := p.X
+    panelY := p.Y
+    x := panelX + 10
+    y := panelY + 28 // Start below panel title
+
+    app.ratingsTab = gui.ToggleGroup(rl.NewRectangle(x, y, 60, 16), "Trend;Target;Changes", app.ratingsTab)
+    y += 22
+
+    switch app.ratingsTab {
+    case 1:
+        app.renderPriceTarget(ratings.Target, x, y)
+    case 2:
+        app.renderRatingChanges(p, ratings.Changes, x, y)
+    default:
+        app.renderRatingTrend(p, ratings, x, y)
     }
+}
 
-    // Render dominant trend summary
-    rl.DrawText("Recommendations", int32(panelX+20), int32(y), 24, currentColor)
-    y += 35
+// renderRatingTrend draws the monthly ratings as stacked bars with the
+// newest month's counts and how they moved since the month before
+func (app *App) renderRatingTrend(p *layout.Panel, ratings *analyst.Ratings, x, y float32) {
+    latest, ok := ratings.Latest()
+    if !ok {
+        drawText("No recommendations", int32(x), int32(y), textLabel, colors.Muted)
+        return
+    }
 
-    // render individual trend stats
-    stats := []struct {
+    history := ratings.Trends
+    if len(history) > 12 {
+        history = history[len(history)-12:]
+    }
+    most := int64(1)
+    for _, t := range history {
+        if total := analyst.Total(t); total > most {
+            most = total
+        }
+    }
+
+    chartH := float32(80)
+    chartW := p.W - 20
+    step := chartW / float32(len(history))
+    for i, t := range history {
+        barY := y + chartH
+        for j, count := range ratingCounts(t) {
+            h := float32(count) / float32(most) * chartH
+            barY -= h
+            rl.DrawRectangle(int32(x+float32(i)*step+1), int32(barY), int32(step-2), int32(h), ratingColors()[j])
+        }
+    }
+    drawText(history[0].Period, int32(x), int32(y+chartH+2), textTiny, colors.Muted)
+    drawText(latest.Period, int32(x+chartW)-measureText(latest.Period, textTiny), int32(y+chartH+2), textTiny, colors.Muted)
+    y += chartH + 16
+
+    change := ratings.Change()
+    labels := []string{"SB", "B", "H", "S", "SS"}
+    counts := ratingCounts(latest)
+    changes := ratingCounts(change)
+    for i := range labels {
+        text := fmt.Sprintf("%s %d", labels[i], counts[i])
+        if changes[i] != 0 {
+            text += fmt.Sprintf(" (%+d)", changes[i])
+        }
+        drawText(text, int32(x+float32(i%3)*95), int32(y+float32(i/3)*14), textSmall, ratingColors()[i])
+    }
+    score := fmt.Sprintf("Score %.2f", analyst.Score(latest))
+    drawText(score, int32(x+190), int32(y+14), textSmall, colors.Text)
+}
+
+func (app *App) renderPriceTarget(target *event.PriceTarget, x, y float32) {
+    if target == nil {
+        drawText("No price target", int32(x), int32(y), textLabel, colors.Muted)
+        return
+    }
+
+    current := 0.0
+    if quote, ok := app.hub.Quote(app.selectedPair().Symbol); ok {
+        current = float64(quote.Current)
+    }
+    rows := []struct {
         label string
-        value int64
-        color rl.Color
+        value float64
     }{
-        {"Buy", trends.Buy, rl.DarkGreen},
-        {"Sell", trends.Sell, rl.Red},
-        {"Hold", trends.Hold, rl.White},
-        {"StrongBuy", trends.StrongBuy, rl.Green},
-        {"StrongSell", trends.StrongSell, rl.Red},
+        {"High", target.High},
+        {"Mean", target.Mean},
+        {"Median", target.Median},
+        {"Low", target.Low},
+    }
+    for _, row := range rows {
+        text := fmt.Sprintf("%-7s %.2f", row.label, row.value)
+        rowColor := colors.Text
+        if current > 0 {
+            upside := (row.value - current) / current * 100
+            text += fmt.Sprintf("  %+.1f%%", upside)
+            rowColor = colors.Up
+            if upside < 0 {
+                rowColor = colors.Down
+            }
+        }
+        drawText(text, int32(x), int32(y), textLabel, rowColor)
+        y += 20
     }
 
-    for _, stat := range stats {
-        statStr := fmt.Sprintf("%s: %d", stat.label, stat.value)
-        rl.DrawText(statStr, int32(panelX+20), int32(y), 20, stat.color)
-        y += 25
+    info := fmt.Sprintf("%d analysts", target.Analysts)
+    if target.Unix != 0 {
+        info += ", updated " + time.Unix(target.Unix, 0).Format("2006-01-02")
     }
+    drawText(info, int32(x), int32(y+4), textSmall, colors.Muted)
 }
 
-func (app *App) handlePanel5Logic(metrics event.SymbolMetric) {
-	panelX := app.panel5.position.X
-    panelY := app.panel5.position.Y
-    y := panelY + 50 // Start below panel title
+func (app *App) renderRatingChanges(p *layout.Panel, changes []event.RatingChange, x, y float32) {
+    if len(changes) == 0 {
+        drawText("No upgrades or downgrades", int32(x), int32(y), textLabel, colors.Muted)
+        return
+    }
 
-	rl.DrawText("Basic Financials", int32(panelX + 10), int32(panelY + 25), 20, rl.Yellow)
+    for _, c := range changes {
+        if y > p.Y+p.H-14 {
+            break
+        }
+        rowColor := colors.Text
+        switch c.Action {
+        case "up":
+            rowColor = colors.Up
+        case "down":
+            rowColor = colors.Down
+        }
+        grade := c.ToGrade
+        if c.FromGrade != "" && c.FromGrade != c.ToGrade {
+            grade = c.FromGrade + " > " + c.ToGrade
+        }
+        row := fmt.Sprintf("%s %s %s", time.Unix(c.Unix, 0).Format("01-02"), c.Company, grade)
+        drawText(fitText(row, textSmall, p.W-20), int32(x), int32(y), textSmall, rowColor)
+        y += 14
+    }
+}
 
-	metricData := []struct{
-		Label string
-		Value string
-	}{
-		{"10-Day Avg. Volume", fmt.Sprintf("%.2f", metrics.TenDayAverageTradingVolume)},
-        {"52-Week High", fmt.Sprintf("%.2f", metrics.FiftyTwoWeekHigh)},
-        {"52-Week Low", fmt.Sprintf("%.2f", metrics.FiftyTwoWeekLow)},
-        {"52-Week Price Return", fmt.Sprintf("%.2f%%", metrics.FiftyTwoWeekPriceReturnDaily)},
+// fundamentals panel tabs, the categories come first
+var (
+	fundamentalsHistoryTab = int32(len(fundamentals.Categories))
+	fundamentalsPeersTab = fundamentalsHistoryTab + 1
+)
+
+func (app *App) handlePanel5Logic(p *layout.Panel) {
+	panelX := p.X
+	panelY := p.Y
+	x := panelX + 10
+	y := panelY + 28
+
+	labels := make([]string, 0, len(fundamentals.Categories)+2)
+	for _, c := range fundamentals.Categories {
+		labels = append(labels, c.String()[:4])
+	}
+	labels = append(labels, "Hist", "Peer")
+	app.fundamentalsTab = gui.ToggleGroup(rl.NewRectangle(x, y, 33, 18), strings.Join(labels, ";"), app.fundamentalsTab)
+	y += 26
+
+	pair := app.pairFor(p)
+	if calendar.For(pair) == calendar.Crypto {
+		drawText("No fundamentals for crypto", int32(x), int32(y), textLabel, colors.Muted)
+		return
+	}
+	data := app.hub.Fundamentals(pair.Symbol)
+	if data == nil {
+		drawText("Loading fundamentals...", int32(x), int32(y), textLabel, colors.Muted)
+		return
 	}
 
-	for _, data := range metricData {
-		rl.DrawText(fmt.Sprintf("%s: %s", data.Label, data.Value), int32(panelX + 10), int32(y), 17, rl.White)
-		y += 25
+	switch app.fundamentalsTab {
+	case fundamentalsHistoryTab:
+		app.renderFundamentalsHistory(p, data, x, y)
+	case fundamentalsPeersTab:
+		app.renderPeers(p, pair.Symbol, x, y)
+	default:
+		app.renderFundamentalsCategory(p, data, fundamentals.Categories[app.fundamentalsTab], x, y)
 	}
 }
 
-func handleMarketTimer() {
-	nyLoc, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		log.Printf("Failed to load location: %v", err)
+func (app *App) renderFundamentalsCategory(p *layout.Panel, data *fundamentals.Fundamentals, category fundamentals.Category, x, y float32) {
+	metrics := data.Available(category)
+	if len(metrics) == 0 {
+		drawText("Nothing reported", int32(x), int32(y), textLabel, colors.Muted)
+		return
 	}
 
-	// define market open and close times
-	now := time.Now().In(nyLoc)
-	marketOpen := time.Date(now.Year(), now.Month(), now.Day(), 9, 30, 0, 0, nyLoc)
-	marketClose := time.Date(now.Year(), now.Month(), now.Day(), 16, 0, 0, 0, nyLoc)
+	// the list scrolls when there's more than fits
+	rowHeight := float32(15)
+	bottom := p.Y + p.H - 5
+	area := rl.NewRectangle(p.X, y, p.W, bottom-y)
+	if app.hovered(p) && rl.CheckCollisionPointRec(rl.GetMousePosition(), area) {
+		app.fundamentalsScroll -= rl.GetMouseWheelMove() * 15
+	}
+	maxScroll := float32(max(0, int(float32(len(metrics))*rowHeight-area.Height)))
+	if app.fundamentalsScroll < 0 {
+		app.fundamentalsScroll = 0
+	} else if app.fundamentalsScroll > maxScroll {
+		app.fundamentalsScroll = maxScroll
+	}
 
-	var marketTimer string
-	var color rl.Color
+	valueX := int32(x + p.W - 110)
+	beginScissor(area.X, area.Y, area.Width, area.Height)
+	rowY := y - app.fundamentalsScroll
+	for _, m := range metrics {
+		drawText(m.Label, int32(x), int32(rowY), textSmall, colors.Text)
+		drawText(m.Format(data.Value(m.Key)), valueX, int32(rowY), textSmall, colors.Info)
+		rowY += rowHeight
+	}
+	rl.EndScissorMode()
+}
 
-	// check if markets open
-	if now.Before(marketOpen) {
-		marketTimer = "Market is not open yet."
-		color = rl.Red
-	} else if now.After(marketClose) {
-		marketTimer = "Market is now closed."
-		color = rl.Red
-	} else {
-		elapsed := now.Sub(marketOpen)
-		totalMarketTime := marketClose.Sub(marketOpen)
-		remaining := totalMarketTime - elapsed
-
-		marketTimer = fmt.Sprintf("Time until close: %02d:%02d:%02d",
-		int(remaining.Hours()),
-		int(remaining.Minutes())%60,
-		int(remaining.Seconds())%60,
-		)
-		color = rl.Green
+// renderFundamentalsHistory draws one series as bars, arrows flip
+// through the series finnhub had for the symbol
+func (app *App) renderFundamentalsHistory(p *layout.Panel, data *fundamentals.Fundamentals, x, y float32) {
+	names := data.SeriesNames()
+	if len(names) == 0 {
+		drawText("No history reported", int32(x), int32(y), textLabel, colors.Muted)
+		return
+	}
+	if gui.Button(rl.NewRectangle(x, y, 18, 18), "<") {
+		app.seriesIndex--
+	}
+	if gui.Button(rl.NewRectangle(x+160, y, 18, 18), ">") {
+		app.seriesIndex++
 	}
+	app.seriesIndex = (app.seriesIndex%len(names) + len(names)) % len(names)
+	name := names[app.seriesIndex]
+	drawText(fitText(name, textBody, 130), int32(x+24), int32(y+2), textBody, colors.Accent)
 
-	rl.DrawText(marketTimer, 440, 20, 35, color)
-}
+	period := int32(0)
+	if app.seriesQuarterly {
+		period = 1
+	}
+	app.seriesQuarterly = gui.ToggleGroup(rl.NewRectangle(x+190, y, 40, 18), "Ann;Qtr", period) == 1
+	y += 24
 
-func (app *App) handleSymbolClick(x, y float32) {
-	// Adjust for scrolling offset and panel title height
-    adjustedY := y + app.scrollOffset - 30 // 30 is the panel title height
+	points := data.Series[name].Annual
+	if app.seriesQuarterly {
+		points = data.Series[name].Quarterly
+	}
+	if len(points) == 0 {
+		drawText("No data for this period", int32(x), int32(y), textBody, colors.Muted)
+		return
+	}
+	if len(points) > 12 {
+		points = points[len(points)-12:]
+	}
 
-    // Calculate which symbol was clicked based on adjusted Y position
-    symbolIndex := int(adjustedY / 25)
-    
-    // Ensure the index is within bounds
-    if symbolIndex >= 0 && symbolIndex < len(app.symbolOrder) {
-        newSymbol := app.symbolOrder[symbolIndex]
-        if newSymbol != app.selectedSymbol {
-            log.Printf("Switching from %s to %s", app.selectedSymbol, newSymbol)
-            app.selectedSymbol = newSymbol
-            app.engine.Send(app.finnhubClient, newSymbol)
-            delete(app.trades, strings.ToLower(newSymbol))
-        }
-    }
-}
+	high, low := 0.0, 0.0
+	for _, p := range points {
+		high = math.Max(high, p.Value)
+		low = math.Min(low, p.Value)
+	}
+	if high == low {
+		high += 1
+	}
 
-// i got lazy and annoyed trying to handle quotes so i just used
-// the finnhub package cause they already did it so why not
-func (app *App) handleQuotes() {
-	client, err := NewFinnhubClient(os.Getenv("API_KEY"))
-    if err != nil {
-        log.Fatalf("Failed to initialize Finnhub client: %v", err)
-    }
+	chartH := p.Y + p.H - y - 30
+	chartW := p.W - 20
+	step := chartW / float32(len(points))
+	zeroY := y + float32(high/(high-low))*chartH
+	for i, p := range points {
+		barColor := colors.Up
+		if p.Value < 0 {
+			barColor = colors.Down
+		}
+		barX := x + float32(i)*step
+		barY := y + float32((high-math.Max(p.Value, 0))/(high-low))*chartH
+		barH := float32(math.Abs(p.Value)/(high-low)) * chartH
+		rl.DrawRectangle(int32(barX+1), int32(barY), int32(step-2), int32(math.Max(1, float64(barH))), barColor)
+	}
+	rl.DrawLine(int32(x), int32(zeroY), int32(x+chartW), int32(zeroY), colors.Menu)
 
-	for {
-        if app.selectedSymbol != "" {
-            fullSymbol, exists := app.availableSymbols[app.selectedSymbol]
-            if exists {
-                // Get quote from Finnhub
-                quote, _, err := client.Client.Quote(context.Background()).Symbol(fullSymbol).Execute()
-
-                if err != nil {
-                    log.Printf("Error fetching quote for %s: %v", fullSymbol, err)
-                    continue
-                }
-
-                // Update the quote in app state
-                app.quotes[app.selectedSymbol] = event.Quote{
-                    Pair: event.Pair{
-                        Exchange: "finnhub",
-                        Symbol:   app.selectedSymbol,
-                    },
-                    Current:   quote.GetC(),
-                    High:      quote.GetH(),
-                    Low:       quote.GetL(),
-                    Open:      quote.GetO(),
-                    PrevClose: quote.GetPc(),
-                }
-            } else {
-                log.Printf("Symbol %s not found in available symbols map", app.selectedSymbol)
-            }
-        }
-        
-        time.Sleep(2 * time.Second)
-    }
+	first, last := points[0], points[len(points)-1]
+	bottom := int32(y + chartH + 4)
+	drawText(first.Period.Format("2006-01"), int32(x), bottom, textSmall, colors.Muted)
+	lastLabel := fmt.Sprintf("%s  %.2f", last.Period.Format("2006-01"), last.Value)
+	drawText(lastLabel, int32(x+chartW)-measureText(lastLabel, textSmall), bottom, textSmall, colors.Text)
 }
 
-func (app *App) handleMarketStatus() {
-	client, err := NewFinnhubClient(os.Getenv("API_KEY"))
-    if err != nil {
-        log.Fatalf("Failed to initialize Finnhub client: %v", err)
-    }
+func (app *App) renderPeers(p *layout.Panel, symbol string, x, y float32) {
+	peers, ok := app.hub.Peers(symbol)
+	if !ok {
+		drawText("Loading peers...", int32(x), int32(y), textLabel, colors.Muted)
+		return
+	}
 
-	for {
-		res, _, err := client.Client.MarketStatus(context.Background()).Exchange("US").Execute()
-		if err != nil {
-			log.Printf("Could not get market status from Exchange: %v", err)
-		}
+	comparison := app.hub.Compare(append([]string{symbol}, peers...))
+	colWidth := (p.W - 70) / float32(len(comparison.Metrics))
+	header := func(label string, i int) {
+		drawText(fitText(label, textTiny, colWidth-4), int32(x+60+float32(i)*colWidth), int32(y), textTiny, colors.Muted)
+	}
+	for i, m := range comparison.Metrics {
+		header(m.Label, i)
+	}
+	y += 14
 
-		// update market status in app state
-		app.marketStatus["US"] = event.MarketStatus{
-			Pair: event.Pair{
-				Exchange: "finnhub",
-				Symbol: "MarketStatus",
-			},
-			IsOpen: res.GetIsOpen(),
-			Session: res.GetSession(),
+	bottom := p.Y + p.H - 18
+	row := func(label string, values []float64, rowColor rl.Color) {
+		drawText(fitText(label, textSmall, 56), int32(x), int32(y), textSmall, rowColor)
+		for i, m := range comparison.Metrics {
+			drawText(m.Format(values[i]), int32(x+60+float32(i)*colWidth), int32(y), textSmall, rowColor)
 		}
-
-		time.Sleep(2 * time.Second)
+		y += 14
 	}
+	for i, r := range comparison.Rows {
+		if y > bottom {
+			break
+		}
+		rowColor := colors.Text
+		if i == 0 {
+			rowColor = colors.Accent
+		}
+		row(r.Symbol, r.Values, rowColor)
+	}
+	row("Median", comparison.Median, colors.Info)
 }
 
-func (app *App) handleRecommendationTrends() {
-	client, err := NewFinnhubClient(os.Getenv("API_KEY"))
-    if err != nil {
-        log.Fatalf("Failed to initialize Finnhub client: %v", err)
-    }
+// selectedPair is the selected symbol as the full finnhub pair, which is
+// what the calendar needs to tell crypto from stocks
+func (app *App) selectedPair() event.Pair {
+	return app.hub.SelectedPair()
+}
 
-	for {
-		if app.selectedSymbol != "" {
-			fullSymbol, exists := app.availableSymbols[app.selectedSymbol]
-			if exists {
-				trends, _, err := client.Client.RecommendationTrends(context.Background()).Symbol(fullSymbol).Execute()
-				if err != nil {
-                    log.Printf("Error fetching trends for %s: %v", fullSymbol, err)
-                    continue
-                }
-
-				for _, trend := range trends {
-					app.recommendationTrends[app.selectedSymbol] = event.RecommendationTrends{
-						Pair: event.Pair{
-							Exchange: "finnhub",
-							Symbol: app.selectedSymbol,
-						},
-						Buy: trend.GetBuy(),
-						Sell: trend.GetSell(),
-						Hold: trend.GetHold(),
-						StrongBuy: trend.GetStrongBuy(),
-						StrongSell: trend.GetStrongSell(),
-					}
-				}
+// renderMarketStatus shows the status of the selected symbol's venue.
+// finnhub's status wins when we have one since it knows about unscheduled
+// closures, otherwise it falls back to the calendar.
+func (app *App) renderMarketStatus() {
+	exchange := calendar.For(app.selectedPair())
+	now := time.Now()
+	isOpen, session := app.hub.Status(exchange, now)
+
+	statusStr := fmt.Sprintf("%s: %s", exchange.Code,
+		map[bool]string{true: "Open", false: "Closed"}[isOpen])
+	statusColor := colors.Up
+	if !isOpen {
+		statusColor = colors.Down
+	}
+	// kept to the top right whatever the window's width
+	right := int32(screenWidth())
+	drawText(statusStr, right-200, 7, textTitle, statusColor)
+
+	sessionStr := fmt.Sprintf("Session: %s", session)
+	drawText(sessionStr, right-220, 25, textTitle, colors.Text)
+
+	app.renderExchangeStrip(now)
+}
+
+// renderExchangeStrip draws one compact entry per venue in the symbol list,
+// with today's hours in the venue's own timezone
+func (app *App) renderExchangeStrip(now time.Time) {
+	x := int32(440)
+	y := int32(60)
+	fontSize := int32(textLabel)
+
+	for _, exchange := range app.hub.Exchanges {
+		isOpen, _ := app.hub.Status(exchange, now)
+
+		var hours string
+		switch {
+		case exchange.AlwaysOpen:
+			hours = "24/7"
+		default:
+			open, close, ok := exchange.TradingHours(now)
+			if !ok {
+				// show when it opens next instead
+				next := exchange.NextOpen(now).In(exchange.Location)
+				hours = fmt.Sprintf("opens %s", next.Format("Mon 15:04 MST"))
 			} else {
-				log.Printf("Symbol %s not found in available symbols map", app.selectedSymbol)
+				local := open.In(exchange.Location)
+				hours = fmt.Sprintf("%s-%s %s", local.Format("15:04"),
+					close.In(exchange.Location).Format("15:04"), local.Format("MST"))
 			}
 		}
 
-		time.Sleep(2 * time.Second)
+		entry := fmt.Sprintf("%s %s", exchange.Code, hours)
+		entryColor := colors.Down
+		if isOpen {
+			entryColor = colors.Up
+		}
+		drawText(entry, x, y, fontSize, entryColor)
+		x += measureText(entry, fontSize) + 20
 	}
 }
 
-func (app *App) handleSymbolMetric() {
-	client, err := NewFinnhubClient(os.Getenv("API_KEY"))
-	if err != nil {
-		log.Print("Error connecting to finnhub client in symbol metric: %v", err)
+func (app *App) handleMarketTimer() {
+	marketTimer, session := hub.MarketTimer(calendar.For(app.selectedPair()), time.Now())
+
+	var color rl.Color
+	switch session {
+	case calendar.Continuous, calendar.Regular:
+		color = colors.Up
+	case calendar.PreMarket, calendar.AfterHours:
+		color = colors.Warning
+	default:
+		color = colors.Down
+	}
+
+	drawText(marketTimer, 440, 20, textClock, color)
+}
+
+// handleChartLogic draws the panel's symbol's bars and returns the range
+// they cover, zero when there are none
+func (app *App) handleChartLogic(p *layout.Panel) (from, to time.Time) {
+	panelX := p.X
+	panelY := p.Y
+
+	// timeframe picker along the top of the panel
+	x := panelX + 10
+	for _, tf := range candle.Timeframes {
+		if gui.Toggle(rl.NewRectangle(x, panelY+30, 40, 20), tf.Name, tf == app.timeframe) && tf != app.timeframe {
+			app.timeframe = tf
+		}
+		x += 45
+	}
+
+	pair := app.pairFor(p)
+	if pair.Symbol == "" {
+		return
+	}
+	app.ensureBars(pair.Symbol)
+	bars := app.hub.Candles.Bars(pair, app.timeframe)
+	if len(bars) == 0 {
+		drawText("Waiting for bars...", int32(panelX+20), int32(panelY+70), textTitle, colors.Muted)
 		return
 	}
 
-	for {
-		if app.selectedSymbol != "" {
-			fullSymbol, exists := app.availableSymbols[app.selectedSymbol]
-			if exists {
-				res, _, err := client.Client.CompanyBasicFinancials(context.Background()).Symbol(fullSymbol).Metric("all").Execute()
-				if err != nil {
-					log.Print("Error getting company basic financials: %v", err)
-					return
-				}
+	// chart area below the picker, right side keeps room for the price scale
+	chartX := panelX + 10
+	chartY := panelY + 60
+	chartW := p.W - 80
+	chartH := p.H - 70
+
+	barWidth := float32(6)
+	step := barWidth + 2
+	visible := int(chartW / step)
+	if len(bars) > visible {
+		bars = bars[len(bars)-visible:]
+	}
+	// the export menu exports what's on screen
+	from = time.Unix(bars[0].Unix, 0)
+	to = time.Unix(bars[len(bars)-1].Unix, 0).Add(app.timeframe.Duration)
+
+	high, low := bars[0].High, bars[0].Low
+	for _, bar := range bars {
+		high = math.Max(high, bar.High)
+		low = math.Min(low, bar.Low)
+	}
+	if high == low {
+		high += 1
+		low -= 1
+	}
+	priceY := func(price float64) float32 {
+		return chartY + float32((high-price)/(high-low))*chartH
+	}
 
-				// parse and store the metrics
-				if res.Metric != nil {
-					metricsMap := *res.Metric // deref the pointer to access the map
-
-					// parse and store metrics
-					metrics := event.SymbolMetric{
-						Pair: event.Pair{
-							Exchange: "finnhub",
-							Symbol: app.selectedSymbol,
-						},
-						TenDayAverageTradingVolume: getFloatFromMap(metricsMap, "10DayAverageTradingVolume"),
-                        FiftyTwoWeekHigh:           getFloatFromMap(metricsMap, "52WeekHigh"),
-                        FiftyTwoWeekLow:            getFloatFromMap(metricsMap, "52WeekLow"),
-                        FiftyTwoWeekPriceReturnDaily: getFloatFromMap(metricsMap, "52WeekPriceReturnDaily"),
-					}
+	for i, bar := range bars {
+		barColor := colors.Up
+		if bar.Close < bar.Open {
+			barColor = colors.Down
+		}
+		x := chartX + float32(i)*step
+
+		// wick, then body
+		rl.DrawLineV(rl.NewVector2(x+barWidth/2, priceY(bar.High)), rl.NewVector2(x+barWidth/2, priceY(bar.Low)), barColor)
+		top := priceY(math.Max(bar.Open, bar.Close))
+		bodyHeight := max(1, int(priceY(math.Min(bar.Open, bar.Close))-top))
+		rl.DrawRectangle(int32(x), int32(top), int32(barWidth), int32(bodyHeight), barColor)
+	}
 
-					// store metrics in the apps map
-					app.symbolMetrics[app.selectedSymbol] = metrics
-					//log.Printf("Updated metrics for %s: %+v", app.selectedSymbol, metrics)
+	// earnings, dividend and split markers on the first bar of their day
+	if events, err := app.corporate.Get(pair.Symbol); err == nil && events != nil {
+		for _, item := range events.Items() {
+			for i, bar := range bars {
+				if bar.Unix < item.Unix || bar.Unix >= item.Unix+24*60*60 {
+					continue
 				}
-			} else {
-				log.Printf("Symbol %s not found in symbols map", app.selectedSymbol)
+				x := chartX + float32(i)*step
+				drawText(item.Kind.String()[:1], int32(x), int32(chartY+chartH-12), textSmall, corporateColor(item.Kind))
+				break
 			}
 		}
-		time.Sleep(2 * time.Second)
 	}
+
+	scaleX := int32(chartX + chartW + 10)
+	drawText(fmt.Sprintf("%.2f", high), scaleX, int32(chartY), textBody, colors.Text)
+	drawText(fmt.Sprintf("%.2f", low), scaleX, int32(chartY+chartH-14), textBody, colors.Text)
+	last := bars[len(bars)-1]
+	drawText(fmt.Sprintf("%.2f", last.Close), scaleX, int32(priceY(last.Close)-7), textBody, colors.Accent)
+	return from, to
 }
 
-// helper func for symbolMetrics
-func getFloatFromMap(metrics map[string]interface{}, key string) float64 {
-	if val, ok := metrics[key]; ok {
-		if floatVal, valid := val.(float64); valid {
-			return floatVal
+// where the export menu writes its files
+var exportDir = filepath.Join("data", "exports")
+
+// handleExportMenu is the Export button on the chart, it exports the
+// chart's symbol's trades, quotes or bars over from, to
+func (app *App) handleExportMenu(p *layout.Panel, from, to time.Time) {
+	panelX := p.X
+	panelY := p.Y
+	x := panelX + p.W - 70
+
+	if gui.Button(rl.NewRectangle(x, panelY+30, 60, 20), "Export") {
+		if app.exportMenu == p.ID {
+			app.exportMenu = ""
+		} else {
+			app.exportMenu = p.ID
 		}
 	}
+	if status := app.exportStatus.Load(); status != nil {
+		// between the timeframe picker and the button
+		drawText(fitText(*status, textSmall, x-panelX-300), int32(panelX+290), int32(panelY+34), textSmall, colors.Subtle)
+	}
+	if app.exportMenu != p.ID {
+		return
+	}
 
-	return 0
-}
-
-// helper func (idk wtf it does)
-func max(a, b int) int {
-	if a > b {
-		return a
+	// one row per kind, one column per format, drawn over the chart
+	menuW := float32(len(export.Formats))*65 + 70
+	menuX := panelX + p.W - menuW - 10
+	menuY := panelY + 55
+	rl.DrawRectangle(int32(menuX), int32(menuY), int32(menuW), int32(len(export.Kinds))*25+10, colors.Menu)
+	for i, kind := range export.Kinds {
+		y := menuY + 5 + float32(i)*25
+		drawText(string(kind), int32(menuX+5), int32(y+4), textBody, colors.Text)
+		for j, format := range export.Formats {
+			if gui.Button(rl.NewRectangle(menuX+70+float32(j)*65, y, 60, 20), string(format)) {
+				app.exportMenu = ""
+				app.startExport(app.pairFor(p), from, to, kind, format)
+			}
+		}
 	}
-	return b
 }
 
-type Panel struct {
-    position rl.Vector2
-    width, height float32
-    title string
-	isDragging bool // tracks panel being dragged
-	dragOffset rl.Vector2 // keeps the offset of the mouse relative to the panels top-left corner
-    onClick func(x, y float32) // add click handler
-}
+func (app *App) startExport(pair event.Pair, from, to time.Time, kind export.Kind, format export.Format) {
+	setStatus := func(status string) { app.exportStatus.Store(&status) }
+	if pair.Symbol == "" {
+		setStatus("Pick a symbol first")
+		return
+	}
+	if from.IsZero() {
+		setStatus("No bars on the chart to take the range from")
+		return
+	}
+	req := export.Request{
+		Kind: kind,
+		Format: format,
+		Pairs: []event.Pair{pair},
+		From: from,
+		To: to,
+		Timeframe: app.timeframe,
+	}
+	path := filepath.Join(exportDir, req.FileName())
+	setStatus("Exporting " + path)
 
-func NewPanel(x, y, width, height float32) *Panel {
-    return &Panel{
-        position: rl.NewVector2(x, y),
-        width: width,
-        height: height,
-		isDragging: false,
-		dragOffset: rl.NewVector2(0, 0),
-        onClick: nil, // will be set later
-    }
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		n, err := app.export.WriteFile(ctx, path, req)
+		if err != nil {
+			log.Error("export failed", "path", path, "err", err)
+			setStatus("Export failed: " + err.Error())
+			return
+		}
+		log.Info("exported", "path", path, "rows", n)
+		setStatus(fmt.Sprintf("Wrote %d rows to %s", n, path))
+	}()
 }
 
-// generic panel move func since update was being annoying
-func (p *Panel) HandlePanelDrag(x, y float32) {
-    mouseX := float32(rl.GetMouseX())
-    mouseY := float32(rl.GetMouseY())
+// handleGridLogic draws 2x2 or 3x3 tiles of different symbols, each its
+// quote over a line of its closes. clicking a tile picks its symbol for
+// the grid's link group, so a linked chart shows it in full
+func (app *App) handleGridLogic(p *layout.Panel) {
+	x := p.X + 10
+	y := p.Y + 30
 
-    if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-        // Check if click is within panel bounds
-        if mouseX >= p.position.X && mouseX <= p.position.X+p.width &&
-            mouseY >= p.position.Y && mouseY <= p.position.Y+p.height {
-            p.isDragging = true
-            p.dragOffset = rl.NewVector2(mouseX-p.position.X, mouseY-p.position.Y)
-        }
-    }
-
-    if p.isDragging {
-        p.position.X = mouseX - p.dragOffset.X
-        p.position.Y = mouseY - p.dragOffset.Y
-    }
+	size := int32(0)
+	if p.GridSize == 3 {
+		size = 1
+	}
+	p.GridSize = int(gui.ToggleGroup(rl.NewRectangle(x, y, 40, 18), "2x2;3x3", size)) + 2
+	drawText("< > change a tile, click one to select it", int32(x+90), int32(y+3), textSmall, colors.Muted)
+	app.fillTiles(p)
+	y += 24
+
+	tileW := (p.W - 20) / float32(p.GridSize)
+	tileH := (p.Y + p.H - 5 - y) / float32(p.GridSize)
+	for i, symbol := range gridTiles(p) {
+		col, row := float32(i%p.GridSize), float32(i/p.GridSize)
+		tile := rl.NewRectangle(x+col*tileW+2, y+row*tileH+2, tileW-4, tileH-4)
+		if next := app.renderTile(p, symbol, tile); next != symbol {
+			p.Tiles[i] = next
+		}
+	}
+}
 
-    if rl.IsMouseButtonReleased(rl.MouseLeftButton) {
-        p.isDragging = false
-    }
+// gridTiles are the symbols of the tiles a grid shows, it keeps the rest
+// for when it goes back to 3x3
+func gridTiles(p *layout.Panel) []string {
+	n := max(2, p.GridSize)
+	return p.Tiles[:min(len(p.Tiles), n*n)]
+}
 
-    // Ensure the panel stays within the window bounds
-    if p.position.X < 0 {
-        p.position.X = 0
-    }
-    if p.position.Y < 0 {
-        p.position.Y = 0
-    }
-    if p.position.X+p.width > float32(rl.GetScreenWidth()) {
-        p.position.X = float32(rl.GetScreenWidth()) - p.width
-    }
-    if p.position.Y+p.height > float32(rl.GetScreenHeight()) {
-        p.position.Y = float32(rl.GetScreenHeight()) - p.height
-    }
+// fillTiles gives a grid's empty tiles symbols it doesn't show yet, in
+// watchlist order
+func (app *App) fillTiles(p *layout.Panel) {
+	n := p.GridSize * p.GridSize
+	shown := make(map[string]bool)
+	for _, symbol := range p.Tiles {
+		shown[symbol] = true
+	}
+	for _, symbol := range app.hub.Order {
+		if len(p.Tiles) >= n {
+			return
+		}
+		if !shown[symbol] {
+			p.Tiles = append(p.Tiles, symbol)
+		}
+	}
 }
 
-func (p *Panel) update() {
-	mouseX := float32(rl.GetMouseX())
-	mouseY := float32(rl.GetMouseY())
+// renderTile draws one grid tile and returns its symbol, changed when
+// one of its arrows was clicked
+func (app *App) renderTile(p *layout.Panel, symbol string, tile rl.Rectangle) string {
+	border := colors.Menu
+	if symbol == app.symbolFor(p) {
+		border = linkColor(p.Link)
+		if p.Link == "" {
+			border = colors.Accent
+		}
+	}
+	rl.DrawRectangleLinesEx(tile, 1, border)
+
+	// arrows step through the watchlist
+	step := func(by int) string {
+		i := slices.Index(app.hub.Order, symbol)
+		n := len(app.hub.Order)
+		return app.hub.Order[((i+by)%n+n)%n]
+	}
+	if gui.Button(rl.NewRectangle(tile.X+2, tile.Y+2, 16, 16), "<") {
+		return step(-1)
+	}
+	if gui.Button(rl.NewRectangle(tile.X+tile.Width-18, tile.Y+2, 16, 16), ">") {
+		return step(1)
+	}
+	drawText(fitText(symbol, textBody, tile.Width-44), int32(tile.X+22), int32(tile.Y+3), textBody, colors.Accent)
 
-    if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-        // Check if click is within panel bounds
-        if mouseX >= p.position.X && mouseX <= p.position.X+p.width &&
-           mouseY >= p.position.Y && mouseY <= p.position.Y+p.height {
-			p.onClick(mouseX - p.position.X, mouseY - p.position.Y)
-            p.isDragging = true
-            p.dragOffset = rl.NewVector2(mouseX-p.position.X, mouseY-p.position.Y)
-        }
-    }
+	pair := event.Pair{Exchange: "finnhub", Symbol: symbol}
+	app.ensureBars(symbol)
+	bars := app.hub.Candles.Bars(pair, app.timeframe)
 
-	if p.isDragging {
-		p.position.X = mouseX - p.dragOffset.X
-		p.position.Y = mouseY - p.dragOffset.Y
+	// live trades move the last bar before the next quote comes in
+	quote, haveQuote := app.hub.Quote(symbol)
+	price := float64(quote.Current)
+	if len(bars) > 0 {
+		price = bars[len(bars)-1].Close
+	}
+	if price != 0 {
+		line := fmt.Sprintf("%.2f", price)
+		lineColor := colors.Text
+		if haveQuote && quote.PrevClose != 0 {
+			prevClose := float64(quote.PrevClose)
+			change := (price - prevClose) / prevClose * 100
+			line += fmt.Sprintf("  %+.2f%%", change)
+			lineColor = colors.Up
+			if change < 0 {
+				lineColor = colors.Down
+			}
+		}
+		drawText(line, int32(tile.X+6), int32(tile.Y+22), textBody, lineColor)
 	}
 
-	if rl.IsMouseButtonReleased(rl.MouseLeftButton) {
-		p.isDragging = false
+	chart := rl.NewRectangle(tile.X+6, tile.Y+42, tile.Width-12, tile.Height-48)
+	if app.hovered(p) && rl.IsMouseButtonPressed(rl.MouseLeftButton) &&
+		rl.CheckCollisionPointRec(rl.GetMousePosition(), rl.NewRectangle(tile.X, tile.Y+20, tile.Width, tile.Height-20)) {
+		app.selectFor(p, symbol)
+	}
+	if chart.Height < 10 {
+		return symbol
+	}
+	if len(bars) < 2 {
+		drawText("Waiting for bars...", int32(chart.X), int32(chart.Y), textSmall, colors.Muted)
+		return symbol
 	}
 
-	if p.position.X < 0 {
-		p.position.X = 0
+	// two pixels a bar, the most recent that fit
+	if visible := int(chart.Width / 2); len(bars) > visible {
+		bars = bars[len(bars)-visible:]
+	}
+	high, low := bars[0].Close, bars[0].Close
+	for _, bar := range bars {
+		high = math.Max(high, bar.Close)
+		low = math.Min(low, bar.Close)
 	}
-	if p.position.Y < 0 {
-		p.position.Y = 0
+	if high == low {
+		high += 1
+		low -= 1
 	}
-	if p.position.X+p.width > float32(rl.GetScreenWidth()) {
-		p.position.X = float32(rl.GetScreenWidth()) - p.width
+	lineColor := colors.Up
+	if bars[len(bars)-1].Close < bars[0].Close {
+		lineColor = colors.Down
 	}
-	if p.position.Y+p.height > float32(rl.GetScreenHeight()) {
-		p.position.Y = float32(rl.GetScreenHeight()) - p.height
+	stepX := chart.Width / float32(len(bars)-1)
+	point := func(i int) rl.Vector2 {
+		return rl.NewVector2(chart.X+float32(i)*stepX, chart.Y+float32((high-bars[i].Close)/(high-low))*chart.Height)
 	}
+	for i := 1; i < len(bars); i++ {
+		rl.DrawLineV(point(i-1), point(i), lineColor)
+	}
+	return symbol
 }
 
-func (p *Panel) getDrawPos(x, y float32) rl.Vector2 {
-    return rl.NewVector2(p.position.X+x, p.position.Y+24+y+p.height)
+// orderForm is the state of the paper trading order entry
+type orderForm struct {
+	qty string
+	limit string
+	stop string
+	orderType int32
+	tif int32
+	editing int // which text box has focus, see the form* consts
+	message string
+	messageColor rl.Color
 }
 
-func (p *Panel) render() {
-    // Draw solid black background
-    rl.DrawRectangle(int32(p.position.X), int32(p.position.Y), int32(p.width), int32(p.height), rl.Black)
+const (
+	formNone = iota
+	formQty
+	formLimit
+	formStop
+)
 
-    // Render the panel with its title
-    gui.Panel(rl.NewRectangle(p.position.X, p.position.Y, p.width, p.height), p.title)
+func (app *App) handlePaperLogic(p *layout.Panel) {
+	if app.paper == nil {
+		return
+	}
+	form := &app.orderForm
+	panelX := p.X
+	panelY := p.Y
+	x := panelX + 10
+	y := panelY + 30
+
+	symbol := app.symbolFor(p)
+	title := "No symbol selected"
+	if symbol != "" {
+		title = fmt.Sprintf("Order %s", symbol)
+	}
+	drawText(title, int32(x), int32(y), textHeading, colors.Accent)
+	y += 25
+
+	form.orderType = gui.ToggleGroup(rl.NewRectangle(x, y, 60, 20), "MKT;LMT;STP;STL", form.orderType)
+	y += 25
+	form.tif = gui.ToggleGroup(rl.NewRectangle(x, y, 60, 20), "GTC;DAY;IOC;FOK", form.tif)
+	y += 30
+
+	textBox := func(label string, field int, text *string, bx, by float32) {
+		drawText(label, int32(bx), int32(by+4), textBody, colors.Text)
+		if gui.TextBox(rl.NewRectangle(bx+40, by, 70, 22), text, 16, form.editing == field) {
+			if form.editing == field {
+				form.editing = formNone
+			} else {
+				form.editing = field
+			}
+		}
+	}
+	textBox("Qty", formQty, &form.qty, x, y)
+	y += 28
+	textBox("Limit", formLimit, &form.limit, x, y)
+	textBox("Stop", formStop, &form.stop, x+125, y)
+	y += 32
+
+	if gui.Button(rl.NewRectangle(x, y, 120, 26), "Buy") {
+		app.submitPaperOrder(symbol, paper.Buy)
+	}
+	if gui.Button(rl.NewRectangle(x+130, y, 120, 26), "Sell") {
+		app.submitPaperOrder(symbol, paper.Sell)
+	}
+	y += 32
 
-    // Optional: Add a visual marker (e.g., red dot) for debugging
-    pos := p.getDrawPos(10, 10)
-    rl.DrawCircle(int32(pos.X), int32(pos.Y), 2, rl.Red)
-}
+	if form.message != "" {
+		drawText(form.message, int32(x), int32(y), textBody, form.messageColor)
+	}
+	y += 22
 
-func main() {
-    e, err := actor.NewEngine(actor.NewEngineConfig())
-    if err != nil {
-        log.Fatal(err)
-    }
-    tradeCh := make(chan event.StockTrade)
-    app := NewApp(tradeCh, e)
-    go app.start()
+	snap := app.paper.Snapshot()
+	pnlColor := func(v float64) rl.Color {
+		if v < 0 {
+			return colors.Down
+		}
+		return colors.Up
+	}
+	drawText(fmt.Sprintf("Cash: %.2f  Equity: %.2f", snap.Cash, snap.Equity), int32(x), int32(y), textBody, colors.Text)
+	y += 18
+	drawText(fmt.Sprintf("Realized: %.2f  Fees: %.2f", snap.RealizedPnL, snap.Fees), int32(x), int32(y), textBody, pnlColor(snap.RealizedPnL))
+	y += 18
+	drawText(fmt.Sprintf("Unrealized: %.2f", snap.UnrealizedPnL), int32(x), int32(y), textBody, pnlColor(snap.UnrealizedPnL))
+	y += 22
+
+	for _, pos := range snap.Positions {
+		if y > panelY+p.H-60 {
+			break
+		}
+		posStr := fmt.Sprintf("%s %g @ %.2f (%.2f)", strings.ToUpper(pos.Symbol), pos.Qty, pos.AvgPrice, pos.UnrealizedPnL)
+		drawText(posStr, int32(x), int32(y), textBody, pnlColor(pos.UnrealizedPnL))
+		y += 18
+	}
 
-    // store the pid when spawning
-    finnhubPID := e.Spawn(finnhub.New(tradeCh), "finnhub")
-    app.finnhubClient = finnhubPID
+	// open orders, each with a cancel button
+	for _, order := range app.paper.Orders(true) {
+		if y > panelY+p.H-20 {
+			break
+		}
+		if gui.Button(rl.NewRectangle(x, y, 16, 16), "x") {
+			if err := app.paper.Cancel(order.ID); err != nil {
+				form.message, form.messageColor = err.Error(), colors.Down
+			}
+		}
+		drawText(order.String(), int32(x+22), int32(y+1), textSmall, colors.Subtle)
+		y += 18
+	}
+}
 
-    rl.InitWindow(1200, 800, "Stock Spider")
-    defer rl.CloseWindow()
-    rl.SetTargetFPS(60)
-    gui.SetStyle(0, gui.BACKGROUND_COLOR, 0x000000ff)
+func (app *App) submitPaperOrder(symbol string, side paper.Side) {
+	form := &app.orderForm
+	if symbol == "" {
+		form.message, form.messageColor = "Pick a symbol first", colors.Down
+		return
+	}
+
+	parse := func(text string) float64 {
+		v, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	order := paper.Order{
+		Symbol: symbol,
+		Side: side,
+		Type: paper.OrderTypes[form.orderType],
+		TIF: paper.TimeInForces[form.tif],
+		Qty: parse(form.qty),
+		LimitPrice: parse(form.limit),
+		StopPrice: parse(form.stop),
+	}
+
+	placed, err := app.paper.Submit(order)
+	if err != nil {
+		form.message, form.messageColor = err.Error(), colors.Down
+		return
+	}
+	log.Info("paper order placed", "id", placed.ID, "order", placed.String())
+	form.message, form.messageColor = fmt.Sprintf("%s placed", placed.ID), colors.Up
+}
+
+// handlePaperExpiry kills day orders for symbols that stopped trading,
+// the engine only checks expiry for a symbol when a trade comes in
+func (app *App) handlePaperExpiry() {
+	for {
+		if app.paper != nil {
+			if err := app.paper.Expire(time.Now()); err != nil {
+				log.Error("saving paper account after expiring orders", "err", err)
+			}
+		}
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// where imported portfolios are kept
+var portfolioDir = filepath.Join("data", "portfolios")
+
+// holdingSymbols are the symbols held in the active portfolio
+func (app *App) holdingSymbols() []string {
+	if symbols := app.holdings.Load(); symbols != nil {
+		return *symbols
+	}
+	return nil
+}
+
+// updateHoldings hands the hub the active portfolio's symbols. the
+// portfolios belong to the render loop, the hub polls from its own
+// goroutine and only ever sees this copy
+func (app *App) updateHoldings() {
+	var symbols []string
+	if int(app.activePortfolio) < len(app.portfolios) {
+		symbols = app.portfolios[app.activePortfolio].Symbols()
+	}
+	app.holdings.Store(&symbols)
+}
+
+// handleDroppedFiles imports csv files dropped on the window as portfolios
+// named after the file, replacing a portfolio of the same name
+func (app *App) handleDroppedFiles() {
+	if !rl.IsFileDropped() {
+		return
+	}
+	files := rl.LoadDroppedFiles()
+	rl.UnloadDroppedFiles()
+
+	for _, path := range files {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ttf", ".otf":
+			app.setFont(path)
+			continue
+		case ".csv":
+		default:
+			continue
+		}
+		if err := app.importPortfolio(path); err != nil {
+			log.Error("importing portfolio", "path", path, "err", err)
+			app.portfolioMessage = err.Error()
+		}
+	}
+}
+
+func (app *App) importPortfolio(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	txs, err := portfolio.Import(f, portfolio.ImportOptions{
+		// "ETH" in a broker export should price like our ETH
+		Symbol: app.hub.FullSymbol,
+	})
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	p := portfolio.New(name, portfolio.FIFO)
+	if err := p.Add(txs...); err != nil {
+		// still keep it, the message tells the user what didn't add up
+		app.portfolioMessage = err.Error()
+	} else {
+		app.portfolioMessage = fmt.Sprintf("Imported %d rows into %s", len(txs), name)
+	}
+	if err := portfolio.Save(portfolioDir, p); err != nil {
+		return err
+	}
+
+	for i, existing := range app.portfolios {
+		if strings.EqualFold(existing.Name, name) {
+			app.portfolios[i] = p
+			app.activePortfolio = int32(i)
+			app.updateHoldings()
+			return nil
+		}
+	}
+	app.portfolios = append(app.portfolios, p)
+	app.activePortfolio = int32(len(app.portfolios) - 1)
+	app.updateHoldings()
+	log.Info("imported portfolio", "name", name, "transactions", len(txs))
+	return nil
+}
+
+func (app *App) handlePortfolioLogic(p *layout.Panel) {
+	panelX := p.X
+	panelY := p.Y
+	x := panelX + 10
+	y := panelY + 30
+
+	if len(app.portfolios) == 0 {
+		drawText("Drop a holdings CSV on the", int32(x), int32(y), textLabel, colors.Muted)
+		drawText("window to import it", int32(x), int32(y+20), textLabel, colors.Muted)
+		if app.portfolioMessage != "" {
+			drawText(app.portfolioMessage, int32(x), int32(y+45), textSmall, colors.Down)
+		}
+		return
+	}
+
+	names := make([]string, len(app.portfolios))
+	for i, p := range app.portfolios {
+		names[i] = p.Name
+	}
+	tabWidth := min(90, (p.W-20)/float32(len(names))-2)
+	previous := app.activePortfolio
+	app.activePortfolio = gui.ToggleGroup(rl.NewRectangle(x, y, tabWidth, 18), strings.Join(names, ";"), app.activePortfolio)
+	if int(app.activePortfolio) >= len(app.portfolios) {
+		app.activePortfolio = 0
+	}
+	if app.activePortfolio != previous {
+		app.updateHoldings()
+	}
+	active := app.portfolios[app.activePortfolio]
+	y += 22
+
+	// tax lot method, lots get replayed when it changes
+	methods := []portfolio.Method{portfolio.FIFO, portfolio.LIFO}
+	current := int32(0)
+	if active.Method == portfolio.LIFO {
+		current = 1
+	}
+	if picked := gui.ToggleGroup(rl.NewRectangle(x, y, 45, 16), "FIFO;LIFO", current); picked != current {
+		active.SetMethod(methods[picked])
+		if err := portfolio.Save(portfolioDir, active); err != nil {
+			log.Error("saving portfolio", "name", active.Name, "err", err)
+		}
+	}
+
+	value := active.Value(app.hub.Quotes())
+	pnlColor := func(v float64) rl.Color {
+		if v < 0 {
+			return colors.Down
+		}
+		return colors.Up
+	}
+	drawText(fmt.Sprintf("Value %.2f", value.MarketValue), int32(x+100), int32(y+1), textBody, colors.Text)
+	y += 20
+	drawText(fmt.Sprintf("Day %.2f", value.DayPnL), int32(x), int32(y), textBody, pnlColor(value.DayPnL))
+	drawText(fmt.Sprintf("Total %.2f", value.TotalPnL), int32(x+90), int32(y), textBody, pnlColor(value.TotalPnL))
+	drawText(fmt.Sprintf("Real. %.2f", value.Realized), int32(x+190), int32(y), textBody, pnlColor(value.Realized))
+	y += 18
+
+	// allocation by asset class as one stacked bar
+	classes := make([]string, 0, len(value.Allocation))
+	for class := range value.Allocation {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	classColors := colors.Series
+	barX := x
+	barWidth := p.W - 20
+	legendX := x
+	for i, class := range classes {
+		w := barWidth * float32(value.Allocation[class])
+		rl.DrawRectangle(int32(barX), int32(y), int32(w), 8, classColors[i%len(classColors)])
+		barX += w
+		legend := fmt.Sprintf("%s %.0f%%", class, value.Allocation[class]*100)
+		drawText(legend, int32(legendX), int32(y+10), textSmall, classColors[i%len(classColors)])
+		legendX += float32(measureText(legend, textSmall) + 10)
+	}
+	y += 28
+
+	for _, pos := range value.Positions {
+		if y > panelY+p.H-16 {
+			break
+		}
+		row := fmt.Sprintf("%-8s %8.2f %7.2f %4.1f%%", pos.Symbol, pos.MarketValue, pos.DayPnL, pos.Weight*100)
+		rowColor := pnlColor(pos.TotalPnL)
+		if !pos.Quoted {
+			rowColor = colors.Muted
+		}
+		drawText(row, int32(x), int32(y), textSmall, rowColor)
+		y += 14
+	}
+}
+
+// symbols corporate events get tracked for, stocks in the list first
+// then portfolio holdings. crypto doesn't have any
+func (app *App) corporateSymbols() []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(full string) {
+		if seen[full] || calendar.For(event.Pair{Exchange: "finnhub", Symbol: full}) == calendar.Crypto {
+			return
+		}
+		seen[full] = true
+		symbols = append(symbols, full)
+	}
+	if app.hub.Selected() != "" {
+		add(app.selectedPair().Symbol)
+	}
+	for _, fullSymbol := range app.hub.Order {
+		add(fullSymbol)
+	}
+	for _, holding := range app.holdingSymbols() {
+		add(holding)
+	}
+	return symbols
+}
+
+// corporate events change a few times a quarter
+const corporateTTL = 12 * time.Hour
+
+// handleCorporateEvents refreshes one stale symbol per round and applies
+// splits once they take effect, to the paper account and to the chart
+func (app *App) handleCorporateEvents() {
+	client, err := NewFinnhubClient(os.Getenv("API_KEY"))
+	if err != nil {
+		log.Error("creating finnhub client for corporate events", "err", err)
+		return
+	}
+
+	// splits that already happened are in what gets backfilled, only the
+	// ones taking effect while we run need the chart rebuilt
+	adjusted := make(map[string]bool)
+	splitKey := func(split event.Split) string {
+		return fmt.Sprintf("%s@%d", split.Pair.Symbol, split.Unix)
+	}
+	for _, symbol := range app.corporateSymbols() {
+		for _, split := range app.corporate.Splits(symbol) {
+			adjusted[splitKey(split)] = true
+		}
+	}
+
+	for {
+		now := time.Now()
+		for _, symbol := range app.corporateSymbols() {
+			stored, err := app.corporate.Get(symbol)
+			if err != nil {
+				log.Error("reading corporate events", "symbol", symbol, "err", err)
+				continue
+			}
+			if !stored.Stale(now, corporateTTL) {
+				continue
+			}
+
+			fresh, err := corporate.Fetch(context.Background(), client.Client, symbol, now)
+			if err != nil {
+				// partial data is still worth keeping
+				log.Warn("fetching corporate events", "symbol", symbol, "err", err)
+			}
+			if _, err := app.corporate.Put(fresh); err != nil {
+				log.Error("storing corporate events", "symbol", symbol, "err", err)
+			}
+			break
+		}
+
+		for _, symbol := range app.corporateSymbols() {
+			for _, split := range app.corporate.Splits(symbol) {
+				at := time.Unix(split.Unix, 0)
+				if at.After(now) {
+					continue
+				}
+				if app.paper != nil {
+					changed, err := app.paper.ApplySplit(strings.ToLower(symbol), at, corporate.Ratio(split))
+					if err != nil {
+						log.Error("applying split to paper account", "symbol", symbol, "err", err)
+					} else if changed {
+						log.Info("applied split to paper account", "symbol", symbol, "from", split.From, "to", split.To)
+					}
+				}
+				if !adjusted[splitKey(split)] {
+					adjusted[splitKey(split)] = true
+					pair := event.Pair{Exchange: "finnhub", Symbol: symbol}
+					app.hub.Candles.Reset(pair)
+					if app.selectedPair().Symbol == symbol {
+						go app.hub.BackfillSymbol(app.hub.Selected(), app.timeframe)
+					}
+				}
+			}
+		}
+
+		time.Sleep(15 * time.Second)
+	}
+}
+
+func corporateColor(kind corporate.Kind) rl.Color {
+	switch kind {
+	case corporate.EarningsKind:
+		return colors.Series[0]
+	case corporate.DividendKind:
+		return colors.Series[3]
+	}
+	return colors.Series[2]
+}
+
+// how far ahead the calendar panel looks
+const calendarWindow = 30 * 24 * time.Hour
+
+func (app *App) handleCalendarLogic(p *layout.Panel) {
+	panelX := p.X
+	panelY := p.Y
+	x := panelX + 10
+	y := panelY + 28
+
+	app.calendarFilter = gui.ToggleGroup(rl.NewRectangle(x, y, 50, 16), "All;Earn;Div;Split", app.calendarFilter)
+	y += 22
+
+	var events []*corporate.Events
+	for _, symbol := range app.corporateSymbols() {
+		if e, err := app.corporate.Get(symbol); err == nil && e != nil {
+			events = append(events, e)
+		}
+	}
+	items := corporate.Upcoming(events, time.Now(), calendarWindow)
+	if len(items) == 0 {
+		drawText("Nothing in the next 30 days", int32(x), int32(y), textBody, colors.Muted)
+		return
+	}
+
+	for _, item := range items {
+		if app.calendarFilter > 0 && corporate.Kind(app.calendarFilter-1) != item.Kind {
+			continue
+		}
+		if y > panelY+p.H-14 {
+			break
+		}
+		row := fmt.Sprintf("%s %-6s %s", item.Time().Format("01-02"), item.Symbol, item.Label)
+		drawText(fitText(row, textSmall, p.W-20), int32(x), int32(y), textSmall, corporateColor(item.Kind))
+		y += 14
+	}
+}
+
+func (app *App) handleNewsLogic(p *layout.Panel) {
+	panelX := p.X
+	panelY := p.Y
+	width := p.W
+	x := panelX + 10
+	y := panelY + 30
+
+	app.newsTab = gui.ToggleGroup(rl.NewRectangle(x, y, 60, 18), "Symbol;Market", app.newsTab)
+	y += 24
+
+	key := ""
+	if app.newsTab == 0 {
+		key = app.symbolFor(p)
+		if key == "" {
+			drawText("Pick a symbol for its news", int32(x), int32(y), textLabel, colors.Muted)
+			return
+		}
+	}
+	items := app.hub.News(key)
+	if len(items) == 0 {
+		drawText("No headlines yet...", int32(x), int32(y), textLabel, colors.Muted)
+		return
+	}
+
+	// picked headline gets its summary at the bottom of the panel
+	var selected *event.News
+	for i := range items {
+		if news.Key(items[i]) == app.selectedNews {
+			selected = &items[i]
+			break
+		}
+	}
+	listBottom := panelY + p.H - 5
+	if selected != nil {
+		listBottom = panelY + p.H - 130
+	}
+
+	rowHeight := float32(34)
+	mouse := rl.GetMousePosition()
+	listRect := rl.NewRectangle(panelX, y, width, listBottom-y)
+	if app.hovered(p) && rl.CheckCollisionPointRec(mouse, listRect) {
+		app.newsScroll -= rl.GetMouseWheelMove() * 20
+	}
+	maxScroll := float32(max(0, int(float32(len(items))*rowHeight-listRect.Height)))
+	if app.newsScroll < 0 {
+		app.newsScroll = 0
+	} else if app.newsScroll > maxScroll {
+		app.newsScroll = maxScroll
+	}
+
+	now := time.Now()
+	beginScissor(listRect.X, listRect.Y, listRect.Width, listRect.Height)
+	rowY := y - app.newsScroll
+	for _, item := range items {
+		if rowY+rowHeight >= listRect.Y && rowY <= listBottom {
+			itemKey := news.Key(item)
+			headlineColor := colors.Text
+			if itemKey == app.selectedNews {
+				headlineColor = colors.Accent
+			}
+			drawText(fitText(item.Headline, textBody, width-20), int32(x), int32(rowY), textBody, headlineColor)
+			meta := fmt.Sprintf("%s - %s", item.Source, formatAge(now, time.Unix(item.Unix, 0)))
+			drawText(meta, int32(x), int32(rowY+16), textSmall, colors.Muted)
+
+			rowRect := rl.NewRectangle(panelX, rowY, width, rowHeight)
+			if rl.IsMouseButtonPressed(rl.MouseLeftButton) && app.hovered(p) &&
+				rl.CheckCollisionPointRec(mouse, listRect) && rl.CheckCollisionPointRec(mouse, rowRect) {
+				if app.selectedNews == itemKey {
+					app.selectedNews = ""
+				} else {
+					app.selectedNews = itemKey
+				}
+			}
+		}
+		rowY += rowHeight
+	}
+	rl.EndScissorMode()
+
+	if selected == nil {
+		return
+	}
+	summaryY := listBottom + 5
+	rl.DrawLine(int32(panelX+5), int32(listBottom), int32(panelX+width-5), int32(listBottom), colors.Menu)
+	summary := selected.Summary
+	if summary == "" {
+		summary = "No summary, see " + selected.URL
+	}
+	for _, line := range wrapText(summary, textSmall, width-20) {
+		if summaryY > panelY+p.H-16 {
+			break
+		}
+		drawText(line, int32(x), int32(summaryY), textSmall, colors.Subtle)
+		summaryY += 14
+	}
+}
+
+// formatAge renders how long ago t was, "5m ago", "3h ago"...
+func formatAge(now, t time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// font sizes, in layout pixels like everything else, the UI scale turns
+// them into screen pixels
+const (
+	textTiny = 10
+	textSmall = 12
+	textBody = 14
+	textLabel = 16
+	textHeading = 18
+	textTitle = 20
+	textBig = 24
+	textClock = 35
+	textTicker = 40
+)
+
+// where the theme, font and zoom are saved
+var settingsPath = filepath.Join("data", "settings.json")
+
+// colors is the theme everything is drawn in, the View menu swaps it
+var colors = theme.Dark
+
+// scale is screen pixels per layout pixel, the monitor's DPI scale times
+// the zoom. everything is drawn and laid out unscaled, a camera and the
+// mouse scale take care of the rest
+var scale float32 = 1
+
+// font is the TTF or OTF from the settings, customFont is false while
+// text is in raylib's own
+var (
+	font rl.Font
+	customFont bool
+)
+
+// custom fonts are rasterized this big and scaled down, so text stays
+// sharp when zoomed
+const fontLoadSize = 96
+
+// drawText is rl.DrawText in the settings' font
+func drawText(text string, x, y, size int32, c rl.Color) {
+	if !customFont {
+		rl.DrawText(text, x, y, size, c)
+		return
+	}
+	rl.DrawTextEx(font, text, rl.NewVector2(float32(x), float32(y)), float32(size), textSpacing(size), c)
+}
+
+// measureText is rl.MeasureText in the settings' font
+func measureText(text string, size int32) int32 {
+	if !customFont {
+		return rl.MeasureText(text, size)
+	}
+	return int32(rl.MeasureTextEx(font, text, float32(size), textSpacing(size)).X)
+}
+
+// textSpacing is the gap between letters rl.DrawText leaves
+func textSpacing(size int32) float32 {
+	return float32(max(int(size)/10, 1))
+}
+
+// loadFont switches text to the font file at path, "" goes back to
+// raylib's own
+func loadFont(path string) error {
+	if customFont {
+		rl.UnloadFont(font)
+		customFont = false
+	}
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	f := rl.LoadFontEx(path, fontLoadSize, nil)
+	// raylib hands back its default font when it can't read the file
+	if f.Texture.ID == 0 || f.Texture.ID == rl.GetFontDefault().Texture.ID {
+		return fmt.Errorf("%s isn't a font raylib can read", path)
+	}
+	rl.SetTextureFilter(f.Texture, rl.FilterBilinear)
+	font, customFont = f, true
+	return nil
+}
+
+// applyTheme styles raygui's controls after colors and the font
+func applyTheme() {
+	gui.LoadStyleDefault()
+	set := func(property int32, c rl.Color) {
+		gui.SetStyle(gui.DEFAULT, property, int64(c.R)<<24|int64(c.G)<<16|int64(c.B)<<8|int64(c.A))
+	}
+	set(gui.BACKGROUND_COLOR, colors.Panel)
+	set(gui.LINE_COLOR, colors.Border)
+	set(gui.BORDER_COLOR_NORMAL, colors.Border)
+	set(gui.BASE_COLOR_NORMAL, colors.Menu)
+	set(gui.TEXT_COLOR_NORMAL, colors.Text)
+	set(gui.BORDER_COLOR_FOCUSED, colors.Accent)
+	set(gui.BASE_COLOR_FOCUSED, colors.Highlight)
+	set(gui.TEXT_COLOR_FOCUSED, colors.Text)
+	set(gui.BORDER_COLOR_PRESSED, colors.Accent)
+	set(gui.BASE_COLOR_PRESSED, colors.Accent)
+	set(gui.TEXT_COLOR_PRESSED, colors.Background)
+	set(gui.BORDER_COLOR_DISABLED, colors.Menu)
+	set(gui.BASE_COLOR_DISABLED, colors.Panel)
+	set(gui.TEXT_COLOR_DISABLED, colors.Muted)
+	if customFont {
+		gui.SetFont(font)
+		gui.SetStyle(gui.DEFAULT, gui.TEXT_SIZE, textTiny)
+	}
+}
+
+// useSettings applies the theme and font of s, the zoom is picked up by
+// the next frame's updateScale
+func (app *App) useSettings(s theme.Settings) {
+	if t, err := theme.Named(s.Theme); err == nil {
+		colors = t
+	}
+	if s.Font != app.settings.Font || s.Font != "" && !customFont {
+		if err := loadFont(s.Font); err != nil {
+			log.Error("loading font", "path", s.Font, "err", err)
+			app.layoutMessage = err.Error()
+			s.Font = ""
+		}
+	}
+	app.settings = s
+	applyTheme()
+}
+
+// changeSettings applies s and saves it
+func (app *App) changeSettings(s theme.Settings) {
+	app.useSettings(s)
+	if err := theme.Save(settingsPath, app.settings); err != nil {
+		log.Error("saving settings", "path", settingsPath, "err", err)
+	}
+}
+
+func (app *App) setTheme(name string) {
+	s := app.settings
+	s.Theme = name
+	app.changeSettings(s)
+}
+
+func (app *App) setZoom(zoom float32) {
+	s := app.settings
+	s.Zoom = theme.ClampZoom(zoom)
+	app.changeSettings(s)
+}
+
+func (app *App) setFont(path string) {
+	s := app.settings
+	s.Font = path
+	app.changeSettings(s)
+}
+
+// updateScale follows the zoom and the monitor's DPI, which changes when
+// the window moves to another monitor. it says whether the scale changed
+func (app *App) updateScale() bool {
+	dpi := rl.GetWindowScaleDPI().X
+	// macOS scales the whole window itself
+	if dpi <= 0 || runtime.GOOS == "darwin" {
+		dpi = 1
+	}
+	s := dpi * app.settings.Zoom
+	if s == scale {
+		return false
+	}
+	scale = s
+	rl.SetMouseScale(1/scale, 1/scale)
+	return true
+}
+
+// screenWidth and screenHeight are the window's size in layout pixels
+func screenWidth() float32 {
+	return float32(rl.GetScreenWidth()) / scale
+}
+
+func screenHeight() float32 {
+	return float32(rl.GetScreenHeight()) / scale
+}
+
+// beginScissor is rl.BeginScissorMode for a rectangle in layout pixels
+func beginScissor(x, y, w, h float32) {
+	rl.BeginScissorMode(int32(x*scale), int32(y*scale), int32(w*scale), int32(h*scale))
+}
+
+// fitText cuts text down to fit width, with "..." if it had to
+func fitText(text string, fontSize int32, width float32) string {
+	if float32(measureText(text, fontSize)) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && float32(measureText(string(runes)+"...", fontSize)) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// wrapText breaks text into lines no wider than width
+func wrapText(text string, fontSize int32, width float32) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && float32(measureText(next, fontSize)) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// symbolAt is the symbol of the list row y is on, y from the top of the
+// panel. clicks and the keyboard both go through here
+func (app *App) symbolAt(y float32) (string, bool) {
+	if y < symbolListTop {
+		return "", false
+	}
+	i := int((y - symbolListTop + app.scrollOffset) / symbolRowHeight)
+	if i < 0 || i >= len(app.hub.Order) {
+		return "", false
+	}
+	return app.hub.Order[i], true
+}
+
+// clampSymbolScroll keeps the list from scrolling past its ends
+func (app *App) clampSymbolScroll(p *layout.Panel) {
+	content := float32(len(app.hub.Order)*symbolRowHeight + symbolListTop)
+	app.scrollOffset = min(app.scrollOffset, content-p.H)
+	if app.scrollOffset < 0 {
+		app.scrollOffset = 0
+	}
+}
+
+// scrollToSymbol scrolls the list just enough to show row i
+func (app *App) scrollToSymbol(p *layout.Panel, i int) {
+	top := float32(i * symbolRowHeight)
+	visible := p.H - symbolListTop
+	switch {
+	case top < app.scrollOffset:
+		app.scrollOffset = top
+	case top+symbolRowHeight > app.scrollOffset+visible:
+		app.scrollOffset = top + symbolRowHeight - visible
+	}
+	app.clampSymbolScroll(p)
+}
+
+// helper func (idk wtf it does)
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// panelKind is something a panel can show, the Panels menu adds any of
+// them as many times as you like
+type panelKind struct {
+	kind string
+	title string
+	width, height float32
+	render func(app *App, p *layout.Panel)
+}
+
+var panelKinds = []panelKind{
+	{"symbols", "Symbols - Finnhub", 300, 700, (*App).handlePanel1Logic},
+	{"trades", "Trades - Finnhub", 300, 300, func(app *App, p *layout.Panel) {
+		// the hub keys trades by the lowercase full finnhub symbol itself
+		app.handlePanel2Logic(p, app.hub.Trades(app.symbolFor(p)))
+	}},
+	{"quote", "Quotes - Finnhub", 300, 200, func(app *App, p *layout.Panel) {
+		if quote, ok := app.hub.Quote(app.symbolFor(p)); ok {
+			app.handlePanel3Logic(p, quote)
+		}
+	}},
+	{"ratings", "Recommendation Trends - Finnhub", 300, 200, func(app *App, p *layout.Panel) {
+		if ratings := app.hub.Ratings(app.symbolFor(p)); ratings != nil {
+			app.handlePanel4Logic(p, ratings)
+		}
+	}},
+	{"metrics", "Symbol Metrics - Finnhub", 300, 240, func(app *App, p *layout.Panel) {
+		if app.symbolFor(p) != "" {
+			app.handlePanel5Logic(p)
+		}
+	}},
+	{"chart", "Chart - Finnhub", 570, 300, func(app *App, p *layout.Panel) {
+		from, to := app.handleChartLogic(p)
+		// the palette's export commands use it too
+		app.chartRanges[p.ID] = [2]time.Time{from, to}
+		app.handleExportMenu(p, from, to)
+	}},
+	{"grid", "Chart Grid", 570, 400, (*App).handleGridLogic},
+	{"paper", "Paper Trading", 270, 390, (*App).handlePaperLogic},
+	{"portfolio", "Portfolio", 295, 210, (*App).handlePortfolioLogic},
+	{"news", "News - Finnhub", 295, 390, (*App).handleNewsLogic},
+	{"calendar", "Calendar - Finnhub", 295, 150, (*App).handleCalendarLogic},
+}
+
+func panelKindOf(kind string) (panelKind, bool) {
+	for _, k := range panelKinds {
+		if k.kind == kind {
+			return k, true
+		}
+	}
+	return panelKind{}, false
+}
+
+// where named layouts are saved, see the layout package
+var layoutDir = filepath.Join("data", "layouts")
+
+// panels live below the ticker and the clocks
+const headerHeight = 80
+
+func panelArea() layout.Rect {
+	return layout.Rect{X: 0, Y: headerHeight, W: screenWidth(), H: screenHeight() - headerHeight}
+}
+
+// defaultLayout is where the panels have always been, in the order they
+// used to be drawn
+func defaultLayout() *layout.Layout {
+	l := &layout.Layout{Name: "default", Width: 1200, Height: 800}
+	for _, at := range []struct {
+		kind string
+		x, y float32
+	}{
+		{"symbols", 10, 80}, {"trades", 900, 300}, {"quote", 900, 700}, {"ratings", 600, 700},
+		{"chart", 320, 90}, {"paper", 320, 400}, {"portfolio", 900, 85}, {"metrics", 600, 400},
+		{"news", 600, 400}, {"calendar", 600, 645},
+	} {
+		kind, _ := panelKindOf(at.kind)
+		l.Add(kind.kind, kind.title, layout.Rect{X: at.x, Y: at.y, W: kind.width, H: kind.height})
+	}
+	return l
+}
+
+// loadLayout is the layout the app was closed with, or the default one
+func loadLayout() *layout.Layout {
+	name := layout.Current(layoutDir)
+	if name == "" {
+		return defaultLayout()
+	}
+	l, err := layout.Load(layoutDir, name)
+	if err != nil {
+		log.Warn("loading layout, using the default", "name", name, "err", err)
+		return defaultLayout()
+	}
+	return l
+}
+
+// useLayout switches to l, resizing the window to what it was saved with
+func (app *App) useLayout(l *layout.Layout) {
+	app.panels.Layout = l
+	if l.Width > 0 && l.Height > 0 {
+		app.panels.Area = layout.Rect{X: 0, Y: headerHeight, W: float32(l.Width) / scale, H: float32(l.Height)/scale - headerHeight}
+		if l.Width != rl.GetScreenWidth() || l.Height != rl.GetScreenHeight() {
+			rl.SetWindowSize(l.Width, l.Height)
+		}
+	}
+	app.panels.Resize(panelArea())
+	if err := layout.SetCurrent(layoutDir, l.Name); err != nil {
+		log.Warn("remembering layout", "name", l.Name, "err", err)
+	}
+}
+
+// saveLayout saves the panels and window size as name
+func (app *App) saveLayout(name string) {
+	l := app.panels.Layout
+	l.Name = name
+	l.Width, l.Height = rl.GetScreenWidth(), rl.GetScreenHeight()
+	if err := layout.Save(layoutDir, l); err != nil {
+		log.Error("saving layout", "name", name, "err", err)
+		app.layoutMessage = err.Error()
+		return
+	}
+	app.layoutMessage = "Saved " + name
+}
+
+// hovered says whether p is the panel under the mouse, the only one that
+// gets clicks and the wheel
+func (app *App) hovered(p *layout.Panel) bool {
+	return app.panels.Hover == p && !app.panels.Busy()
+}
+
+// symbolFor is the full symbol p shows: its pin, its link group's symbol
+// or the main selection
+func (app *App) symbolFor(p *layout.Panel) string {
+	switch {
+	case p.Symbol != "":
+		return p.Symbol
+	case p.Link != "":
+		return app.panels.Layout.Links[p.Link]
+	}
+	return app.selectedPair().Symbol
+}
+
+func (app *App) pairFor(p *layout.Panel) event.Pair {
+	return event.Pair{Exchange: "finnhub", Symbol: app.symbolFor(p)}
+}
+
+// selectFor picks symbol for p's link group, or as the main selection
+// when p isn't linked or is nil
+func (app *App) selectFor(p *layout.Panel, symbol string) {
+	if p != nil && p.Link != "" {
+		if app.panels.Layout.Links == nil {
+			app.panels.Layout.Links = make(map[string]string)
+		}
+		app.panels.Layout.Links[p.Link] = symbol
+		return
+	}
+	if app.hub.Select(symbol, app.timeframe) {
+		// Select backfills it itself
+		app.backfilled[symbol+"|"+app.timeframe.Name] = true
+		app.newsScroll = 0
+	}
+}
+
+// pinnable panels show one symbol, the list and the grid pick them
+func pinnable(p *layout.Panel) bool {
+	return p.Kind != "symbols" && p.Kind != "grid"
+}
+
+// togglePin pins p to what it shows now, or lets it follow again
+func (app *App) togglePin(p *layout.Panel) {
+	if !pinnable(p) {
+		return
+	}
+	if p.Symbol != "" {
+		p.Symbol = ""
+		return
+	}
+	p.Symbol = app.symbolFor(p)
+}
+
+// linkColor is the theme's color for a link group
+func linkColor(link string) rl.Color {
+	if i := slices.Index(layout.Links, link); i >= 0 && i < len(colors.Links) {
+		return colors.Links[i]
+	}
+	return colors.Muted
+}
+
+// watchSymbols tells the hub what's on screen besides the selection, so
+// pinned panels, link groups and tiles get live trades and quotes
+func (app *App) watchSymbols() {
+	var symbols []string
+	for _, p := range app.panels.Layout.Panels {
+		switch {
+		case p.Minimized:
+		case p.Kind == "grid":
+			symbols = append(symbols, gridTiles(p)...)
+		case p.Kind != "symbols":
+			symbols = append(symbols, app.symbolFor(p))
+		}
+	}
+	for _, a := range app.alerts.All() {
+		symbols = append(symbols, a.Symbol)
+	}
+	app.hub.Watch(symbols)
+}
+
+// ensureBars backfills symbol at the chart's timeframe the first time a
+// chart or tile shows it
+func (app *App) ensureBars(symbol string) {
+	key := symbol + "|" + app.timeframe.Name
+	if symbol == "" || app.backfilled[key] {
+		return
+	}
+	app.backfilled[key] = true
+	go app.hub.BackfillSymbol(symbol, app.timeframe)
+}
+
+// renderPanelFrame draws a panel's background and title bar with its
+// minimize and close buttons, the content is up to its kind
+func (app *App) renderPanelFrame(p *layout.Panel) {
+	b := p.Bounds()
+	rect := rl.NewRectangle(b.X, b.Y, b.W, b.H)
+	rl.DrawRectangleRec(rect, colors.Panel)
+	// pinned and linked panels say what they're showing
+	title := p.Title
+	if p.Symbol != "" || p.Link != "" && p.Kind != "symbols" && p.Kind != "grid" {
+		if symbol := app.symbolFor(p); symbol != "" {
+			title += " - " + symbol
+		}
+	}
+	gui.Panel(rect, fitText(title, textTiny, p.W-90))
+
+	mouse := rl.GetMousePosition()
+	minimize := "-"
+	if p.Minimized {
+		minimize = "+"
+	}
+	type titleButton struct {
+		r layout.Rect
+		label string
+		on bool
+	}
+	buttons := []titleButton{{p.MinimizeButton(), minimize, false}, {p.CloseButton(), "x", false}}
+	if pinnable(p) {
+		buttons = append(buttons, titleButton{p.PinButton(), "p", p.Symbol != ""})
+	}
+	for _, button := range buttons {
+		buttonColor := colors.Muted
+		if button.on {
+			buttonColor = colors.Accent
+		} else if app.hovered(p) && button.r.Contains(mouse.X, mouse.Y) {
+			buttonColor = colors.Text
+		}
+		rl.DrawRectangleLines(int32(button.r.X), int32(button.r.Y), int32(button.r.W), int32(button.r.H), buttonColor)
+		drawText(button.label, int32(button.r.X+5), int32(button.r.Y+1), textBody, buttonColor)
+	}
+
+	// the link button is filled with the group's color
+	link := p.LinkButton()
+	if p.Link != "" {
+		rl.DrawRectangle(int32(link.X+3), int32(link.Y+3), int32(link.W-6), int32(link.H-6), linkColor(p.Link))
+	}
+	rl.DrawRectangleLines(int32(link.X), int32(link.Y), int32(link.W), int32(link.H), colors.Muted)
+	if p.Minimized {
+		return
+	}
+
+	// grip in the corner so it's obvious panels resize
+	right, bottom := int32(p.X+p.W)-3, int32(p.Y+p.H)-3
+	for i := int32(4); i <= 12; i += 4 {
+		rl.DrawLine(right-i, bottom, right, bottom-i, colors.Muted)
+	}
+}
+
+// setCursor shows which way the panel edge under the mouse resizes
+func (app *App) setCursor(mouse rl.Vector2) {
+	cursor := int32(rl.MouseCursorDefault)
+	switch edges := app.panels.Cursor(mouse.X, mouse.Y); {
+	case edges == layout.Bottom:
+		cursor = rl.MouseCursorResizeNS
+	case edges == layout.Bottom|layout.Right:
+		cursor = rl.MouseCursorResizeNWSE
+	case edges == layout.Bottom|layout.Left:
+		cursor = rl.MouseCursorResizeNESW
+	case edges != 0:
+		cursor = rl.MouseCursorResizeEW
+	}
+	if cursor != app.cursor {
+		rl.SetMouseCursor(cursor)
+		app.cursor = cursor
+	}
+}
+
+// the menu bar above the ticker
+var (
+	panelMenuButton = rl.NewRectangle(20, 2, 70, 16)
+	layoutMenuButton = rl.NewRectangle(95, 2, 70, 16)
+	viewMenuButton = rl.NewRectangle(170, 2, 70, 16)
+)
+
+const menuRowHeight = 22
+
+func panelMenuRect() rl.Rectangle {
+	return rl.NewRectangle(panelMenuButton.X, 20, 260, float32(len(panelKinds))*menuRowHeight+6)
+}
+
+// the layouts menu has a row per saved layout, then save, save as and
+// default
+func (app *App) layoutMenuRect() rl.Rectangle {
+	return rl.NewRectangle(layoutMenuButton.X, 20, 260, float32(len(app.layoutNames)+3)*menuRowHeight+6)
+}
+
+// the view menu has a row per theme, then the zoom and font rows
+func viewMenuRect() rl.Rectangle {
+	return rl.NewRectangle(viewMenuButton.X, 20, 200, float32(len(theme.All)+4)*menuRowHeight+6)
+}
 
-	// panel (panel1) is the one that contains the clickable symbols, leave it
-    panel := NewPanel(10, 80, 300, 700)
-    panel.title = "Quick Pick Symbols - Finnhub"
-    panel.onClick = app.handleSymbolClick
+// overMenu says whether the menus have the mouse, the panels under them
+// shouldn't react
+func (app *App) overMenu(mouse rl.Vector2) bool {
+	// nothing but the palette takes the mouse while it's open
+	if app.palette.open {
+		return true
+	}
+	for _, button := range []rl.Rectangle{panelMenuButton, layoutMenuButton, viewMenuButton} {
+		if rl.CheckCollisionPointRec(mouse, button) {
+			return true
+		}
+	}
+	return app.panelMenu && rl.CheckCollisionPointRec(mouse, panelMenuRect()) ||
+		app.layoutMenu && rl.CheckCollisionPointRec(mouse, app.layoutMenuRect()) ||
+		app.viewMenu && rl.CheckCollisionPointRec(mouse, viewMenuRect())
+}
+
+func (app *App) handleMenus() {
+	// a click anywhere else closes them
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !app.overMenu(rl.GetMousePosition()) {
+		app.panelMenu, app.layoutMenu, app.viewMenu = false, false, false
+		app.layoutNameEditing = false
+	}
+	if gui.Button(panelMenuButton, "Panels") {
+		app.panelMenu, app.layoutMenu, app.viewMenu = !app.panelMenu, false, false
+	}
+	if gui.Button(viewMenuButton, "View") {
+		app.viewMenu, app.panelMenu, app.layoutMenu = !app.viewMenu, false, false
+	}
+	if gui.Button(layoutMenuButton, "Layouts") {
+		app.layoutMenu, app.panelMenu, app.viewMenu = !app.layoutMenu, false, false
+		if app.layoutMenu {
+			names, err := layout.Names(layoutDir)
+			if err != nil {
+				log.Warn("listing layouts", "err", err)
+			}
+			app.layoutNames = names
+		}
+	}
+	if app.layoutMessage != "" {
+		drawText(app.layoutMessage, int32(viewMenuButton.X+viewMenuButton.Width+10), 4, textSmall, colors.Subtle)
+	}
+
+	if app.panelMenu {
+		app.handlePanelMenu()
+	}
+	if app.layoutMenu {
+		app.handleLayoutMenu()
+	}
+	if app.viewMenu {
+		app.handleViewMenu()
+	}
+}
+
+// handlePanelMenu adds a panel of whichever kind is picked, in the middle
+// and on top
+func (app *App) handlePanelMenu() {
+	menu := panelMenuRect()
+	rl.DrawRectangleRec(menu, colors.Menu)
+	open := make(map[string]int)
+	for _, p := range app.panels.Layout.Panels {
+		open[p.Kind]++
+	}
+	for i, kind := range panelKinds {
+		row := rl.NewRectangle(menu.X+3, menu.Y+3+float32(i)*menuRowHeight, menu.Width-6, menuRowHeight-2)
+		label := kind.title
+		if n := open[kind.kind]; n > 0 {
+			label = fmt.Sprintf("%s (%d open)", label, n)
+		}
+		if gui.Button(row, label) {
+			app.addPanel(kind)
+			app.panelMenu = false
+		}
+	}
+}
+
+// addPanel puts a new panel of kind in the middle, on top
+func (app *App) addPanel(kind panelKind) {
+	area := app.panels.Area
+	w, h := min(kind.width, area.W), min(kind.height, area.H)
+	app.panels.Layout.Add(kind.kind, kind.title, layout.Rect{X: area.X + (area.W-w)/2, Y: area.Y + (area.H-h)/2, W: w, H: h})
+}
+
+// handleLayoutMenu switches to, saves and names layouts
+func (app *App) handleLayoutMenu() {
+	menu := app.layoutMenuRect()
+	rl.DrawRectangleRec(menu, colors.Menu)
+	row := func(i int) rl.Rectangle {
+		return rl.NewRectangle(menu.X+3, menu.Y+3+float32(i)*menuRowHeight, menu.Width-6, menuRowHeight-2)
+	}
+
+	for i, name := range app.layoutNames {
+		label := name
+		if name == app.panels.Layout.Name {
+			label += " (current)"
+		}
+		if gui.Button(row(i), label) {
+			l, err := layout.Load(layoutDir, name)
+			if err != nil {
+				log.Error("loading layout", "name", name, "err", err)
+				app.layoutMessage = err.Error()
+				continue
+			}
+			app.useLayout(l)
+			app.layoutMessage = "Switched to " + name
+			app.layoutMenu = false
+		}
+	}
+
+	n := len(app.layoutNames)
+	if gui.Button(row(n), "Save "+app.panels.Layout.Name) {
+		app.saveLayout(app.panels.Layout.Name)
+		app.layoutMenu = false
+	}
+
+	saveAs := row(n + 1)
+	if gui.TextBox(rl.NewRectangle(saveAs.X, saveAs.Y, saveAs.Width-70, saveAs.Height), &app.layoutName, 32, app.layoutNameEditing) {
+		app.layoutNameEditing = !app.layoutNameEditing
+	}
+	if gui.Button(rl.NewRectangle(saveAs.X+saveAs.Width-65, saveAs.Y, 65, saveAs.Height), "Save as") {
+		name := strings.TrimSpace(app.layoutName)
+		if err := layout.ValidName(name); err != nil {
+			app.layoutMessage = err.Error()
+		} else {
+			app.saveLayout(name)
+			app.layoutName, app.layoutNameEditing = "", false
+			app.layoutMenu = false
+		}
+	}
+
+	if gui.Button(row(n+2), "Default panels") {
+		// the current layout goes back to the default panels
+		l := defaultLayout()
+		l.Name = app.panels.Layout.Name
+		app.useLayout(l)
+		app.layoutMenu = false
+	}
+}
+
+// where the keyboard bindings are read from, written with the defaults
+// the first time
+var keysPath = filepath.Join("data", "keybindings.json")
+
+// defaultBindings are the keys each action has unless keybindings.json
+// says otherwise
+func defaultBindings() keys.Bindings {
+	chords := map[string][]string{
+		"palette": {"ctrl+k"},
+		"quit": {"escape"},
+		"layout.save": {"ctrl+s"},
+		"symbols.up": {"up"},
+		"symbols.down": {"down"},
+		"symbols.pageup": {"pageup"},
+		"symbols.pagedown": {"pagedown"},
+		"symbols.first": {"home"},
+		"symbols.last": {"end"},
+		"view.zoomin": {"ctrl+="},
+		"view.zoomout": {"ctrl+-"},
+		"view.zoomreset": {"ctrl+0"},
+	}
+	for i, tf := range candle.Timeframes {
+		if i < 9 {
+			chords["timeframe."+tf.Name] = []string{fmt.Sprintf("ctrl+%d", i+1)}
+		}
+	}
+	for i, kind := range panelKinds {
+		if i < 12 {
+			chords["panel."+kind.kind] = []string{fmt.Sprintf("f%d", i+1)}
+		}
+	}
+	return keys.Must(chords)
+}
+
+// keyCodes are raylib's codes for the key names bindings can use
+var keyCodes = func() map[string]int32 {
+	codes := map[string]int32{
+		"up": rl.KeyUp, "down": rl.KeyDown, "left": rl.KeyLeft, "right": rl.KeyRight,
+		"pageup": rl.KeyPageUp, "pagedown": rl.KeyPageDown, "home": rl.KeyHome, "end": rl.KeyEnd,
+		"enter": rl.KeyEnter, "escape": rl.KeyEscape, "tab": rl.KeyTab, "space": rl.KeySpace,
+		"backspace": rl.KeyBackspace, "delete": rl.KeyDelete, "insert": rl.KeyInsert,
+		"=": rl.KeyEqual, "-": rl.KeyMinus,
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		codes[string(c)] = rl.KeyA + int32(c-'a')
+	}
+	for c := '0'; c <= '9'; c++ {
+		codes[string(c)] = rl.KeyZero + int32(c-'0')
+	}
+	for i := 1; i <= 12; i++ {
+		codes[fmt.Sprintf("f%d", i)] = rl.KeyF1 + int32(i-1)
+	}
+	return codes
+}()
+
+// loadKeys is the default bindings with whatever keybindings.json changes,
+// anything in it that can't work is logged and left out
+func loadKeys() keys.Bindings {
+	defaults := defaultBindings()
+	bindings, err := keys.Load(keysPath, defaults)
+	if err != nil {
+		log.Warn("loading keybindings", "path", keysPath, "err", err)
+	}
+	for action, chords := range bindings {
+		if _, ok := defaults[action]; !ok {
+			log.Warn("keybindings: unknown action", "action", action)
+		}
+		for _, c := range chords {
+			if _, ok := keyCodes[c.Key]; !ok {
+				log.Warn("keybindings: unknown key", "action", action, "key", c.Key)
+			}
+		}
+	}
+	return bindings
+}
+
+// modifiers are the modifier keys held down, cmd counts as ctrl
+func modifiers() (ctrl, shift, alt bool) {
+	ctrl = rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl) ||
+		rl.IsKeyDown(rl.KeyLeftSuper) || rl.IsKeyDown(rl.KeyRightSuper)
+	shift = rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+	alt = rl.IsKeyDown(rl.KeyLeftAlt) || rl.IsKeyDown(rl.KeyRightAlt)
+	return ctrl, shift, alt
+}
+
+// pressed says whether one of action's chords went down this frame, with
+// exactly its modifiers held. moving through the symbols repeats while
+// the key is held
+func (app *App) pressed(action string) bool {
+	ctrl, shift, alt := modifiers()
+	repeat := strings.HasPrefix(action, "symbols.")
+	for _, c := range app.keys[action] {
+		code, ok := keyCodes[c.Key]
+		if !ok || c.Ctrl != ctrl || c.Shift != shift || c.Alt != alt {
+			continue
+		}
+		if rl.IsKeyPressed(code) || repeat && rl.IsKeyPressedRepeat(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// typing is true while a text box has the keyboard
+func (app *App) typing() bool {
+	return app.orderForm.editing != formNone || app.layoutNameEditing
+}
+
+// handleKeys runs the hotkeys. text boxes keep the keyboard while they're
+// being typed in, and the palette takes it all while it's open
+func (app *App) handleKeys() {
+	if app.palette.open {
+		if app.pressed("palette") {
+			app.palette.open = false
+		}
+		return
+	}
+	if app.typing() {
+		return
+	}
+
+	switch {
+	case app.pressed("palette"):
+		app.openPalette()
+		return
+	case app.pressed("quit"):
+		// escape closes whatever's open before it quits
+		if app.panelMenu || app.layoutMenu || app.viewMenu || app.exportMenu != "" {
+			app.panelMenu, app.layoutMenu, app.viewMenu, app.exportMenu = false, false, false, ""
+		} else {
+			app.quit = true
+		}
+	case app.pressed("layout.save"):
+		app.saveLayout(app.panels.Layout.Name)
+	case app.pressed("view.zoomin"):
+		app.setZoom(app.settings.Zoom + theme.ZoomStep)
+	case app.pressed("view.zoomout"):
+		app.setZoom(app.settings.Zoom - theme.ZoomStep)
+	case app.pressed("view.zoomreset"):
+		app.setZoom(1)
+	}
+	for _, tf := range candle.Timeframes {
+		if app.pressed("timeframe." + tf.Name) {
+			app.timeframe = tf
+		}
+	}
+	for _, kind := range panelKinds {
+		if app.pressed("panel." + kind.kind) {
+			app.togglePanel(kind)
+		}
+	}
+	app.handleSymbolKeys()
+}
+
+// symbolList is the list the keyboard moves through: the one under the
+// mouse, else the topmost one. nil when none is open, the keys still
+// move the main selection then
+func (app *App) symbolList() *layout.Panel {
+	if p := app.panels.Hover; p != nil && p.Kind == "symbols" {
+		return p
+	}
+	var list *layout.Panel
+	for _, p := range app.panels.Layout.Panels {
+		if p.Kind == "symbols" {
+			list = p
+		}
+	}
+	return list
+}
+
+// handleSymbolKeys moves through the symbol list with the arrows and page
+// keys, and jumps to whatever ticker is typed
+func (app *App) handleSymbolKeys() {
+	list := app.symbolList()
+	if len(app.hub.Order) == 0 {
+		return
+	}
+	page := 10
+	if list != nil && !list.Minimized {
+		page = max(1, int((list.H-symbolListTop)/symbolRowHeight)-1)
+	}
+	last := len(app.hub.Order) - 1
+
+	current := -1
+	if list != nil {
+		current = slices.Index(app.hub.Order, app.symbolFor(list))
+	} else {
+		current = slices.Index(app.hub.Order, app.hub.Selected())
+	}
+	next := current
+	switch {
+	case app.pressed("symbols.up"):
+		next = current - 1
+	case app.pressed("symbols.down"):
+		next = current + 1
+	case app.pressed("symbols.pageup"):
+		next = current - page
+	case app.pressed("symbols.pagedown"):
+		next = current + page
+	case app.pressed("symbols.first"):
+		next = 0
+	case app.pressed("symbols.last"):
+		next = last
+	default:
+		next = app.handleTypeAhead(current)
+	}
+	if next == current {
+		return
+	}
+	next = min(max(next, 0), last)
+	app.selectFor(list, app.hub.Order[next])
+	if list != nil {
+		app.scrollToSymbol(list, next)
+	}
+}
+
+// type-ahead starts over after a pause this long
+const typeAheadTimeout = time.Second
+
+// handleTypeAhead is the first symbol matching the letters typed, current
+// when nothing new was typed or nothing matches
+func (app *App) handleTypeAhead(current int) int {
+	next := current
+	for ch := rl.GetCharPressed(); ch != 0; ch = rl.GetCharPressed() {
+		if ctrl, _, alt := modifiers(); ctrl || alt || !unicode.IsPrint(ch) || unicode.IsSpace(ch) {
+			continue
+		}
+		if time.Since(app.typeAheadAt) > typeAheadTimeout {
+			app.typeAhead = ""
+		}
+		app.typeAhead += string(unicode.ToUpper(ch))
+		app.typeAheadAt = time.Now()
+		if i := app.matchSymbol(app.typeAhead); i >= 0 {
+			next = i
+		}
+	}
+	return next
+}
+
+// matchSymbol is the index of the first symbol whose full name, name
+// without the exchange or display name starts with prefix, -1 for none
+func (app *App) matchSymbol(prefix string) int {
+	display := make(map[string][]string, len(app.hub.Symbols))
+	for name, full := range app.hub.Symbols {
+		display[full] = append(display[full], strings.ToUpper(name))
+	}
+	for i, full := range app.hub.Order {
+		names := append([]string{full}, display[full]...)
+		if _, short, ok := strings.Cut(full, ":"); ok {
+			names = append(names, short)
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// resolveSymbol turns a typed symbol, "eth", "ETHUSDT" or "AAPL", into the
+// full finnhub one. unknown symbols are taken as typed
+func (app *App) resolveSymbol(typed string) string {
+	typed = strings.ToUpper(typed)
+	for name, full := range app.hub.Symbols {
+		if strings.ToUpper(name) == typed {
+			return full
+		}
+	}
+	for _, full := range app.hub.Order {
+		if _, short, _ := strings.Cut(full, ":"); full == typed || short == typed {
+			return full
+		}
+	}
+	return typed
+}
+
+// togglePanel is a panel hotkey: it brings the topmost panel of kind to
+// the front, minimizes it when it's already there and adds one when
+// there's none
+func (app *App) togglePanel(kind panelKind) {
+	l := app.panels.Layout
+	var p *layout.Panel
+	for _, q := range l.Panels {
+		if q.Kind == kind.kind {
+			p = q
+		}
+	}
+	switch {
+	case p == nil:
+		app.addPanel(kind)
+	case p.Minimized:
+		p.Minimized = false
+		l.Raise(p)
+	case l.Panels[len(l.Panels)-1] == p:
+		p.Minimized = true
+	default:
+		l.Raise(p)
+	}
+}
+
+// topmost is the topmost open panel of kind, nil for none
+func (app *App) topmost(kind string) *layout.Panel {
+	panels := app.panels.Layout.Panels
+	for i := len(panels) - 1; i >= 0; i-- {
+		if panels[i].Kind == kind && !panels[i].Minimized {
+			return panels[i]
+		}
+	}
+	return nil
+}
+
+// palette is the command palette, everything the GUI does by name
+type palette struct {
+	open bool
+	query string
+	cursor int // highlighted row of the filtered commands
+	layouts []string // saved layouts, read when it opens
+}
+
+type command struct {
+	name string
+	// action is the binding that does the same, to show its keys
+	action string
+	run func()
+}
+
+// the palette shows this many matches at a time
+const paletteRows = 12
+
+func (app *App) openPalette() {
+	names, err := layout.Names(layoutDir)
+	if err != nil {
+		log.Warn("listing layouts", "err", err)
+	}
+	app.palette = palette{open: true, layouts: names}
+	app.panelMenu, app.layoutMenu, app.viewMenu, app.exportMenu = false, false, false, ""
+}
+
+// commands are the palette's commands matching what's typed. typing
+// "alert AAPL above 190" or just "AAPL above 190" offers to add it
+func (app *App) commands() []command {
+	query := strings.TrimSpace(app.palette.query)
+	var parsed, all []command
+	if a, err := alert.Parse(strings.TrimPrefix(query, "alert "), app.resolveSymbol); err == nil {
+		// it's what was typed, it doesn't have to match anything
+		parsed = append(parsed, command{name: "Add alert: " + a.String(), run: func() {
+			if err := app.alerts.Add(a); err != nil {
+				log.Error("adding alert", "alert", a, "err", err)
+				return
+			}
+			log.Info("alert added", "alert", a)
+		}})
+	} else {
+		all = append(all, command{name: "Add alert...", run: func() {
+			// stays open for the rest of it
+			app.palette.query, app.palette.open = "alert ", true
+		}})
+	}
+	for _, a := range app.alerts.All() {
+		all = append(all, command{name: "Remove alert: " + a.String(), run: func() {
+			if err := app.alerts.Remove(a); err != nil {
+				log.Error("removing alert", "alert", a, "err", err)
+			}
+		}})
+	}
+
+	for _, full := range app.hub.Order {
+		all = append(all, command{name: "Go to " + full, run: func() {
+			app.selectFor(app.symbolList(), full)
+		}})
+	}
+	for _, tf := range candle.Timeframes {
+		all = append(all, command{name: "Timeframe " + tf.Name, action: "timeframe." + tf.Name, run: func() {
+			app.timeframe = tf
+		}})
+	}
+	for _, kind := range panelKinds {
+		all = append(all,
+			command{name: "Show/hide panel: " + kind.title, action: "panel." + kind.kind, run: func() { app.togglePanel(kind) }},
+			command{name: "Add panel: " + kind.title, run: func() { app.addPanel(kind) }},
+		)
+	}
+
+	for _, name := range app.palette.layouts {
+		all = append(all, command{name: "Switch layout: " + name, run: func() {
+			l, err := layout.Load(layoutDir, name)
+			if err != nil {
+				log.Error("loading layout", "name", name, "err", err)
+				app.layoutMessage = err.Error()
+				return
+			}
+			app.useLayout(l)
+			app.layoutMessage = "Switched to " + name
+		}})
+	}
+	all = append(all,
+		command{name: "Save layout " + app.panels.Layout.Name, action: "layout.save", run: func() {
+			app.saveLayout(app.panels.Layout.Name)
+		}},
+		command{name: "Default panels", run: func() {
+			l := defaultLayout()
+			l.Name = app.panels.Layout.Name
+			app.useLayout(l)
+		}},
+	)
+
+	// exports go by the topmost chart, like its Export button
+	if chart := app.topmost("chart"); chart != nil {
+		r := app.chartRanges[chart.ID]
+		for _, kind := range export.Kinds {
+			for _, format := range export.Formats {
+				all = append(all, command{name: fmt.Sprintf("Export %s as %s", kind, format), run: func() {
+					app.startExport(app.pairFor(chart), r[0], r[1], kind, format)
+				}})
+			}
+		}
+	}
+	for _, t := range theme.All {
+		all = append(all, command{name: "Theme: " + t.Name, run: func() { app.setTheme(t.Name) }})
+	}
+	all = append(all,
+		command{name: "Zoom in", action: "view.zoomin", run: func() { app.setZoom(app.settings.Zoom + theme.ZoomStep) }},
+		command{name: "Zoom out", action: "view.zoomout", run: func() { app.setZoom(app.settings.Zoom - theme.ZoomStep) }},
+		command{name: "Reset zoom", action: "view.zoomreset", run: func() { app.setZoom(1) }},
+	)
+	if app.settings.Font != "" {
+		all = append(all, command{name: "Default font", run: func() { app.setFont("") }})
+	}
+	all = append(all, command{name: "Quit", action: "quit", run: func() { app.quit = true }})
+
+	// every word typed has to be in the name, in any order
+	words := strings.Fields(strings.ToLower(query))
+	matches := parsed
+	for _, c := range all {
+		name := strings.ToLower(c.name)
+		if !slices.ContainsFunc(words, func(w string) bool { return !strings.Contains(name, w) }) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func paletteRect() rl.Rectangle {
+	w := min(500, screenWidth()-40)
+	return rl.NewRectangle((screenWidth()-w)/2, 60, w, float32(paletteRows+1)*menuRowHeight+10)
+}
+
+// handlePalette draws the palette over everything and runs what's picked
+// with enter or a click. escape or a click outside closes it
+func (app *App) handlePalette() {
+	if !app.palette.open {
+		return
+	}
+	pal := &app.palette
+
+	for ch := rl.GetCharPressed(); ch != 0; ch = rl.GetCharPressed() {
+		if ctrl, _, alt := modifiers(); !ctrl && !alt && unicode.IsPrint(ch) {
+			pal.query += string(ch)
+			pal.cursor = 0
+		}
+	}
+	if (rl.IsKeyPressed(rl.KeyBackspace) || rl.IsKeyPressedRepeat(rl.KeyBackspace)) && pal.query != "" {
+		_, size := utf8.DecodeLastRuneInString(pal.query)
+		pal.query = pal.query[:len(pal.query)-size]
+		pal.cursor = 0
+	}
+	matches := app.commands()
+	switch {
+	case rl.IsKeyPressed(rl.KeyUp) || rl.IsKeyPressedRepeat(rl.KeyUp):
+		pal.cursor--
+	case rl.IsKeyPressed(rl.KeyDown) || rl.IsKeyPressedRepeat(rl.KeyDown):
+		pal.cursor++
+	}
+	pal.cursor = min(max(pal.cursor, 0), max(len(matches)-1, 0))
+
+	run := -1
+	if rl.IsKeyPressed(rl.KeyEnter) && len(matches) > 0 {
+		run = pal.cursor
+	}
+	if rl.IsKeyPressed(rl.KeyEscape) {
+		pal.open = false
+		return
+	}
+
+	box := paletteRect()
+	mouse := rl.GetMousePosition()
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) && !rl.CheckCollisionPointRec(mouse, box) {
+		pal.open = false
+		return
+	}
+	rl.DrawRectangleRec(box, colors.Menu)
+	rl.DrawRectangleLinesEx(box, 1, colors.Border)
+	drawText(fitText("> "+pal.query+"_", textHeading, box.Width-20), int32(box.X+10), int32(box.Y+6), textHeading, colors.Text)
+
+	// the highlighted row stays in view
+	first := max(0, pal.cursor-paletteRows+1)
+	for i := first; i < len(matches) && i < first+paletteRows; i++ {
+		row := rl.NewRectangle(box.X+5, box.Y+5+float32(i-first+1)*menuRowHeight, box.Width-10, menuRowHeight-2)
+		hover := rl.CheckCollisionPointRec(mouse, row)
+		if i == pal.cursor || hover {
+			rl.DrawRectangleRec(row, colors.Highlight)
+		}
+		if hover && rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+			run = i
+		}
+		hint := app.keys.Help(matches[i].action)
+		drawText(fitText(matches[i].name, textBody, row.Width-90), int32(row.X+5), int32(row.Y+3), textBody, colors.Text)
+		if hint != "" {
+			drawText(hint, int32(row.X+row.Width)-measureText(hint, textSmall)-5, int32(row.Y+4), textSmall, colors.Subtle)
+		}
+	}
+	if len(matches) == 0 {
+		drawText("No matches", int32(box.X+10), int32(box.Y+5+menuRowHeight+3), textBody, colors.Subtle)
+	}
+
+	if run >= 0 {
+		// closes first so a command can open it again
+		pal.open = false
+		matches[run].run()
+	}
+}
+
+// where alerts are kept
+var alertsPath = filepath.Join("data", "alerts.json")
+
+// checkAlerts sets off the alerts trade crosses, from the trade loop
+func (app *App) checkAlerts(trade event.StockTrade) {
+	hit, err := app.alerts.Check(trade.Pair.Symbol, trade.Price)
+	if err != nil {
+		log.Error("saving alerts", "err", err)
+	}
+	for _, a := range hit {
+		log.Info("alert", "alert", a, "price", trade.Price)
+		message := fmt.Sprintf("Alert: %s, traded at %.2f", a, trade.Price)
+		app.alertMessage.Store(&message)
+	}
+}
 
-    for !rl.WindowShouldClose() {
-        panel.update()
-        panel.render()
+// handleViewMenu picks the theme, zooms and goes back to the default
+// font. a font file dropped on the window switches to it
+func (app *App) handleViewMenu() {
+	menu := viewMenuRect()
+	rl.DrawRectangleRec(menu, colors.Menu)
+	row := func(i int) rl.Rectangle {
+		return rl.NewRectangle(menu.X+3, menu.Y+3+float32(i)*menuRowHeight, menu.Width-6, menuRowHeight-2)
+	}
+
+	for i, t := range theme.All {
+		label := "Theme: " + t.Name
+		if t.Name == colors.Name {
+			label += " (current)"
+		}
+		if gui.Button(row(i), label) {
+			app.setTheme(t.Name)
+		}
+	}
+	n := len(theme.All)
+	if gui.Button(row(n), "Zoom in") {
+		app.setZoom(app.settings.Zoom + theme.ZoomStep)
+	}
+	if gui.Button(row(n+1), "Zoom out") {
+		app.setZoom(app.settings.Zoom - theme.ZoomStep)
+	}
+	if gui.Button(row(n+2), fmt.Sprintf("Reset zoom (%.0f%%)", app.settings.Zoom*100)) {
+		app.setZoom(1)
+	}
+	fontLabel := "Drop a TTF to change font"
+	if app.settings.Font != "" {
+		fontLabel = "Default font"
+	}
+	if gui.Button(row(n+3), fontLabel) && app.settings.Font != "" {
+		app.setFont("")
+	}
+}
+
+// the websocket is stale when finnhub hasn't sent anything, not even a
+// ping, for this long
+const feedStaleAfter = time.Minute
+
+func main() {
+    // a missing .env is fine as long as the environment has what it needs
+    envErr := godotenv.Load(".env")
+
+    // `stockspider quote AAPL` and friends print and exit, no window
+    if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+        if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
+            fmt.Fprintln(os.Stderr, "stockspider:", err)
+            os.Exit(1)
+        }
+        return
+    }
+
+    logCfg, err := logging.ConfigFromEnv()
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "bad log config: %v\n", err)
+        os.Exit(1)
+    }
+    logs, err := logging.New(logCfg)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "could not start logging: %v\n", err)
+        os.Exit(1)
+    }
+    defer logs.Close()
+    log = logs.Component("app")
+    // hollywood logs crashes and restarts through the default logger
+    slog.SetDefault(logs.Component("actor"))
+    if envErr != nil {
+        log.Warn("no .env file, using the environment", "err", envErr)
+    }
+
+    // NODE_LISTEN and INGEST_ADDR make this a viewer of a separate ingest node
+    nodeCfg := node.ConfigFromEnv()
+    e, err := node.NewEngine(nodeCfg)
+    if err != nil {
+        fatal("starting actor engine", "err", err)
+    }
+    // the supervisor first so it sees every crash, then the broker since
+    // it has to be up before anything publishes or subscribes
+    supervise.Start(e, logs.Component("supervisor"))
+    bus.Start(e)
+
+    client, err := NewFinnhubClient(os.Getenv("API_KEY"))
+    if err != nil {
+        fatal("creating finnhub client", "err", err)
+    }
+    h := hub.New(e, client.Client, logs.Component("hub"))
+    app := NewApp(e, h)
+
+    ticks, err := store.NewTickStore("data/ticks")
+    if err != nil {
+        fatal("opening tick store", "err", err)
+    }
+    defer ticks.Close()
+    h.Ticks = ticks
+    quotes, err := store.NewQuoteStore("data/quotes")
+    if err != nil {
+        fatal("opening quote store", "err", err)
+    }
+    defer quotes.Close()
+    h.QuoteHistory = quotes
+    corp, err := corporate.NewStore("data/corporate")
+    if err != nil {
+        fatal("opening corporate events store", "err", err)
+    }
+    app.corporate = corp
+
+    // finnhub candles come split-adjusted, our own ticks don't
+    barSources := []backfill.Source{
+        backfill.FinnhubSource{Client: client.Client},
+        backfill.StoreSource{Ticks: ticks, Adjust: corp.AdjustCandles},
+    }
+    h.Backfill = backfill.New(barSources...)
+    app.export = export.Sources{Ticks: ticks, Quotes: quotes, Bars: barSources}
+
+    paperEngine, err := paper.New(paper.DefaultConfig())
+    if err != nil {
+        fatal("loading paper trading account", "err", err)
+    }
+    app.paper = paperEngine
+
+    portfolios, err := portfolio.LoadAll(portfolioDir)
+    if err != nil {
+        log.Warn("loading portfolios", "err", err)
+    }
+    app.portfolios = portfolios
+    app.updateHoldings()
+
+    alerts, err := alert.Open(alertsPath)
+    if err != nil {
+        fatal("loading alerts", "err", err)
+    }
+    app.alerts = alerts
+    app.keys = loadKeys()
+    settings, err := theme.Load(settingsPath)
+    if err != nil {
+        log.Warn("loading settings, using the defaults", "path", settingsPath, "err", err)
+    }
+
+    // PAPER_STRATEGY runs one of the backtest strategies live on the paper account
+    if name := os.Getenv("PAPER_STRATEGY"); name != "" {
+        s, err := strategy.New(name)
+        if err != nil {
+            fatal("starting paper strategy", "err", err)
+        }
+        tf := candle.M1
+        if tfName := os.Getenv("PAPER_STRATEGY_TF"); tfName != "" {
+            if tf, err = candle.ParseTimeframe(tfName); err != nil {
+                fatal("bad PAPER_STRATEGY_TF", "err", err)
+            }
+        }
+        app.strategy = strategy.NewLive(s, paperEngine, tf)
+        log.Info("running strategy live on the paper account", "strategy", s.Name(), "timeframe", tf.Name)
+    }
+    go app.start()
+
+    // /metrics for prometheus and /healthz, METRICS_ADDR=off turns it off
+    metricsAddr := os.Getenv("METRICS_ADDR")
+    if metricsAddr == "" {
+        metricsAddr = ":2112"
+    }
+    if metricsAddr != "off" {
+        // the quote subcommand asks here before finnhub
+        metrics.Handle("/quotes", http.HandlerFunc(h.ServeQuotes))
+        go func() {
+            if err := metrics.Serve(metricsAddr, feedStaleAfter); err != nil {
+                log.Error("metrics server stopped", "err", err)
+            }
+        }()
+    }
+
+    // both find everything they need on the bus, a viewer gets what they
+    // publish from the ingest node instead
+    if nodeCfg.Viewer() {
+        node.StartImporter(e, nodeCfg.Ingest, node.Patterns, logs.Component("node"))
+    } else {
+        e.Spawn(finnhub.New(logs.Component("finnhub")), "finnhub", finnhub.Policy.Opts()...)
+        e.Spawn(news.New(client.Client, logs.Component("news")), "news", news.Policy.Opts()...)
+    }
+
+    // the window opens at the size the layout was saved with
+    panels := loadLayout()
+    width, height := panels.Width, panels.Height
+    if width <= 0 || height <= 0 {
+        width, height = 1200, 800
+    }
+    rl.SetConfigFlags(rl.FlagWindowResizable)
+    rl.InitWindow(int32(width), int32(height), "Stock Spider")
+    defer rl.CloseWindow()
+    rl.SetWindowMinSize(640, 480)
+    rl.SetTargetFPS(60)
+    // the theme and font need the window, and the scale them
+    app.useSettings(settings)
+    app.updateScale()
+    // escape is a binding like any other, see the quit action
+    rl.SetExitKey(rl.KeyNull)
+    app.panels = layout.NewManager(panels, panelArea())
+    // layouts saved on a bigger screen still have to fit
+    app.panels.Resize(panelArea())
+
+    for !rl.WindowShouldClose() && !app.quit {
         app.render()
     }
-	// close trade chan for cleanup, hope it does at least
-    close(tradeCh)
+    // closing saves where everything was
+    app.saveLayout(app.panels.Layout.Name)
+	// stop the trade loop for cleanup, hope it does at least
+    h.Close()
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Transport counts finnhub REST calls per endpoint, errors and 429s.
+// next nil means http.DefaultTransport
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripper{next: next}
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := Endpoint(req.URL.Path)
+	restRequests.WithLabelValues(endpoint).Inc()
+
+	res, err := rt.next.RoundTrip(req)
+	if err != nil {
+		restErrors.WithLabelValues(endpoint).Inc()
+		return res, err
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		restRateLimited.WithLabelValues(endpoint).Inc()
+	}
+	if res.StatusCode >= 400 {
+		restErrors.WithLabelValues(endpoint).Inc()
+	}
+	return res, nil
+}
+
+// Endpoint names a finnhub api path for labels, "/api/v1/stock/metric"
+// becomes "stock/metric"
+func Endpoint(p string) string {
+	p = strings.TrimPrefix(path.Clean(p), "/")
+	p = strings.TrimPrefix(p, "api/v1/")
+	if p == "" || p == "." {
+		return "unknown"
+	}
+	return p
+}
+
+// Health is what /healthz reports
+type Health struct {
+	Status         string  `json:"status"` // "ok" or "stale"
+	Connected      bool    `json:"connected"`
+	LastMessageAge float64 `json:"lastMessageAgeSeconds"` // -1 before the first message
+	StaleAfter     float64 `json:"staleAfterSeconds"`
+}
+
+// Check reports the feed stale when the websocket is down or has been quiet
+// for longer than staleAfter. finnhub pings idle connections so a healthy
+// feed is never quiet for long, even with every market closed
+func Check(now time.Time, staleAfter time.Duration) Health {
+	h := Health{
+		Status:         "ok",
+		Connected:      Connected(),
+		LastMessageAge: -1,
+		StaleAfter:     staleAfter.Seconds(),
+	}
+	if last := LastMessage(); !last.IsZero() {
+		h.LastMessageAge = now.Sub(last).Seconds()
+	}
+	if !h.Connected || h.LastMessageAge < 0 || h.LastMessageAge > h.StaleAfter {
+		h.Status = "stale"
+	}
+	return h
+}
+
+// what other packages serve next to /metrics, the hub's /quotes say
+var (
+	handlersMu sync.Mutex
+	handlers   = make(map[string]http.Handler)
+)
+
+// Handle adds handler at pattern to the server, before Serve
+func Handle(pattern string, handler http.Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+
+	handlers[pattern] = handler
+}
+
+// Handler serves /metrics, /healthz and whatever was added with Handle
+func Handler(staleAfter time.Duration) http.Handler {
+	mux := http.NewServeMux()
+	handlersMu.Lock()
+	for pattern, handler := range handlers {
+		mux.Handle(pattern, handler)
+	}
+	handlersMu.Unlock()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		h := Check(time.Now(), staleAfter)
+		w.Header().Set("Content-Type", "application/json")
+		if h.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	})
+	return mux
+}
+
+// Serve listens on addr until it fails
+func Serve(addr string, staleAfter time.Duration) error {
+	go rateLoop()
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           Handler(staleAfter),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return server.ListenAndServe()
+}
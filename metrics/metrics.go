@@ -0,0 +1,303 @@
+// Package metrics exposes how the data pipeline is doing: the websocket
+// feed, REST calls against finnhub, actor mailboxes and the trade queues.
+// Serve puts it on /metrics for prometheus and /healthz for a quick check.
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "stockspider"
+
+var (
+	wsConnected = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ws_connected",
+		Help:      "1 while the finnhub websocket is connected.",
+	})
+	wsReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ws_reconnects_total",
+		Help:      "Times the finnhub websocket had to be redialed.",
+	})
+	wsMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ws_messages_total",
+		Help:      "Websocket messages received, by message type.",
+	}, []string{"type"})
+	wsMessageRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "ws_messages_per_second",
+		Help:      "Websocket messages received over the last second, by message type.",
+	}, []string{"type"})
+
+	tradeRenderLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "trade_to_render_seconds",
+		Help:      "Time from a trade's exchange timestamp to the frame that draws it, network and clock skew included.",
+		Buckets:   []float64{.001, .0025, .005, .01, .017, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	})
+
+	restRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rest_requests_total",
+		Help:      "Finnhub REST calls, by endpoint.",
+	}, []string{"endpoint"})
+	restErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rest_errors_total",
+		Help:      "Finnhub REST calls that failed or returned an error status, by endpoint.",
+	}, []string{"endpoint"})
+	restRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rest_rate_limited_total",
+		Help:      "Finnhub REST calls answered with 429 Too Many Requests, by endpoint.",
+	}, []string{"endpoint"})
+
+	mailboxDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "actor_mailbox_depth",
+		Help:      "Messages sent to actors of a kind that they haven't handled yet.",
+	}, []string{"actor"})
+
+	mailboxDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actor_mailbox_dropped_total",
+		Help:      "Messages not sent because the actor's mailbox was full.",
+	}, []string{"actor"})
+
+	actorRestarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actor_restarts_total",
+		Help:      "Actors restarted after a panic, by kind.",
+	}, []string{"actor"})
+	actorGaveUp = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "actor_gave_up_total",
+		Help:      "Actors that crashed past their restart limit, by kind.",
+	}, []string{"actor"})
+	deadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_letters_total",
+		Help:      "Messages sent to actors that weren't there anymore, by message type.",
+	}, []string{"type"})
+
+	queueDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_dropped_total",
+		Help:      "Items a full queue had to throw away.",
+	}, []string{"queue"})
+	conflated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "conflated_total",
+		Help:      "Updates overwritten by newer ones before they were displayed.",
+	}, []string{"queue"})
+)
+
+// when the last websocket message arrived, in unix nanos
+var lastMessage atomic.Int64
+
+var connected atomic.Bool
+
+// per type message counts of the current second, see rateLoop
+var (
+	rateMu     sync.Mutex
+	rateCounts = make(map[string]float64)
+)
+
+// SetConnected records the websocket going up or down
+func SetConnected(up bool) {
+	connected.Store(up)
+	if up {
+		wsConnected.Set(1)
+		return
+	}
+	wsConnected.Set(0)
+}
+
+func Connected() bool {
+	return connected.Load()
+}
+
+// Reconnected counts a websocket redial
+func Reconnected() {
+	wsReconnects.Inc()
+}
+
+// WSMessage counts one websocket message of msgType
+func WSMessage(msgType string) {
+	if msgType == "" {
+		msgType = "unknown"
+	}
+	lastMessage.Store(time.Now().UnixNano())
+	wsMessages.WithLabelValues(msgType).Inc()
+
+	rateMu.Lock()
+	rateCounts[msgType]++
+	rateMu.Unlock()
+}
+
+// LastMessage is when the websocket last sent anything, zero before the first message
+func LastMessage() time.Time {
+	nanos := lastMessage.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// rateLoop turns the per second counts into the messages per second gauge
+func rateLoop() {
+	seen := make(map[string]bool)
+	for range time.Tick(time.Second) {
+		rateMu.Lock()
+		counts := rateCounts
+		rateCounts = make(map[string]float64)
+		rateMu.Unlock()
+
+		for msgType := range seen {
+			if _, ok := counts[msgType]; !ok {
+				wsMessageRate.WithLabelValues(msgType).Set(0)
+			}
+		}
+		for msgType, n := range counts {
+			seen[msgType] = true
+			wsMessageRate.WithLabelValues(msgType).Set(n)
+		}
+	}
+}
+
+// Rendered records a trade that traded at traded, by the exchange's
+// clock, making it on screen
+func Rendered(traded time.Time) {
+	tradeRenderLatency.Observe(time.Since(traded).Seconds())
+}
+
+// WatchQueue exports the fill level of a queue. watching another queue
+// under the same name replaces the first one
+func WatchQueue(queue string, length, capacity func() int) {
+	queues.mu.Lock()
+	queues.watched[queue] = watchedQueue{length: length, capacity: capacity}
+	queues.mu.Unlock()
+}
+
+type watchedQueue struct {
+	length, capacity func() int
+}
+
+// queueCollector reads the watched queues at scrape time, one collector
+// for all of them since a gauge func per queue can only be registered once
+type queueCollector struct {
+	mu       sync.Mutex
+	watched  map[string]watchedQueue
+	length   *prometheus.Desc
+	capacity *prometheus.Desc
+}
+
+var queues = &queueCollector{
+	watched: make(map[string]watchedQueue),
+	length: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_length"),
+		"Items waiting in a queue.", []string{"queue"}, nil),
+	capacity: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_capacity"),
+		"How many items a queue holds before it drops.", []string{"queue"}, nil),
+}
+
+func init() {
+	prometheus.MustRegister(queues)
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- q.length
+	ch <- q.capacity
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for name, w := range q.watched {
+		ch <- prometheus.MustNewConstMetric(q.length, prometheus.GaugeValue, float64(w.length()), name)
+		ch <- prometheus.MustNewConstMetric(q.capacity, prometheus.GaugeValue, float64(w.capacity()), name)
+	}
+}
+
+// Dropped counts an item a full queue threw away
+func Dropped(queue string) {
+	queueDropped.WithLabelValues(queue).Inc()
+}
+
+// Conflated counts n updates overwritten before they were displayed
+func Conflated(queue string, n int) {
+	conflated.WithLabelValues(queue).Add(float64(n))
+}
+
+// Restarted counts a restart of an actor of kind
+func Restarted(kind string) {
+	actorRestarts.WithLabelValues(kind).Inc()
+}
+
+// GaveUp counts an actor of kind crashing past its restart limit
+func GaveUp(kind string) {
+	actorGaveUp.WithLabelValues(kind).Inc()
+}
+
+// DeadLetter counts a message of msgType nobody was there to receive
+func DeadLetter(msgType string) {
+	deadLetters.WithLabelValues(msgType).Inc()
+}
+
+// Mailbox tracks the depth of one kind of actor's mailbox. hollywood
+// doesn't expose its inboxes so senders count what they send and the
+// actor counts what it handled. With a capacity, Reserve turns senders
+// away once that many messages are waiting
+type Mailbox struct {
+	capacity int64 // 0 is unbounded
+	n        atomic.Int64
+	depth    prometheus.Gauge
+	dropped  prometheus.Counter
+}
+
+// NewMailbox tracks the mailboxes of actor, capacity 0 never turns anyone away
+func NewMailbox(actor string, capacity int) *Mailbox {
+	return &Mailbox{
+		capacity: int64(capacity),
+		depth:    mailboxDepth.WithLabelValues(actor),
+		dropped:  mailboxDropped.WithLabelValues(actor),
+	}
+}
+
+// Sent counts a message put in the mailbox
+func (m *Mailbox) Sent() {
+	m.n.Add(1)
+	m.depth.Inc()
+}
+
+// Reserve counts a message about to be sent if there's room for it,
+// false means don't send it, the drop is already counted
+func (m *Mailbox) Reserve() bool {
+	if m.capacity > 0 && m.n.Add(1) > m.capacity {
+		m.n.Add(-1)
+		m.dropped.Inc()
+		return false
+	}
+	if m.capacity <= 0 {
+		m.n.Add(1)
+	}
+	m.depth.Inc()
+	return true
+}
+
+// Handled counts a message taken out of the mailbox
+func (m *Mailbox) Handled() {
+	m.n.Add(-1)
+	m.depth.Dec()
+}
+
+// Depth is how many messages are waiting
+func (m *Mailbox) Depth() int64 {
+	return m.n.Load()
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWatchQueueTwice(t *testing.T) {
+	WatchQueue("test", func() int { return 1 }, func() int { return 8 })
+	// a second queue under the same name used to panic registering
+	WatchQueue("test", func() int { return 3 }, func() int { return 16 })
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "queue" && l.GetValue() == "test" && m.GetGauge() != nil {
+					got[f.GetName()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	if got["stockspider_queue_length"] != 3 || got["stockspider_queue_capacity"] != 16 {
+		t.Fatalf("got %v, want length 3 and capacity 16", got)
+	}
+}
@@ -0,0 +1,182 @@
+package node
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/eventpb"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// a viewer that hasn't sent Subscribe in this long is dropped, they
+// resend every resubscribeInterval
+var viewerTimeout = 3 * resubscribeInterval
+
+// ExporterMailbox is the depth of the exporter's mailbox, everything the
+// viewers asked for goes through it
+var ExporterMailbox = metrics.NewMailbox("exporter", 1<<16)
+
+// ExporterPolicy restarts a crashed exporter, viewers come back on their
+// own with the next Subscribe
+var ExporterPolicy = supervise.Policy{
+	MaxRestarts: 100,
+	Delay:       100 * time.Millisecond,
+}
+
+type sweep struct{}
+
+type viewer struct {
+	pid      *actor.PID
+	patterns []string
+	seen     time.Time
+}
+
+// Exporter runs on the ingest node and sends what's published on its bus
+// to the viewers that subscribed to it, each event converted once no
+// matter how many viewers get it
+type Exporter struct {
+	viewers  map[string]*viewer // by pid
+	patterns map[string]bool    // subscribed to on the bus
+	selected string
+	repeater actor.SendRepeater
+	log      *slog.Logger
+}
+
+// StartExporter spawns the exporter on the ingest node e
+func StartExporter(e *actor.Engine, log *slog.Logger) *actor.PID {
+	opts := append(ExporterPolicy.Opts(), actor.WithID(exporterID))
+	return e.Spawn(func() actor.Receiver {
+		return &Exporter{
+			viewers:  make(map[string]*viewer),
+			patterns: make(map[string]bool),
+			log:      log,
+		}
+	}, exporterKind, opts...)
+}
+
+func (x *Exporter) Receive(c *actor.Context) {
+	switch msg := c.Message().(type) {
+	case actor.Started:
+		// for RemoteUnreachableEvent, a viewer that went away is dropped
+		// straight off instead of waiting out viewerTimeout
+		c.Engine().Subscribe(c.PID())
+		x.repeater = c.SendRepeat(c.PID(), sweep{}, resubscribeInterval)
+	case actor.Stopped:
+		x.repeater.Stop()
+		c.Engine().Unsubscribe(c.PID())
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{PID: c.PID()})
+	case *Subscribe:
+		x.subscribe(c, msg)
+	case *Unsubscribe:
+		if sender := c.Sender(); sender != nil {
+			x.drop(c, sender.String())
+		}
+	case sweep:
+		now := time.Now()
+		for key, v := range x.viewers {
+			if now.Sub(v.seen) > viewerTimeout {
+				x.log.Info("viewer timed out", "viewer", key)
+				x.drop(c, key)
+			}
+		}
+	case actor.RemoteUnreachableEvent:
+		for key, v := range x.viewers {
+			if v.pid.GetAddress() == msg.ListenAddr {
+				x.log.Info("viewer unreachable", "viewer", key)
+				x.drop(c, key)
+			}
+		}
+	default:
+		topic, ok := TopicOf(msg)
+		if !ok {
+			return
+		}
+		ExporterMailbox.Handled()
+		x.export(c, topic, msg)
+	}
+}
+
+func (x *Exporter) subscribe(c *actor.Context, msg *Subscribe) {
+	sender := c.Sender()
+	if sender == nil {
+		return
+	}
+	key := sender.String()
+	v, ok := x.viewers[key]
+	if !ok {
+		x.log.Info("viewer subscribed", "viewer", key, "patterns", msg.GetPatterns())
+		v = &viewer{pid: sender}
+		x.viewers[key] = v
+	}
+	v.patterns = msg.GetPatterns()
+	v.seen = time.Now()
+	x.resubscribe(c)
+
+	// only a change is followed, every viewer repeats its selection with
+	// each Subscribe and the last one to pick something new wins
+	if sel := msg.GetSelected(); sel != "" && sel != x.selected {
+		x.selected = sel
+		bus.Publish(c.Engine(), bus.Selected, event.Selected{
+			Pair: event.Pair{Exchange: "finnhub", Symbol: sel},
+		})
+	}
+}
+
+func (x *Exporter) drop(c *actor.Context, key string) {
+	delete(x.viewers, key)
+	x.resubscribe(c)
+}
+
+// resubscribe keeps the exporter's own bus subscriptions to what the
+// viewers together asked for
+func (x *Exporter) resubscribe(c *actor.Context) {
+	want := make(map[string]bool)
+	for _, v := range x.viewers {
+		for _, p := range v.patterns {
+			want[p] = true
+		}
+	}
+	for p := range want {
+		if x.patterns[p] {
+			continue
+		}
+		x.patterns[p] = true
+		c.Send(bus.PID(c.Engine()), bus.Subscribe{
+			Pattern: p,
+			PID:     c.PID(),
+			// selections only go from viewers to the ingest node
+			Filter:  func(msg any) bool { _, sel := msg.(event.Selected); return !sel },
+			Mailbox: ExporterMailbox,
+		})
+	}
+	for p := range x.patterns {
+		if want[p] {
+			continue
+		}
+		delete(x.patterns, p)
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{Pattern: p, PID: c.PID()})
+	}
+}
+
+func (x *Exporter) export(c *actor.Context, topic bus.Topic, msg any) {
+	var m any
+	for _, v := range x.viewers {
+		if !slices.ContainsFunc(v.patterns, func(p string) bool { return bus.Match(p, topic) }) {
+			continue
+		}
+		if m == nil {
+			pm, ok := eventpb.FromEvent(msg)
+			if !ok {
+				return
+			}
+			m = pm
+		}
+		c.Send(v.pid, m)
+	}
+}
@@ -0,0 +1,91 @@
+package node
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/eventpb"
+	"github.com/Scrimzay/stockspider/metrics"
+	"github.com/Scrimzay/stockspider/supervise"
+
+	"github.com/anthdm/hollywood/actor"
+	"google.golang.org/protobuf/proto"
+)
+
+// how often a viewer repeats its Subscribe, it doubles as the heartbeat
+// and picks a restarted ingest node back up
+var resubscribeInterval = 10 * time.Second
+
+// ImporterMailbox counts the viewer's selections, events from the ingest
+// node come in over the network and can't be bounded here
+var ImporterMailbox = metrics.NewMailbox("importer", 0)
+
+// ImporterPolicy restarts a crashed importer, the restart subscribes again
+var ImporterPolicy = supervise.Policy{
+	MaxRestarts: 100,
+	Delay:       100 * time.Millisecond,
+}
+
+type resubscribe struct{}
+
+// Importer runs on a viewer node. It subscribes to the ingest node's
+// exporter, publishes what comes back on the local bus and passes the
+// viewer's bus.Selected on to the ingest node
+type Importer struct {
+	exporter *actor.PID
+	patterns []string
+	selected string
+	repeater actor.SendRepeater
+	log      *slog.Logger
+}
+
+// StartImporter spawns the importer on the viewer node e, taking patterns
+// from the ingest node at addr
+func StartImporter(e *actor.Engine, addr string, patterns []string, log *slog.Logger) *actor.PID {
+	return e.Spawn(func() actor.Receiver {
+		return &Importer{
+			exporter: ExporterPID(addr),
+			patterns: patterns,
+			log:      log,
+		}
+	}, "importer", ImporterPolicy.Opts()...)
+}
+
+func (m *Importer) Receive(c *actor.Context) {
+	switch msg := c.Message().(type) {
+	case actor.Started:
+		c.Send(bus.PID(c.Engine()), bus.Subscribe{
+			Pattern: string(bus.Selected),
+			PID:     c.PID(),
+			Filter:  bus.Filter[event.Selected](nil),
+			Mailbox: ImporterMailbox,
+		})
+		m.log.Info("subscribing to ingest node", "addr", m.exporter.GetAddress(), "patterns", m.patterns)
+		m.subscribe(c)
+		m.repeater = c.SendRepeat(c.PID(), resubscribe{}, resubscribeInterval)
+	case actor.Stopped:
+		m.repeater.Stop()
+		c.Send(bus.PID(c.Engine()), bus.Unsubscribe{PID: c.PID()})
+		c.Send(m.exporter, &Unsubscribe{})
+	case resubscribe:
+		m.subscribe(c)
+	case event.Selected:
+		ImporterMailbox.Handled()
+		m.selected = msg.Pair.Symbol
+		m.subscribe(c)
+	case proto.Message:
+		v, ok := eventpb.ToEvent(msg)
+		if !ok {
+			return
+		}
+		if topic, ok := TopicOf(v); ok {
+			bus.Publish(c.Engine(), topic, v)
+		}
+	}
+}
+
+func (m *Importer) subscribe(c *actor.Context) {
+	c.Send(m.exporter, &Subscribe{Patterns: m.patterns, Selected: m.selected})
+}
@@ -0,0 +1,92 @@
+// Package node splits the app over processes with hollywood remote. An
+// ingest node runs the finnhub and news consumers and an Exporter, viewer
+// nodes run the GUI and an Importer that subscribes to the ingest node by
+// its address. Events cross as eventpb messages and land on the viewer's
+// own bus under the topic they had on the ingest node, so nothing past
+// the bus can tell the difference.
+//
+//	NODE_LISTEN=127.0.0.1:4000 ingest
+//	NODE_LISTEN=127.0.0.1:4001 INGEST_ADDR=127.0.0.1:4000 stockspider
+package node
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative node/node.proto
+
+import (
+	"os"
+
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/event"
+
+	"github.com/anthdm/hollywood/actor"
+	"github.com/anthdm/hollywood/remote"
+)
+
+// the exporter is spawned under a fixed kind and id so viewers can find
+// it from nothing but the ingest node's address
+const (
+	exporterKind = "exporter"
+	exporterID   = "exporter"
+)
+
+// Patterns are what a viewer asks for by default. quotes and candles are
+// made by the GUI itself out of the REST api and the trades, so they
+// aren't among them
+var Patterns = []string{"trades.*", "stats.*", "news.*"}
+
+// Config is where this node listens and, for a viewer, where the ingest
+// node is
+type Config struct {
+	Listen string // host:port for remote, "" runs the node on its own
+	Ingest string // ingest node host:port, "" for the ingest node itself
+}
+
+// ConfigFromEnv reads NODE_LISTEN and INGEST_ADDR
+func ConfigFromEnv() Config {
+	return Config{
+		Listen: os.Getenv("NODE_LISTEN"),
+		Ingest: os.Getenv("INGEST_ADDR"),
+	}
+}
+
+// Viewer reports whether this node takes its events from an ingest node
+func (c Config) Viewer() bool {
+	return c.Ingest != ""
+}
+
+// NewEngine is an engine that listens on cfg.Listen, or a plain local
+// one when that's empty
+func NewEngine(cfg Config) (*actor.Engine, error) {
+	ecfg := actor.NewEngineConfig()
+	if cfg.Listen != "" {
+		ecfg = ecfg.WithRemote(remote.New(cfg.Listen, remote.NewConfig()))
+	}
+	return actor.NewEngine(ecfg)
+}
+
+// ExporterPID is the exporter on the ingest node at addr
+func ExporterPID(addr string) *actor.PID {
+	return actor.NewPID(addr, exporterKind+"/"+exporterID)
+}
+
+// TopicOf is the topic msg is published on. the bus doesn't hand topics
+// to subscribers, the exporter rebuilds them from the event so the
+// importer can publish under the same one
+func TopicOf(msg any) (bus.Topic, bool) {
+	switch msg := msg.(type) {
+	case event.StockTrade:
+		return bus.Trades(msg.Pair), true
+	case event.Stat:
+		return bus.Stats(msg.Pair), true
+	case event.Quote:
+		return bus.Quotes(msg.Pair), true
+	case event.Candle:
+		return bus.Candles(msg.Timeframe, msg.Pair), true
+	case event.News:
+		return bus.News(msg.Pair), true
+	case event.Book:
+		return bus.Books(msg.Pair), true
+	case event.Selected:
+		return bus.Selected, true
+	}
+	return "", false
+}
@@ -0,0 +1,162 @@
+package node
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/bus"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/eventpb"
+	"github.com/Scrimzay/stockspider/logging"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// quietViewer subscribes to the exporter once and never again, so the
+// exporter has to time it out
+type quietViewer struct {
+	exporter *actor.PID
+	trades   chan struct{}
+}
+
+func (v *quietViewer) Receive(c *actor.Context) {
+	switch c.Message().(type) {
+	case actor.Started:
+		c.Send(v.exporter, &Subscribe{Patterns: []string{"trades.*"}})
+	case *eventpb.StockTrade:
+		select {
+		case v.trades <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func TestIngestToViewer(t *testing.T) {
+	oldInterval, oldTimeout := resubscribeInterval, viewerTimeout
+	resubscribeInterval, viewerTimeout = 50*time.Millisecond, 150*time.Millisecond
+	t.Cleanup(func() { resubscribeInterval, viewerTimeout = oldInterval, oldTimeout })
+
+	ingestAddr := freeAddr(t)
+	ingest, err := NewEngine(Config{Listen: ingestAddr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewer, err := NewEngine(Config{Listen: freeAddr(t), Ingest: ingestAddr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Start(ingest)
+	bus.Start(viewer)
+
+	selected := make(chan event.Selected, 16)
+	bus.Func(ingest, "selected", string(bus.Selected), func(v event.Selected) {
+		selected <- v
+	})
+	trades := make(chan event.StockTrade, 1024)
+	bus.Func(viewer, "trades", "trades.*", func(v event.StockTrade) {
+		select {
+		case trades <- v:
+		default:
+		}
+	})
+
+	exporter := StartExporter(ingest, logging.Discard())
+	importer := StartImporter(viewer, ingestAddr, Patterns, logging.Discard())
+	// stop them before the cleanup above puts the timings back
+	t.Cleanup(func() {
+		viewer.Poison(importer).Wait()
+		ingest.Poison(exporter).Wait()
+	})
+
+	pair := event.Pair{Exchange: "finnhub", Symbol: "AAPL"}
+	publish := func(price float64) {
+		bus.Publish(ingest, bus.Trades(pair), event.StockTrade{Pair: pair, Price: price, Qty: 1, Unix: 1700000000000})
+	}
+
+	// the subscription takes a round trip to set up, keep publishing
+	// until one comes through
+	waitFor(t, "trade on the viewer bus", func() bool {
+		publish(1)
+		select {
+		case v := <-trades:
+			return v.Pair == pair && v.Price == 1
+		case <-time.After(20 * time.Millisecond):
+			return false
+		}
+	})
+
+	bus.Publish(viewer, bus.Selected, event.Selected{Pair: event.Pair{Exchange: "finnhub", Symbol: "MSFT"}})
+	select {
+	case v := <-selected:
+		if v.Pair.Symbol != "MSFT" {
+			t.Fatalf("selected %+v on the ingest bus, want MSFT", v.Pair)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("selection didn't reach the ingest bus")
+	}
+
+	quiet := &quietViewer{exporter: ExporterPID(ingestAddr), trades: make(chan struct{}, 1)}
+	viewer.Spawn(func() actor.Receiver { return quiet }, "quiet")
+	waitFor(t, "trade on the quiet viewer", func() bool {
+		publish(2)
+		select {
+		case <-quiet.trades:
+			return true
+		case <-time.After(20 * time.Millisecond):
+			return false
+		}
+	})
+
+	// past the timeout and a sweep it's dropped, while the importer that
+	// keeps resubscribing still gets trades
+	time.Sleep(viewerTimeout + 2*resubscribeInterval)
+	drain(quiet.trades)
+	drain(trades)
+	for i := 0; i < 10; i++ {
+		publish(3)
+	}
+	select {
+	case <-trades:
+	case <-time.After(5 * time.Second):
+		t.Fatal("importer stopped getting trades")
+	}
+	select {
+	case <-quiet.trades:
+		t.Fatal("quiet viewer still gets trades past the heartbeat timeout")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// freeAddr is a 127.0.0.1 port nothing listens on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// waitFor retries try for up to 5s
+func waitFor(t *testing.T, what string, try func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if try() {
+			return
+		}
+	}
+	t.Fatalf("no %s", what)
+}
+
+func drain[T any](ch chan T) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package paper
+
+// Position is a long holding of one symbol. Paper trading doesn't do shorts.
+type Position struct {
+	Symbol   string
+	Qty      float64
+	AvgPrice float64
+	Realized float64 // realized P&L before fees
+}
+
+type Account struct {
+	Cash      float64
+	Fees      float64 // total fees paid
+	Positions map[string]*Position
+	Orders    []*Order
+	Fills     []Fill
+	NextID    int
+	Splits    map[string]bool // splits already applied, see Engine.ApplySplit
+}
+
+func newAccount(cash float64) *Account {
+	return &Account{
+		Cash:      cash,
+		Positions: make(map[string]*Position),
+	}
+}
+
+func (a *Account) position(symbol string) *Position {
+	p, ok := a.Positions[symbol]
+	if !ok {
+		p = &Position{Symbol: symbol}
+		a.Positions[symbol] = p
+	}
+	return p
+}
+
+// apply books a fill against cash and the position
+func (a *Account) apply(f Fill) {
+	p := a.position(f.Symbol)
+	switch f.Side {
+	case Buy:
+		cost := p.AvgPrice*p.Qty + f.Price*f.Qty
+		p.Qty += f.Qty
+		p.AvgPrice = cost / p.Qty
+		a.Cash -= f.Price * f.Qty
+	case Sell:
+		p.Realized += (f.Price - p.AvgPrice) * f.Qty
+		p.Qty -= f.Qty
+		if p.Qty <= 1e-12 {
+			p.Qty = 0
+			p.AvgPrice = 0
+		}
+		a.Cash += f.Price * f.Qty
+	}
+	a.Cash -= f.Fee
+	a.Fees += f.Fee
+	a.Fills = append(a.Fills, f)
+}
+
+// Snapshot is the account valued at the last prices the engine has seen.
+type Snapshot struct {
+	Cash          float64
+	Equity        float64 // cash plus market value of positions
+	MarketValue   float64
+	RealizedPnL   float64 // before fees
+	UnrealizedPnL float64
+	Fees          float64
+	Positions     []PositionValue
+}
+
+type PositionValue struct {
+	Position
+	Last          float64
+	MarketValue   float64
+	UnrealizedPnL float64
+}
@@ -0,0 +1,535 @@
+// Package paper is a paper-trading engine: orders are matched against the
+// live trade feed with simulated slippage and fees, and the account is kept
+// on disk between runs. No real orders are ever sent anywhere.
+package paper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type Config struct {
+	StartingCash  float64
+	SlippageBps   float64 // moves every fill against the order, in basis points
+	FeeRate       float64 // fraction of notional, 0.001 is 10bps
+	FeePerShare   float64
+	MinFee        float64
+	RespectVolume bool   // don't fill more than the trade we match against printed
+	Path          string // where the account is saved, "" keeps it in memory
+
+	// Now is the engine's clock, backtests swap in a simulated one
+	Now func() time.Time
+}
+
+func DefaultConfig() Config {
+	return Config{
+		StartingCash:  100_000,
+		SlippageBps:   2,
+		FeeRate:       0.0005,
+		MinFee:        0.5,
+		RespectVolume: true,
+		Path:          filepath.Join("data", "paper", "account.json"),
+	}
+}
+
+type Engine struct {
+	mu       sync.Mutex
+	cfg      Config
+	acct     *Account
+	last     map[string]float64 // last trade price per symbol
+	lastUnix map[string]int64   // unix millis of that trade
+
+	// OnFill is called for every fill, outside the engine lock
+	OnFill func(Fill)
+}
+
+// state is what gets persisted
+type state struct {
+	Account  *Account
+	Last     map[string]float64
+	LastUnix map[string]int64
+}
+
+// New creates an engine, picking the account back up from cfg.Path when
+// there is one.
+func New(cfg Config) (*Engine, error) {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	e := &Engine{
+		cfg:      cfg,
+		acct:     newAccount(cfg.StartingCash),
+		last:     make(map[string]float64),
+		lastUnix: make(map[string]int64),
+	}
+	if cfg.Path == "" {
+		return e, nil
+	}
+
+	b, err := os.ReadFile(cfg.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return e, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading paper account: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, fmt.Errorf("decoding paper account %s: %w", cfg.Path, err)
+	}
+	if st.Account != nil {
+		e.acct = st.Account
+		if e.acct.Positions == nil {
+			e.acct.Positions = make(map[string]*Position)
+		}
+	}
+	if st.Last != nil {
+		e.last = st.Last
+	}
+	if st.LastUnix != nil {
+		e.lastUnix = st.LastUnix
+	}
+	return e, nil
+}
+
+// save writes the account through a temp file so a crash mid-write can't
+// leave a truncated account behind
+func (e *Engine) save() error {
+	if e.cfg.Path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(state{Account: e.acct, Last: e.last, LastUnix: e.lastUnix}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(e.cfg.Path), 0755); err != nil {
+		return err
+	}
+	tmp := e.cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.cfg.Path)
+}
+
+// expiry is when a day order dies: the venue's next close, or the end of
+// the UTC day for venues that never close
+func expiry(symbol string, now time.Time) time.Time {
+	exchange := calendar.For(event.Pair{Symbol: symbol})
+	if close := exchange.NextClose(now); !close.IsZero() {
+		return close
+	}
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Submit validates o and queues it. Orders only fill against trades that
+// arrive after they are submitted.
+func (e *Engine) Submit(o Order) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o.Symbol = strings.ToLower(o.Symbol)
+	if o.TIF == "" {
+		o.TIF = GTC
+	}
+	if err := o.validate(); err != nil {
+		return o, err
+	}
+
+	switch o.Side {
+	case Sell:
+		if o.Qty > e.sellable(o.Symbol)+1e-9 {
+			return o, fmt.Errorf("can't sell %g %s, paper trading doesn't short", o.Qty, o.Symbol)
+		}
+	case Buy:
+		price := o.LimitPrice
+		if o.Type == Market || o.Type == Stop {
+			price = math.Max(e.last[o.Symbol], o.StopPrice)
+		}
+		if price > 0 && price*o.Qty > e.acct.Cash {
+			return o, fmt.Errorf("not enough cash for %g %s at %.2f", o.Qty, o.Symbol, price)
+		}
+	}
+
+	now := e.cfg.Now()
+	e.acct.NextID++
+	o.ID = fmt.Sprintf("P%d", e.acct.NextID)
+	o.Status = Open
+	o.FilledQty = 0
+	o.AvgPrice = 0
+	o.Triggered = false
+	o.Created = now
+	o.Updated = now
+	if o.TIF == Day {
+		o.Expires = expiry(o.Symbol, now)
+	}
+
+	e.acct.Orders = append(e.acct.Orders, &o)
+	if err := e.save(); err != nil {
+		// take it back out, an order that isn't on disk would come back
+		// unfilled or not at all after a restart
+		e.acct.Orders = e.acct.Orders[:len(e.acct.Orders)-1]
+		e.acct.NextID--
+		return o, err
+	}
+	return o, nil
+}
+
+// sellable is the held quantity not already promised to open sell orders
+func (e *Engine) sellable(symbol string) float64 {
+	qty := 0.0
+	if p, ok := e.acct.Positions[symbol]; ok {
+		qty = p.Qty
+	}
+	for _, o := range e.acct.Orders {
+		if o.Symbol == symbol && o.Side == Sell && !o.Status.Done() {
+			qty -= o.Remaining()
+		}
+	}
+	return qty
+}
+
+func (e *Engine) Cancel(id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, o := range e.acct.Orders {
+		if o.ID != id {
+			continue
+		}
+		if o.Status.Done() {
+			return fmt.Errorf("order %s is already %s", id, o.Status)
+		}
+		before := *o
+		o.Status = Cancelled
+		o.Reason = "cancelled by user"
+		o.Updated = e.cfg.Now()
+		if err := e.save(); err != nil {
+			*o = before
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("no order %s", id)
+}
+
+// Expire kills day orders past their expiry. OnTrade does this itself for
+// the symbol it matches, this catches the symbols that stopped trading.
+// The error is from saving the account.
+func (e *Engine) Expire(now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	changed := false
+	for _, o := range e.acct.Orders {
+		if !o.Status.Done() && !o.Expires.IsZero() && !now.Before(o.Expires) {
+			o.Status = Expired
+			o.Updated = now
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return e.save()
+}
+
+func (e *Engine) slip(side Side, price float64) float64 {
+	slip := price * e.cfg.SlippageBps / 10_000
+	if side == Buy {
+		return price + slip
+	}
+	return price - slip
+}
+
+func (e *Engine) fee(qty, price float64) float64 {
+	return math.Max(e.cfg.MinFee, qty*price*e.cfg.FeeRate+qty*e.cfg.FeePerShare)
+}
+
+// OnTrade matches open orders for the trade's symbol against its price and
+// returns the fills it produced. The fills stand even when the error from
+// saving the account afterwards doesn't.
+func (e *Engine) OnTrade(trade event.StockTrade) ([]Fill, error) {
+	e.mu.Lock()
+
+	symbol := strings.ToLower(trade.Pair.Symbol)
+	e.last[symbol] = trade.Price
+	e.lastUnix[symbol] = trade.Unix
+	at := time.UnixMilli(trade.Unix)
+	available := trade.Qty
+
+	var fills []Fill
+	changed := false
+	for _, o := range e.acct.Orders {
+		if o.Symbol != symbol || o.Status.Done() {
+			continue
+		}
+		// orders don't fill against trades from before they existed
+		if at.Before(o.Created.Truncate(time.Millisecond)) {
+			continue
+		}
+		before := *o
+
+		if !o.Expires.IsZero() && !at.Before(o.Expires) {
+			o.Status = Expired
+			o.Updated = at
+			changed = true
+			continue
+		}
+
+		if f, ok := e.match(o, trade.Price, &available, at); ok {
+			e.acct.apply(f)
+			fills = append(fills, f)
+		}
+
+		// ioc and fok only get the first trade after they're placed
+		if (o.TIF == IOC || o.TIF == FOK) && !o.Status.Done() {
+			o.Status = Cancelled
+			o.Reason = fmt.Sprintf("%s remainder", o.TIF)
+			o.Updated = at
+		}
+		if *o != before {
+			changed = true
+		}
+	}
+
+	var err error
+	if changed {
+		err = e.save()
+	}
+	e.mu.Unlock()
+
+	if e.OnFill != nil {
+		for _, f := range fills {
+			e.OnFill(f)
+		}
+	}
+	return fills, err
+}
+
+// match tries to execute o against a trade at price, available is the
+// trade size the orders before it haven't used up
+func (e *Engine) match(o *Order, price float64, available *float64, at time.Time) (Fill, bool) {
+	if (o.Type == Stop || o.Type == StopLimit) && !o.Triggered {
+		if (o.Side == Buy && price >= o.StopPrice) || (o.Side == Sell && price <= o.StopPrice) {
+			o.Triggered = true
+			o.Updated = at
+		} else {
+			return Fill{}, false
+		}
+	}
+
+	exec := e.slip(o.Side, price)
+	if o.Type == Limit || o.Type == StopLimit {
+		switch {
+		case o.Side == Buy && price <= o.LimitPrice:
+			exec = math.Min(exec, o.LimitPrice)
+		case o.Side == Sell && price >= o.LimitPrice:
+			exec = math.Max(exec, o.LimitPrice)
+		default:
+			return Fill{}, false
+		}
+	}
+
+	qty := o.Remaining()
+	if e.cfg.RespectVolume {
+		qty = math.Min(qty, *available)
+	}
+	switch o.Side {
+	case Buy:
+		// shrink to what the cash covers, fee included
+		if cost := qty*exec + e.fee(qty, exec); cost > e.acct.Cash {
+			qty = math.Floor(qty*e.acct.Cash/cost*1e8) / 1e8
+		}
+	case Sell:
+		if p, ok := e.acct.Positions[o.Symbol]; !ok || p.Qty < qty {
+			qty = 0
+			if ok {
+				qty = p.Qty
+			}
+		}
+	}
+
+	if qty <= 0 || (o.TIF == FOK && qty < o.Remaining()-1e-9) {
+		if o.TIF != FOK && qty <= 0 && *available > 0 {
+			o.Status = Rejected
+			o.Reason = "insufficient cash or position"
+			o.Updated = at
+		}
+		return Fill{}, false
+	}
+
+	*available -= qty
+	o.AvgPrice = (o.AvgPrice*o.FilledQty + exec*qty) / (o.FilledQty + qty)
+	o.FilledQty += qty
+	o.Status = PartiallyFilled
+	if o.Remaining() <= 1e-9 {
+		o.Status = Filled
+	}
+	o.Updated = at
+
+	return Fill{
+		OrderID: o.ID,
+		Symbol:  o.Symbol,
+		Side:    o.Side,
+		Qty:     qty,
+		Price:   exec,
+		Fee:     e.fee(qty, exec),
+		Unix:    at.UnixMilli(),
+	}, true
+}
+
+// Now is the engine's clock.
+func (e *Engine) Now() time.Time {
+	return e.cfg.Now()
+}
+
+// Orders returns copies of the orders, newest first. With openOnly it
+// skips the ones that can't fill anymore.
+func (e *Engine) Orders(openOnly bool) []Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var orders []Order
+	for i := len(e.acct.Orders) - 1; i >= 0; i-- {
+		o := e.acct.Orders[i]
+		if openOnly && o.Status.Done() {
+			continue
+		}
+		orders = append(orders, *o)
+	}
+	return orders
+}
+
+// Fills returns every fill so far, oldest first.
+func (e *Engine) Fills() []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fills := make([]Fill, len(e.acct.Fills))
+	copy(fills, e.acct.Fills)
+	return fills
+}
+
+// Last is the last trade price the engine saw for symbol.
+func (e *Engine) Last(symbol string) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	price, ok := e.last[strings.ToLower(symbol)]
+	return price, ok
+}
+
+func (e *Engine) Snapshot() Snapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	snap := Snapshot{
+		Cash: e.acct.Cash,
+		Fees: e.acct.Fees,
+	}
+	for _, p := range e.acct.Positions {
+		snap.RealizedPnL += p.Realized
+		if p.Qty == 0 {
+			continue
+		}
+		last, ok := e.last[p.Symbol]
+		if !ok {
+			last = p.AvgPrice
+		}
+		pv := PositionValue{
+			Position:      *p,
+			Last:          last,
+			MarketValue:   p.Qty * last,
+			UnrealizedPnL: (last - p.AvgPrice) * p.Qty,
+		}
+		snap.MarketValue += pv.MarketValue
+		snap.UnrealizedPnL += pv.UnrealizedPnL
+		snap.Positions = append(snap.Positions, pv)
+	}
+	sort.Slice(snap.Positions, func(i, j int) bool { return snap.Positions[i].Symbol < snap.Positions[j].Symbol })
+	snap.Equity = snap.Cash + snap.MarketValue
+	return snap
+}
+
+// ApplySplit adjusts the account for symbol splitting ratio-for-one at at:
+// shares held from before the split are multiplied by ratio at the same
+// cost basis, and open orders placed before it get their quantity scaled
+// and prices divided. A split is only applied once it has taken effect and
+// only once per account, splits of a symbol have to be applied oldest
+// first. It reports whether anything changed.
+func (e *Engine) ApplySplit(symbol string, at time.Time, ratio float64) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	symbol = strings.ToLower(symbol)
+	key := symbol + "@" + at.UTC().Format("2006-01-02")
+	if ratio <= 0 || ratio == 1 || e.acct.Splits[key] || e.cfg.Now().Before(at) {
+		return false, nil
+	}
+	if e.acct.Splits == nil {
+		e.acct.Splits = make(map[string]bool)
+	}
+	e.acct.Splits[key] = true
+
+	// only the shares held going into the split multiply, anything traded
+	// after it was already at post-split prices. that's the position less
+	// the fills since, the position already has earlier splits in it where
+	// summing the fills before would count those shares pre-split
+	after := 0.0
+	for _, f := range e.acct.Fills {
+		if f.Symbol != symbol || f.Unix < at.UnixMilli() {
+			continue
+		}
+		if f.Side == Buy {
+			after += f.Qty
+		} else {
+			after -= f.Qty
+		}
+	}
+	if p, ok := e.acct.Positions[symbol]; ok && p.Qty > 0 && p.Qty-after > 0 {
+		held := p.Qty - after
+		cost := p.AvgPrice * p.Qty
+		p.Qty += held * (ratio - 1)
+		p.AvgPrice = cost / p.Qty
+	}
+
+	for _, o := range e.acct.Orders {
+		if o.Symbol != symbol || o.Status.Done() || !o.Created.Before(at) {
+			continue
+		}
+		o.Qty = o.FilledQty + o.Remaining()*ratio
+		o.LimitPrice /= ratio
+		o.StopPrice /= ratio
+		o.Updated = e.cfg.Now()
+	}
+
+	// the last price is pre-split until the first trade after it
+	if last, ok := e.last[symbol]; ok && e.lastUnix[symbol] < at.UnixMilli() {
+		e.last[symbol] = last / ratio
+	}
+
+	return true, e.save()
+}
+
+// Reset wipes the account back to the starting cash.
+func (e *Engine) Reset() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.acct = newAccount(e.cfg.StartingCash)
+	return e.save()
+}
@@ -0,0 +1,227 @@
+package paper
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestEngine(t *testing.T) (*Engine, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)}
+	e, err := New(Config{StartingCash: 1_000_000, RespectVolume: false, Now: clock.Now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e, clock
+}
+
+// trade fills what's open at price and moves the clock there
+func (c *testClock) trade(t *testing.T, e *Engine, symbol string, price float64) {
+	t.Helper()
+	c.now = c.now.Add(time.Hour)
+	if _, err := e.OnTrade(event.StockTrade{Pair: event.Pair{Symbol: symbol}, Price: price, Qty: 1e6, Unix: c.now.UnixMilli()}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *testClock) order(t *testing.T, e *Engine, symbol string, side Side, qty float64) {
+	t.Helper()
+	if _, err := e.Submit(Order{Symbol: symbol, Side: side, Type: Market, Qty: qty}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// split applies a ratio split taking effect a day after now, and moves
+// the clock past it
+func (c *testClock) split(t *testing.T, e *Engine, symbol string, ratio float64) {
+	t.Helper()
+	c.now = c.now.Add(24 * time.Hour)
+	at := time.Date(c.now.Year(), c.now.Month(), c.now.Day(), 0, 0, 0, 0, time.UTC)
+	c.splitAt(t, e, symbol, at, ratio)
+}
+
+func (c *testClock) splitAt(t *testing.T, e *Engine, symbol string, at time.Time, ratio float64) {
+	t.Helper()
+	if c.now.Before(at) {
+		c.now = at
+	}
+	changed, err := e.ApplySplit(symbol, at, ratio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Fatalf("split of %s at %v didn't apply", symbol, at)
+	}
+}
+
+func position(e *Engine, symbol string) Position {
+	for _, p := range e.Snapshot().Positions {
+		if p.Symbol == symbol {
+			return p.Position
+		}
+	}
+	return Position{}
+}
+
+func TestApplySplit(t *testing.T) {
+	const sym = "aapl"
+	tests := []struct {
+		name string
+		run  func(t *testing.T, e *Engine, c *testClock)
+		want float64
+	}{
+		{"held through", func(t *testing.T, e *Engine, c *testClock) {
+			c.order(t, e, sym, Buy, 10)
+			c.trade(t, e, sym, 100)
+			c.split(t, e, sym, 2)
+		}, 20},
+		{"bought after", func(t *testing.T, e *Engine, c *testClock) {
+			c.order(t, e, sym, Buy, 10)
+			c.trade(t, e, sym, 100)
+			at := c.now.Add(30 * time.Minute)
+			c.order(t, e, sym, Buy, 5)
+			c.trade(t, e, sym, 50)
+			c.splitAt(t, e, sym, at, 2)
+		}, 25},
+		{"sold after", func(t *testing.T, e *Engine, c *testClock) {
+			c.order(t, e, sym, Buy, 10)
+			c.trade(t, e, sym, 100)
+			at := c.now.Add(30 * time.Minute)
+			c.order(t, e, sym, Sell, 5)
+			c.trade(t, e, sym, 50)
+			c.splitAt(t, e, sym, at, 2)
+		}, 15},
+		{"two splits", func(t *testing.T, e *Engine, c *testClock) {
+			c.order(t, e, sym, Buy, 10)
+			c.trade(t, e, sym, 100)
+			c.split(t, e, sym, 2)
+			// the 20 held going into the second split all multiply, not
+			// just the 10 the fills before it add up to
+			c.split(t, e, sym, 3)
+		}, 60},
+		{"two splits with a buy between", func(t *testing.T, e *Engine, c *testClock) {
+			c.order(t, e, sym, Buy, 10)
+			c.trade(t, e, sym, 100)
+			c.split(t, e, sym, 2)
+			c.order(t, e, sym, Buy, 4)
+			c.trade(t, e, sym, 50)
+			c.split(t, e, sym, 3)
+		}, 72},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, c := newTestEngine(t)
+			tt.run(t, e, c)
+			p := position(e, sym)
+			if math.Abs(p.Qty-tt.want) > 1e-9 {
+				t.Fatalf("holding %g, want %g", p.Qty, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySplitKeepsCost(t *testing.T) {
+	e, c := newTestEngine(t)
+	c.order(t, e, "aapl", Buy, 10)
+	c.trade(t, e, "aapl", 100)
+	before := position(e, "aapl")
+	c.split(t, e, "aapl", 4)
+	after := position(e, "aapl")
+	if math.Abs(before.Qty*before.AvgPrice-after.Qty*after.AvgPrice) > 1e-6 {
+		t.Fatalf("cost went from %g to %g", before.Qty*before.AvgPrice, after.Qty*after.AvgPrice)
+	}
+	// a second go at the same split changes nothing
+	at := time.Date(c.now.Year(), c.now.Month(), c.now.Day(), 0, 0, 0, 0, time.UTC)
+	if changed, err := e.ApplySplit("aapl", at, 4); err != nil || changed {
+		t.Fatalf("applied the same split again: %v %v", changed, err)
+	}
+}
+
+func TestExpireReturnsSaveError(t *testing.T) {
+	e, c := newTestEngine(t)
+	if _, err := e.Submit(Order{Symbol: "aapl", Side: Buy, Type: Limit, LimitPrice: 1, Qty: 1, TIF: Day}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Expire(c.now); err != nil {
+		t.Fatalf("nothing expired yet and got %v", err)
+	}
+
+	blockSave(t, e)
+	if err := e.Expire(c.now.Add(7 * 24 * time.Hour)); err == nil {
+		t.Fatal("expire saved into a path that can't exist")
+	}
+	if o := e.Orders(false)[0]; o.Status != Expired {
+		t.Fatalf("order is %s, want expired even though the save failed", o.Status)
+	}
+}
+
+// blockSave points the account at a path under a file, so every save fails
+func blockSave(t *testing.T, e *Engine) {
+	t.Helper()
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.cfg.Path = filepath.Join(blocker, "account.json")
+}
+
+func TestSaveFailureRollsBack(t *testing.T) {
+	e, _ := newTestEngine(t)
+	placed, err := e.Submit(Order{Symbol: "aapl", Side: Buy, Type: Limit, LimitPrice: 1, Qty: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blockSave(t, e)
+	if _, err := e.Submit(Order{Symbol: "aapl", Side: Buy, Type: Limit, LimitPrice: 1, Qty: 2}); err == nil {
+		t.Fatal("submit saved into a path that can't exist")
+	}
+	if orders := e.Orders(false); len(orders) != 1 || orders[0].ID != placed.ID {
+		t.Fatalf("orders %+v after a failed submit, want only %s", orders, placed.ID)
+	}
+	if err := e.Cancel(placed.ID); err == nil {
+		t.Fatal("cancel saved into a path that can't exist")
+	}
+	if o := e.Orders(false)[0]; o.Status != Open || o.Reason != "" {
+		t.Fatalf("order is %s (%q) after a failed cancel, want open", o.Status, o.Reason)
+	}
+
+	// once saving works the ids carry on where the saved account left off
+	e.cfg.Path = filepath.Join(t.TempDir(), "account.json")
+	next, err := e.Submit(Order{Symbol: "aapl", Side: Buy, Type: Limit, LimitPrice: 1, Qty: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != "P2" {
+		t.Fatalf("next order is %s, want P2", next.ID)
+	}
+}
+
+func TestOnTradeReturnsSaveError(t *testing.T) {
+	e, c := newTestEngine(t)
+	c.order(t, e, "aapl", Buy, 10)
+
+	blockSave(t, e)
+	c.now = c.now.Add(time.Hour)
+	fills, err := e.OnTrade(event.StockTrade{Pair: event.Pair{Symbol: "aapl"}, Price: 100, Qty: 1e6, Unix: c.now.UnixMilli()})
+	if err == nil {
+		t.Fatal("trade saved into a path that can't exist")
+	}
+	if len(fills) != 1 || fills[0].Qty != 10 {
+		t.Fatalf("fills %+v, want the order filled anyway", fills)
+	}
+
+	// nothing open, nothing to save
+	if _, err := e.OnTrade(event.StockTrade{Pair: event.Pair{Symbol: "aapl"}, Price: 101, Qty: 1e6, Unix: c.now.UnixMilli()}); err != nil {
+		t.Fatalf("trade that changed no orders returned %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package paper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+type OrderType string
+
+const (
+	Market    OrderType = "market"
+	Limit     OrderType = "limit"
+	Stop      OrderType = "stop"
+	StopLimit OrderType = "stop_limit"
+)
+
+// OrderTypes lists every order type, in the order the GUI shows them.
+var OrderTypes = []OrderType{Market, Limit, Stop, StopLimit}
+
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "gtc" // good til cancelled
+	Day TimeInForce = "day" // expires at the venue's close
+	IOC TimeInForce = "ioc" // immediate or cancel, fills what it can on the next trade
+	FOK TimeInForce = "fok" // fill or kill, all of it on the next trade or nothing
+)
+
+var TimeInForces = []TimeInForce{GTC, Day, IOC, FOK}
+
+type OrderStatus string
+
+const (
+	Open            OrderStatus = "open"
+	PartiallyFilled OrderStatus = "partially_filled"
+	Filled          OrderStatus = "filled"
+	Cancelled       OrderStatus = "cancelled"
+	Expired         OrderStatus = "expired"
+	Rejected        OrderStatus = "rejected"
+)
+
+// Done reports whether the order can no longer fill.
+func (s OrderStatus) Done() bool {
+	return s == Filled || s == Cancelled || s == Expired || s == Rejected
+}
+
+type Order struct {
+	ID         string
+	Symbol     string // lowercase full finnhub symbol, same as trades
+	Side       Side
+	Type       OrderType
+	TIF        TimeInForce
+	Qty        float64
+	LimitPrice float64
+	StopPrice  float64
+
+	Status    OrderStatus
+	Reason    string // why it was rejected/cancelled
+	Triggered bool   // stop has been hit
+	FilledQty float64
+	AvgPrice  float64
+	Created   time.Time
+	Updated   time.Time
+	Expires   time.Time // zero for no expiry
+}
+
+func (o Order) Remaining() float64 {
+	return o.Qty - o.FilledQty
+}
+
+func (o Order) validate() error {
+	if o.Symbol == "" {
+		return fmt.Errorf("order has no symbol")
+	}
+	if o.Side != Buy && o.Side != Sell {
+		return fmt.Errorf("unknown side %q", o.Side)
+	}
+	if o.Qty <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	switch o.Type {
+	case Market:
+	case Limit:
+		if o.LimitPrice <= 0 {
+			return fmt.Errorf("limit order needs a limit price")
+		}
+	case Stop:
+		if o.StopPrice <= 0 {
+			return fmt.Errorf("stop order needs a stop price")
+		}
+	case StopLimit:
+		if o.StopPrice <= 0 || o.LimitPrice <= 0 {
+			return fmt.Errorf("stop-limit order needs a stop and a limit price")
+		}
+	default:
+		return fmt.Errorf("unknown order type %q", o.Type)
+	}
+	switch o.TIF {
+	case GTC, Day, IOC, FOK:
+	default:
+		return fmt.Errorf("unknown time in force %q", o.TIF)
+	}
+	return nil
+}
+
+func (o Order) String() string {
+	var price string
+	switch o.Type {
+	case Limit:
+		price = fmt.Sprintf(" @ %.2f", o.LimitPrice)
+	case Stop:
+		price = fmt.Sprintf(" stop %.2f", o.StopPrice)
+	case StopLimit:
+		price = fmt.Sprintf(" stop %.2f limit %.2f", o.StopPrice, o.LimitPrice)
+	}
+	return fmt.Sprintf("%s %g %s %s%s %s", o.Side, o.Qty, strings.ToUpper(o.Symbol), o.Type, price, o.TIF)
+}
+
+// Fill is one execution against an order.
+type Fill struct {
+	OrderID string
+	Symbol  string
+	Side    Side
+	Qty     float64
+	Price   float64
+	Fee     float64
+	Unix    int64 // unix millis of the trade it filled against
+}
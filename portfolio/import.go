@@ -0,0 +1,217 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns lists the header names each field can go by. Broker exports all
+// name things differently, matching ignores case, spaces and underscores.
+type Columns struct {
+	Symbol     []string
+	Qty        []string
+	Price      []string // per unit
+	CostBasis  []string // total for the row, used when there's no price
+	Date       []string
+	Side       []string
+	Fee        []string
+	AssetClass []string
+}
+
+var DefaultColumns = Columns{
+	Symbol:     []string{"symbol", "ticker", "instrument", "security", "asset"},
+	Qty:        []string{"quantity", "qty", "shares", "units", "amount"},
+	Price:      []string{"price", "cost per share", "unit cost", "avg cost", "average cost", "purchase price", "cost/share"},
+	CostBasis:  []string{"cost basis", "total cost", "cost", "book value"},
+	Date:       []string{"date", "acquired", "date acquired", "open date", "trade date", "purchase date"},
+	Side:       []string{"side", "action", "transaction type", "type"},
+	Fee:        []string{"fee", "fees", "commission"},
+	AssetClass: []string{"asset class", "class", "asset type", "security type"},
+}
+
+type ImportOptions struct {
+	Columns Columns
+	// Symbol maps a symbol from the file to a full finnhub symbol, nil keeps it
+	Symbol func(string) string
+}
+
+var dateLayouts = []string{
+	"2006-01-02", time.RFC3339, "2006-01-02 15:04:05", "01/02/2006", "1/2/2006",
+	"2006/01/02", "02.01.2006", "Jan 2, 2006", "2 Jan 2006",
+}
+
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	h = strings.ReplaceAll(h, "_", " ")
+	return strings.Join(strings.Fields(h), " ")
+}
+
+// parseNumber copes with "$1,234.50" and accounting style "(12.5)"
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	s = strings.Trim(s, "()")
+	s = strings.NewReplacer("$", "", ",", "", "£", "", "€", "", " ", "").Replace(s)
+	if s == "" || s == "-" || s == "--" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if negative {
+		v = -v
+	}
+	return v, err
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", s)
+}
+
+func parseSide(s string) (Side, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "buy", "bought", "b", "long", "purchase", "open":
+		return Buy, true
+	case "sell", "sold", "s", "close", "sale":
+		return Sell, true
+	}
+	return "", false
+}
+
+// Import reads a holdings or transactions CSV. Rows without a side are lots
+// that were bought, a negative quantity or a sell side is a sale. Rows with
+// other actions (dividends, transfers) are skipped.
+func Import(r io.Reader, opts ImportOptions) ([]Transaction, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	col := make(map[string]int)
+	for i, h := range header {
+		col[normalizeHeader(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+	find := func(names []string) int {
+		for _, name := range names {
+			if i, ok := col[normalizeHeader(name)]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+
+	cols := opts.Columns
+	if cols.Symbol == nil {
+		cols = DefaultColumns
+	}
+	symbolCol, qtyCol := find(cols.Symbol), find(cols.Qty)
+	priceCol, costCol := find(cols.Price), find(cols.CostBasis)
+	dateCol, sideCol := find(cols.Date), find(cols.Side)
+	feeCol, classCol := find(cols.Fee), find(cols.AssetClass)
+	if symbolCol < 0 || qtyCol < 0 {
+		return nil, fmt.Errorf("need symbol and quantity columns, header was %v", header)
+	}
+	if priceCol < 0 && costCol < 0 {
+		return nil, fmt.Errorf("need a price or cost basis column, header was %v", header)
+	}
+
+	field := func(rec []string, i int) string {
+		if i < 0 || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var txs []Transaction
+	line := 1
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		symbol := strings.ToUpper(field(rec, symbolCol))
+		if symbol == "" {
+			continue // totals and blank lines
+		}
+		side, ok := parseSide(field(rec, sideCol))
+		if !ok {
+			continue
+		}
+		qty, err := parseNumber(field(rec, qtyCol))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: quantity: %w", line, err)
+		}
+		if qty < 0 {
+			side, qty = Sell, -qty
+		}
+		if qty == 0 {
+			continue
+		}
+
+		var price float64
+		if priceCol >= 0 && field(rec, priceCol) != "" {
+			if price, err = parseNumber(field(rec, priceCol)); err != nil {
+				return nil, fmt.Errorf("line %d: price: %w", line, err)
+			}
+		} else {
+			cost, err := parseNumber(field(rec, costCol))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: cost basis: %w", line, err)
+			}
+			price = cost / qty
+		}
+		fee, err := parseNumber(field(rec, feeCol))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: fee: %w", line, err)
+		}
+		date, err := parseDate(field(rec, dateCol))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if opts.Symbol != nil {
+			symbol = opts.Symbol(symbol)
+		}
+		class := strings.ToLower(field(rec, classCol))
+		if class == "" {
+			class = AssetClass(symbol)
+		}
+
+		txs = append(txs, Transaction{
+			Date:       date,
+			Symbol:     symbol,
+			Side:       side,
+			Qty:        qty,
+			Price:      abs(price),
+			Fee:        abs(fee),
+			AssetClass: class,
+		})
+	}
+	return txs, nil
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,302 @@
+// Package portfolio tracks real holdings imported from a broker export and
+// values them against live quotes. Holdings are kept as the transactions
+// that built them, so tax lots can be replayed under FIFO or LIFO.
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/calendar"
+	"github.com/Scrimzay/stockspider/event"
+)
+
+type Method string
+
+const (
+	FIFO Method = "fifo"
+	LIFO Method = "lifo"
+)
+
+type Side string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// Transaction is one buy or sell. An import with only current holdings
+// becomes one buy per lot.
+type Transaction struct {
+	Date       time.Time
+	Symbol     string // full finnhub symbol
+	Side       Side
+	Qty        float64
+	Price      float64 // per unit, before fees
+	Fee        float64
+	AssetClass string
+}
+
+// Lot is an open tax lot.
+type Lot struct {
+	Symbol   string
+	Acquired time.Time
+	Qty      float64
+	Price    float64 // cost per unit, buy fees included
+}
+
+// Gain is a realized gain from a sell closing (part of) a lot.
+type Gain struct {
+	Symbol   string
+	Acquired time.Time
+	Sold     time.Time
+	Qty      float64
+	Cost     float64
+	Proceeds float64 // net of the sell fee
+	LongTerm bool    // held more than a year
+}
+
+func (g Gain) PnL() float64 {
+	return g.Proceeds - g.Cost
+}
+
+type Holding struct {
+	Symbol     string
+	AssetClass string
+	Lots       []Lot
+}
+
+func (h Holding) Qty() float64 {
+	qty := 0.0
+	for _, lot := range h.Lots {
+		qty += lot.Qty
+	}
+	return qty
+}
+
+func (h Holding) Cost() float64 {
+	cost := 0.0
+	for _, lot := range h.Lots {
+		cost += lot.Qty * lot.Price
+	}
+	return cost
+}
+
+type Portfolio struct {
+	Name         string
+	Method       Method
+	Transactions []Transaction
+
+	// derived by Rebuild
+	holdings map[string]*Holding
+	gains    []Gain
+}
+
+func New(name string, method Method) *Portfolio {
+	p := &Portfolio{Name: name, Method: method}
+	p.Rebuild()
+	return p
+}
+
+// Add appends transactions and replays the lots.
+func (p *Portfolio) Add(txs ...Transaction) error {
+	p.Transactions = append(p.Transactions, txs...)
+	return p.Rebuild()
+}
+
+// SetMethod switches between FIFO and LIFO and replays the lots.
+func (p *Portfolio) SetMethod(m Method) error {
+	p.Method = m
+	return p.Rebuild()
+}
+
+// Rebuild replays every transaction in date order into lots and realized
+// gains. Selling more than is held is an error, the rest of the replay
+// still happens so the portfolio stays usable.
+func (p *Portfolio) Rebuild() error {
+	p.holdings = make(map[string]*Holding)
+	p.gains = nil
+
+	txs := make([]Transaction, len(p.Transactions))
+	copy(txs, p.Transactions)
+	// buys before sells on the same day so a same-day round trip works
+	sort.SliceStable(txs, func(i, j int) bool {
+		if !txs[i].Date.Equal(txs[j].Date) {
+			return txs[i].Date.Before(txs[j].Date)
+		}
+		return txs[i].Side == Buy && txs[j].Side == Sell
+	})
+
+	var errs []string
+	for _, tx := range txs {
+		h, ok := p.holdings[tx.Symbol]
+		if !ok {
+			h = &Holding{Symbol: tx.Symbol, AssetClass: tx.AssetClass}
+			p.holdings[tx.Symbol] = h
+		}
+		if h.AssetClass == "" {
+			h.AssetClass = tx.AssetClass
+		}
+
+		switch tx.Side {
+		case Buy:
+			h.Lots = append(h.Lots, Lot{
+				Symbol:   tx.Symbol,
+				Acquired: tx.Date,
+				Qty:      tx.Qty,
+				Price:    tx.Price + tx.Fee/tx.Qty,
+			})
+		case Sell:
+			if err := p.sell(h, tx); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	for symbol, h := range p.holdings {
+		if len(h.Lots) == 0 {
+			delete(p.holdings, symbol)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("portfolio %s: %s", p.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (p *Portfolio) sell(h *Holding, tx Transaction) error {
+	remaining := tx.Qty
+	feePerUnit := tx.Fee / tx.Qty
+
+	for remaining > 1e-12 && len(h.Lots) > 0 {
+		i := 0
+		if p.Method == LIFO {
+			i = len(h.Lots) - 1
+		}
+		lot := &h.Lots[i]
+		qty := math.Min(remaining, lot.Qty)
+
+		p.gains = append(p.gains, Gain{
+			Symbol:   tx.Symbol,
+			Acquired: lot.Acquired,
+			Sold:     tx.Date,
+			Qty:      qty,
+			Cost:     qty * lot.Price,
+			Proceeds: qty * (tx.Price - feePerUnit),
+			LongTerm: tx.Date.After(lot.Acquired.AddDate(1, 0, 0)),
+		})
+
+		lot.Qty -= qty
+		remaining -= qty
+		if lot.Qty <= 1e-12 {
+			h.Lots = append(h.Lots[:i], h.Lots[i+1:]...)
+		}
+	}
+
+	if remaining > 1e-9 {
+		return fmt.Errorf("sold %g %s more than held on %s", remaining, tx.Symbol, tx.Date.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// Holdings returns the open holdings sorted by symbol.
+func (p *Portfolio) Holdings() []Holding {
+	holdings := make([]Holding, 0, len(p.holdings))
+	for _, h := range p.holdings {
+		c := *h
+		c.Lots = append([]Lot(nil), h.Lots...)
+		holdings = append(holdings, c)
+	}
+	sort.Slice(holdings, func(i, j int) bool { return holdings[i].Symbol < holdings[j].Symbol })
+	return holdings
+}
+
+// Gains returns the realized gains in the order they were realized.
+func (p *Portfolio) Gains() []Gain {
+	return append([]Gain(nil), p.gains...)
+}
+
+// Symbols are the symbols currently held.
+func (p *Portfolio) Symbols() []string {
+	var symbols []string
+	for _, h := range p.Holdings() {
+		symbols = append(symbols, h.Symbol)
+	}
+	return symbols
+}
+
+// AssetClass guesses the asset class of a symbol when the import didn't say.
+func AssetClass(symbol string) string {
+	switch {
+	case calendar.For(event.Pair{Symbol: symbol}) == calendar.Crypto:
+		return "crypto"
+	case strings.HasPrefix(symbol, "^"):
+		return "index"
+	default:
+		return "equity"
+	}
+}
+
+type PositionValue struct {
+	Holding
+	Qty         float64
+	Cost        float64
+	Price       float64 // zero when there's no quote yet
+	MarketValue float64
+	DayPnL      float64
+	TotalPnL    float64
+	Weight      float64 // of the portfolio's market value
+	Quoted      bool
+}
+
+type Valuation struct {
+	MarketValue float64
+	Cost        float64
+	DayPnL      float64
+	TotalPnL    float64
+	Realized    float64
+	Positions   []PositionValue
+	Allocation  map[string]float64 // asset class -> weight
+}
+
+// Value prices the holdings with quotes keyed by full finnhub symbol.
+// Holdings without a quote are carried at cost so weights stay sane.
+func (p *Portfolio) Value(quotes map[string]event.Quote) Valuation {
+	v := Valuation{Allocation: make(map[string]float64)}
+
+	for _, h := range p.Holdings() {
+		pv := PositionValue{Holding: h, Qty: h.Qty(), Cost: h.Cost()}
+		if q, ok := quotes[h.Symbol]; ok && q.Current > 0 {
+			pv.Quoted = true
+			pv.Price = float64(q.Current)
+			pv.MarketValue = pv.Qty * pv.Price
+			pv.DayPnL = pv.Qty * float64(q.Current-q.PrevClose)
+			pv.TotalPnL = pv.MarketValue - pv.Cost
+		} else {
+			pv.MarketValue = pv.Cost
+		}
+		v.MarketValue += pv.MarketValue
+		v.Cost += pv.Cost
+		v.DayPnL += pv.DayPnL
+		v.TotalPnL += pv.TotalPnL
+		v.Positions = append(v.Positions, pv)
+	}
+
+	for i := range v.Positions {
+		if v.MarketValue > 0 {
+			v.Positions[i].Weight = v.Positions[i].MarketValue / v.MarketValue
+		}
+		class := v.Positions[i].AssetClass
+		if class == "" {
+			class = AssetClass(v.Positions[i].Symbol)
+		}
+		v.Allocation[class] += v.Positions[i].Weight
+	}
+	for _, g := range p.gains {
+		v.Realized += g.PnL()
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// the on-disk form, lots are always rebuilt from the transactions
+type saved struct {
+	Name         string
+	Method       Method
+	Transactions []Transaction
+}
+
+func fileName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_").Replace(name)
+	return name + ".json"
+}
+
+// Save writes p to <dir>/<name>.json.
+func Save(dir string, p *Portfolio) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(saved{Name: p.Name, Method: p.Method, Transactions: p.Transactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, fileName(p.Name))
+	if err := os.WriteFile(path+".tmp", b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// LoadAll loads every portfolio in dir, sorted by name. A missing dir is
+// no portfolios, not an error.
+func LoadAll(dir string) ([]*Portfolio, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var portfolios []*Portfolio
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var s saved
+		if err := json.Unmarshal(b, &s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		if s.Method == "" {
+			s.Method = FIFO
+		}
+		p := &Portfolio{Name: s.Name, Method: s.Method, Transactions: s.Transactions}
+		if err := p.Rebuild(); err != nil {
+			errs = append(errs, err)
+		}
+		portfolios = append(portfolios, p)
+	}
+	sort.Slice(portfolios, func(i, j int) bool { return portfolios[i].Name < portfolios[j].Name })
+	return portfolios, errors.Join(errs...)
+}
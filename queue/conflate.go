@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Scrimzay/stockspider/metrics"
+)
+
+// Conflater keeps the newest depth updates per key between drains.
+// Anything older is overwritten and counted in
+// stockspider_conflated_total, the display only ever shows the latest
+// few anyway. depth 1 is plain last-value conflation.
+// It is safe for concurrent use.
+type Conflater[K comparable, V any] struct {
+	name  string
+	depth int
+
+	mu      sync.Mutex
+	pending map[K][]V
+
+	conflated atomic.Uint64
+}
+
+func NewConflater[K comparable, V any](name string, depth int) *Conflater[K, V] {
+	if depth < 1 {
+		depth = 1
+	}
+	return &Conflater[K, V]{
+		name:    name,
+		depth:   depth,
+		pending: make(map[K][]V),
+	}
+}
+
+func (c *Conflater[K, V]) Put(key K, v V) {
+	c.mu.Lock()
+	updates := append(c.pending[key], v)
+	over := len(updates) - c.depth
+	if over > 0 {
+		// shift instead of reslicing so the backing array doesn't creep
+		copy(updates, updates[over:])
+		updates = updates[:c.depth]
+	}
+	c.pending[key] = updates
+	c.mu.Unlock()
+
+	if over > 0 {
+		c.conflated.Add(uint64(over))
+		metrics.Conflated(c.name, over)
+	}
+}
+
+// Drain hands over everything pending, oldest first per key, and starts
+// over empty
+func (c *Conflater[K, V]) Drain() map[K][]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pending) == 0 {
+		return nil
+	}
+	drained := c.pending
+	c.pending = make(map[K][]V, len(drained))
+	return drained
+}
+
+// Conflated is how many updates were overwritten before anyone drained them
+func (c *Conflater[K, V]) Conflated() uint64 {
+	return c.conflated.Load()
+}
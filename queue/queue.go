@@ -0,0 +1,142 @@
+// Package queue has the bounded buffers the trade path runs through, so a
+// burst of trades costs dropped or conflated updates instead of an
+// ever-growing backlog or a stalled websocket reader.
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/Scrimzay/stockspider/metrics"
+)
+
+// Policy is what Push does when the queue is full
+type Policy int
+
+const (
+	// DropOldest makes room by throwing away the oldest item, for feeds
+	// where fresh beats complete
+	DropOldest Policy = iota
+	// DropNewest refuses the item being pushed
+	DropNewest
+)
+
+// Queue is a bounded FIFO that never blocks the producer. Overflow is
+// handled by its Policy and counted in stockspider_queue_dropped_total.
+// It is safe for concurrent use.
+type Queue[T any] struct {
+	name   string
+	policy Policy
+
+	mu     sync.Mutex
+	items  []T // ring buffer
+	head   int
+	n      int
+	closed bool
+	ready  chan struct{} // poked when items arrive or the queue closes
+
+	dropped atomic.Uint64
+}
+
+// New makes a queue holding up to capacity items, name labels its metrics
+// and has to be unique
+func New[T any](name string, capacity int, policy Policy) *Queue[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	q := &Queue[T]{
+		name:   name,
+		policy: policy,
+		items:  make([]T, capacity),
+		ready:  make(chan struct{}, 1),
+	}
+	metrics.WatchQueue(name, q.Len, q.Cap)
+	return q
+}
+
+// Push adds v without blocking, false when the queue was full and
+// something got dropped or it was closed
+func (q *Queue[T]) Push(v T) bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	ok := true
+	switch {
+	case q.n < len(q.items):
+		q.items[(q.head+q.n)%len(q.items)] = v
+		q.n++
+	case q.policy == DropOldest:
+		q.items[q.head] = v
+		q.head = (q.head + 1) % len(q.items)
+		ok = false
+	default:
+		ok = false
+	}
+	q.mu.Unlock()
+
+	if !ok {
+		q.dropped.Add(1)
+		metrics.Dropped(q.name)
+	}
+	q.poke()
+	return ok
+}
+
+func (q *Queue[T]) poke() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// PopBatch waits for at least one item and appends up to max of them to
+// dst, oldest first. ok is false once the queue is closed and drained
+func (q *Queue[T]) PopBatch(dst []T, max int) ([]T, bool) {
+	for {
+		q.mu.Lock()
+		if q.n > 0 {
+			for i := 0; i < max && q.n > 0; i++ {
+				var zero T
+				dst = append(dst, q.items[q.head])
+				q.items[q.head] = zero
+				q.head = (q.head + 1) % len(q.items)
+				q.n--
+			}
+			q.mu.Unlock()
+			return dst, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+		if closed {
+			// pass the wake-up on to any other waiting PopBatch
+			q.poke()
+			return dst, false
+		}
+		<-q.ready
+	}
+}
+
+// Close stops Push, PopBatch still hands out what's left
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.poke()
+}
+
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.n
+}
+
+func (q *Queue[T]) Cap() int {
+	return len(q.items)
+}
+
+// Dropped is how many items overflow cost so far
+func (q *Queue[T]) Dropped() uint64 {
+	return q.dropped.Load()
+}
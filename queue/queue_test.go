@@ -0,0 +1,202 @@
+package queue
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+func TestOverflow(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []int
+	}{
+		{"drop oldest", DropOldest, []int{3, 4, 5}},
+		{"drop newest", DropNewest, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := New[int]("test-"+tt.name, 3, tt.policy)
+			for i := 1; i <= 5; i++ {
+				if got, want := q.Push(i), i <= 3; got != want {
+					t.Fatalf("push %d returned %v, want %v", i, got, want)
+				}
+			}
+			if q.Len() != 3 || q.Dropped() != 2 {
+				t.Fatalf("len %d dropped %d, want 3 and 2", q.Len(), q.Dropped())
+			}
+			got, ok := q.PopBatch(nil, 10)
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("popped %v %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopBatchMax(t *testing.T) {
+	q := New[int]("test-max", 8, DropNewest)
+	for i := 1; i <= 5; i++ {
+		q.Push(i)
+	}
+	got, _ := q.PopBatch(nil, 2)
+	got, _ = q.PopBatch(got, 2)
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("popped %v, want %v", got, want)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("len %d, want 1", q.Len())
+	}
+}
+
+func TestClose(t *testing.T) {
+	q := New[int]("test-close", 4, DropNewest)
+	q.Push(1)
+	q.Push(2)
+	q.Close()
+	if q.Push(3) {
+		t.Fatal("push after close went through")
+	}
+	if q.Dropped() != 0 {
+		t.Fatalf("push after close counted as %d dropped", q.Dropped())
+	}
+	// what was in it still comes out, then it reports closed
+	if got, ok := q.PopBatch(nil, 10); !ok || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("popped %v %v, want [1 2] true", got, ok)
+	}
+	if got, ok := q.PopBatch(nil, 10); ok || len(got) != 0 {
+		t.Fatalf("popped %v %v from a closed empty queue", got, ok)
+	}
+}
+
+func TestCloseWakesWaiters(t *testing.T) {
+	q := New[int]("test-close-wait", 4, DropNewest)
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := q.PopBatch(nil, 1); ok {
+				t.Error("PopBatch on an empty closed queue returned ok")
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close didn't wake every waiting PopBatch")
+	}
+}
+
+func TestConflater(t *testing.T) {
+	c := NewConflater[string, int]("test-conflate", 2)
+	for i := 1; i <= 4; i++ {
+		c.Put("a", i)
+	}
+	c.Put("b", 1)
+
+	want := map[string][]int{"a": {3, 4}, "b": {1}}
+	if got := c.Drain(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("drained %v, want %v", got, want)
+	}
+	if c.Conflated() != 2 {
+		t.Fatalf("conflated %d, want 2", c.Conflated())
+	}
+	if got := c.Drain(); got != nil {
+		t.Fatalf("second drain %v, want nothing", got)
+	}
+
+	// it starts over after a drain
+	c.Put("a", 5)
+	if got := c.Drain(); !reflect.DeepEqual(got, map[string][]int{"a": {5}}) {
+		t.Fatalf("drained %v after starting over", got)
+	}
+}
+
+func TestConflaterLastValue(t *testing.T) {
+	c := NewConflater[string, int]("test-last-value", 0)
+	c.Put("a", 1)
+	c.Put("a", 2)
+	if got := c.Drain(); !reflect.DeepEqual(got, map[string][]int{"a": {2}}) {
+		t.Fatalf("drained %v, want only the last value", got)
+	}
+	if c.Conflated() != 1 {
+		t.Fatalf("conflated %d, want 1", c.Conflated())
+	}
+}
+
+// BenchmarkTradePath is the app's trade loop without the bus: a reader
+// pushing into the trade queue, the loop popping batches into the tape
+// conflater and a render loop draining it
+func BenchmarkTradePath(b *testing.B) {
+	const symbols = 20
+	pairs := make([]event.Pair, symbols)
+	for i := range pairs {
+		pairs[i] = event.Pair{Exchange: "finnhub", Symbol: fmt.Sprintf("bench:sym%d", i)}
+	}
+	trades := New[event.StockTrade]("bench-trades", 1<<16, DropOldest)
+	tape := NewConflater[string, event.StockTrade]("bench-tape", 100)
+
+	var processed int
+	loopDone := make(chan struct{})
+	go func() {
+		defer close(loopDone)
+		batch := make([]event.StockTrade, 0, 512)
+		for {
+			var ok bool
+			batch, ok = trades.PopBatch(batch[:0], 512)
+			if !ok {
+				return
+			}
+			for _, trade := range batch {
+				tape.Put(trade.Pair.Symbol, trade)
+			}
+			processed += len(batch)
+		}
+	}()
+	stopRender := make(chan struct{})
+	renderDone := make(chan struct{})
+	go func() {
+		defer close(renderDone)
+		frame := time.NewTicker(time.Second / 60)
+		defer frame.Stop()
+		for {
+			select {
+			case <-frame.C:
+				tape.Drain()
+			case <-stopRender:
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		trades.Push(event.StockTrade{
+			Pair:  pairs[i%symbols],
+			Price: 100 + float64(i%100)/100,
+			Qty:   1,
+			Unix:  int64(i),
+		})
+	}
+	trades.Close()
+	<-loopDone
+	elapsed := time.Since(start)
+	b.StopTimer()
+	close(stopRender)
+	<-renderDone
+
+	b.ReportMetric(float64(processed)/elapsed.Seconds(), "trades/s")
+	b.ReportMetric(float64(trades.Dropped())/float64(b.N), "drops/op")
+}
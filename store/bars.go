@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// barRecord is the on-disk form of an imported bar, the pair and
+// timeframe come from the path
+type barRecord struct {
+	Unix   int64   `json:"t"`
+	Open   float64 `json:"o"`
+	High   float64 `json:"h"`
+	Low    float64 `json:"l"`
+	Close  float64 `json:"c"`
+	Volume float64 `json:"v"`
+}
+
+// imported bars live next to the symbol's trades, one file per year:
+// <dir>/<exchange>/<symbol>/bars/<timeframe>/<2006>.jsonl
+func (s *TickStore) barDir(pair event.Pair, timeframe string) string {
+	return filepath.Join(s.symbolDir(pair), "bars", pathPart(timeframe))
+}
+
+// AddTrades stores the trades that aren't stored yet, a trade with the
+// same time, price, size and side as a stored one is a duplicate. it
+// returns how many were new
+func (s *TickStore) AddTrades(trades []event.StockTrade) (int, error) {
+	type key struct {
+		unix       int64
+		price, qty float64
+		isBuy      bool
+	}
+	byPair := make(map[event.Pair][]event.StockTrade)
+	for _, t := range trades {
+		byPair[t.Pair] = append(byPair[t.Pair], t)
+	}
+
+	added := 0
+	for pair, trades := range byPair {
+		sort.SliceStable(trades, func(i, j int) bool { return trades[i].Unix < trades[j].Unix })
+		first, last := trades[0].Unix, trades[len(trades)-1].Unix
+		stored, err := s.Trades(pair, time.UnixMilli(first), time.UnixMilli(last+1))
+		if err != nil {
+			return added, err
+		}
+		seen := make(map[key]bool, len(stored))
+		for _, t := range stored {
+			seen[key{t.Unix, t.Price, t.Qty, t.IsBuy}] = true
+		}
+		for _, t := range trades {
+			k := key{t.Unix, t.Price, t.Qty, t.IsBuy}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			if err := s.Append(t); err != nil {
+				return added, err
+			}
+			added++
+		}
+	}
+	return added, s.Flush()
+}
+
+// AddBars stores bars of pair at timeframe, bars of a bucket that's
+// already stored are duplicates and skipped. it returns how many were new
+func (s *TickStore) AddBars(pair event.Pair, timeframe string, bars []event.Candle) (int, error) {
+	s.barsMu.Lock()
+	defer s.barsMu.Unlock()
+
+	dir := s.barDir(pair, timeframe)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+
+	byYear := make(map[int][]event.Candle)
+	for _, bar := range bars {
+		year := time.Unix(bar.Unix, 0).UTC().Year()
+		byYear[year] = append(byYear[year], bar)
+	}
+
+	added := 0
+	for year, bars := range byYear {
+		path := filepath.Join(dir, strconv.Itoa(year)+".jsonl")
+		stored, err := readBars(path)
+		if err != nil {
+			return added, err
+		}
+		byBucket := make(map[int64]barRecord, len(stored)+len(bars))
+		for _, rec := range stored {
+			byBucket[rec.Unix] = rec
+		}
+		n := 0
+		for _, bar := range bars {
+			if _, ok := byBucket[bar.Unix]; ok {
+				continue
+			}
+			byBucket[bar.Unix] = barRecord{Unix: bar.Unix, Open: bar.Open, High: bar.High, Low: bar.Low, Close: bar.Close, Volume: bar.Volume}
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		if err := writeBars(path, byBucket); err != nil {
+			return added, err
+		}
+		added += n
+	}
+	return added, nil
+}
+
+// Bars returns the imported bars of pair at timeframe in [from, to),
+// oldest first
+func (s *TickStore) Bars(pair event.Pair, timeframe string, from, to time.Time) ([]event.Candle, error) {
+	s.barsMu.Lock()
+	defer s.barsMu.Unlock()
+
+	dir := s.barDir(pair, timeframe)
+	var bars []event.Candle
+	for year := from.UTC().Year(); year <= to.UTC().Year(); year++ {
+		stored, err := readBars(filepath.Join(dir, strconv.Itoa(year)+".jsonl"))
+		if err != nil {
+			return nil, err
+		}
+		for _, rec := range stored {
+			if rec.Unix < from.Unix() || rec.Unix >= to.Unix() {
+				continue
+			}
+			bars = append(bars, event.Candle{
+				Pair:      pair,
+				Timeframe: timeframe,
+				Unix:      rec.Unix,
+				Open:      rec.Open,
+				High:      rec.High,
+				Low:       rec.Low,
+				Close:     rec.Close,
+				Volume:    rec.Volume,
+			})
+		}
+	}
+	return bars, nil
+}
+
+// readBars reads a year of bars, sorted. a missing file is no bars
+func readBars(path string) ([]barRecord, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var bars []barRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec barRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		bars = append(bars, rec)
+	}
+	return bars, scanner.Err()
+}
+
+// writeBars replaces a year of bars, the old file stays until the new one
+// is complete
+func writeBars(path string, byBucket map[int64]barRecord) error {
+	bars := make([]barRecord, 0, len(byBucket))
+	for _, rec := range byBucket {
+		bars = append(bars, rec)
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Unix < bars[j].Unix })
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, rec := range bars {
+		if err := enc.Encode(rec); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
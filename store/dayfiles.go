@@ -0,0 +1,163 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+const dayLayout = "2006-01-02"
+
+// how often buffered records are written through to disk
+const flushInterval = time.Second
+
+// dayFiles keeps one open JSON lines file per symbol, for the UTC day its
+// last record fell on. the tick and quote stores are both laid out as
+// <dir>/<exchange>/<symbol>/<2006-01-02>.jsonl
+type dayFiles struct {
+	dir string
+
+	mu    sync.Mutex
+	files map[string]*dayFile // keyed by symbol directory
+}
+
+type dayFile struct {
+	day       string
+	f         *os.File
+	w         *bufio.Writer
+	lastFlush time.Time
+}
+
+func newDayFiles(dir string) (*dayFiles, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &dayFiles{
+		dir:   dir,
+		files: make(map[string]*dayFile),
+	}, nil
+}
+
+// pathPart makes a symbol safe to use as a directory name, finnhub symbols
+// have colons in them which windows doesn't like
+func pathPart(s string) string {
+	s = strings.ToLower(s)
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_", "^", "_").Replace(s)
+}
+
+func (s *dayFiles) symbolDir(pair event.Pair) string {
+	return filepath.Join(s.dir, pathPart(pair.Exchange), pathPart(pair.Symbol))
+}
+
+// append writes rec as a line of pair's file for day
+func (s *dayFiles) append(pair event.Pair, day string, rec any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := s.symbolDir(pair)
+	df, ok := s.files[dir]
+	if ok && df.day != day {
+		if err := df.close(); err != nil {
+			return err
+		}
+		ok = false
+	}
+	if !ok {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(filepath.Join(dir, day+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		df = &dayFile{day: day, f: f, w: bufio.NewWriter(f), lastFlush: time.Now()}
+		s.files[dir] = df
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := df.w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if time.Since(df.lastFlush) > flushInterval {
+		df.lastFlush = time.Now()
+		return df.w.Flush()
+	}
+	return nil
+}
+
+func (df *dayFile) close() error {
+	if err := df.w.Flush(); err != nil {
+		df.f.Close()
+		return err
+	}
+	return df.f.Close()
+}
+
+// Flush writes every buffered record to disk.
+func (s *dayFiles) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush()
+}
+
+func (s *dayFiles) flush() error {
+	var errs []error
+	for _, df := range s.files {
+		df.lastFlush = time.Now()
+		errs = append(errs, df.w.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+func (s *dayFiles) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for dir, df := range s.files {
+		errs = append(errs, df.close())
+		delete(s.files, dir)
+	}
+	return errors.Join(errs...)
+}
+
+// days flushes and lists pair's files that can hold records in [from, to),
+// in day order
+func (s *dayFiles) days(pair event.Pair, from, to time.Time) ([]string, error) {
+	if err := s.Flush(); err != nil {
+		return nil, err
+	}
+
+	dir := s.symbolDir(pair)
+	firstDay := from.UTC().Format(dayLayout)
+	lastDay := to.UTC().Format(dayLayout)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		day := strings.TrimSuffix(entry.Name(), ".jsonl")
+		if entry.IsDir() || day == entry.Name() || day < firstDay || day > lastDay {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths, nil
+}
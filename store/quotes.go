@@ -0,0 +1,117 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// QuoteStore records quotes the way TickStore records trades, one JSON
+// lines file per symbol per UTC day. quotes are polled, so one that
+// didn't change since the last of its symbol isn't written again
+type QuoteStore struct {
+	*dayFiles
+
+	lastMu sync.Mutex
+	last   map[event.Pair]quoteRecord
+}
+
+// quoteRecord is the on-disk form of a quote, the pair comes from the path
+type quoteRecord struct {
+	Current   float32 `json:"c"`
+	High      float32 `json:"h"`
+	Low       float32 `json:"l"`
+	Open      float32 `json:"o"`
+	PrevClose float32 `json:"pc"`
+	Unix      int64   `json:"t"`
+}
+
+func NewQuoteStore(dir string) (*QuoteStore, error) {
+	files, err := newDayFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("creating quote store dir: %w", err)
+	}
+	return &QuoteStore{dayFiles: files, last: make(map[event.Pair]quoteRecord)}, nil
+}
+
+func (s *QuoteStore) Append(quote event.Quote) error {
+	rec := quoteRecord{
+		Current:   quote.Current,
+		High:      quote.High,
+		Low:       quote.Low,
+		Open:      quote.Open,
+		PrevClose: quote.PrevClose,
+		Unix:      quote.Unix,
+	}
+
+	s.lastMu.Lock()
+	last, ok := s.last[quote.Pair]
+	unchanged := ok && last.Current == rec.Current && last.High == rec.High && last.Low == rec.Low &&
+		last.Open == rec.Open && last.PrevClose == rec.PrevClose
+	s.last[quote.Pair] = rec
+	s.lastMu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	day := time.Unix(quote.Unix, 0).UTC().Format(dayLayout)
+	return s.append(quote.Pair, day, rec)
+}
+
+// Quotes returns the stored quotes of pair in [from, to), oldest first.
+func (s *QuoteStore) Quotes(pair event.Pair, from, to time.Time) ([]event.Quote, error) {
+	paths, err := s.days(pair, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotes []event.Quote
+	for _, path := range paths {
+		dayQuotes, err := readQuotes(path, pair, from, to)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, dayQuotes...)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool { return quotes[i].Unix < quotes[j].Unix })
+	return quotes, nil
+}
+
+func readQuotes(path string, pair event.Pair, from, to time.Time) ([]event.Quote, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var quotes []event.Quote
+	fromUnix, toUnix := from.Unix(), to.Unix()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec quoteRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// a torn last line from a crash shouldn't hide the rest of the day
+			continue
+		}
+		if rec.Unix < fromUnix || rec.Unix >= toUnix {
+			continue
+		}
+		quotes = append(quotes, event.Quote{
+			Pair:      pair,
+			Current:   rec.Current,
+			High:      rec.High,
+			Low:       rec.Low,
+			Open:      rec.Open,
+			PrevClose: rec.PrevClose,
+			Unix:      rec.Unix,
+		})
+	}
+	return quotes, scanner.Err()
+}
@@ -0,0 +1,95 @@
+// Package store is the local on-disk store of the market data stockspider
+// collects, so history survives restarts.
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Scrimzay/stockspider/event"
+)
+
+// TickStore records trades as JSON lines, one file per symbol per UTC day:
+// <dir>/<exchange>/<symbol>/<2006-01-02>.jsonl
+// bars imported from elsewhere are kept next to them, see AddBars
+type TickStore struct {
+	*dayFiles
+
+	barsMu sync.Mutex
+}
+
+// tickRecord is the on-disk form of a trade, the pair comes from the path
+type tickRecord struct {
+	Price float64 `json:"p"`
+	Qty   float64 `json:"v"`
+	IsBuy bool    `json:"b"`
+	Unix  int64   `json:"t"`
+}
+
+func NewTickStore(dir string) (*TickStore, error) {
+	files, err := newDayFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("creating tick store dir: %w", err)
+	}
+	return &TickStore{dayFiles: files}, nil
+}
+
+func (s *TickStore) Append(trade event.StockTrade) error {
+	day := time.UnixMilli(trade.Unix).UTC().Format(dayLayout)
+	return s.append(trade.Pair, day, tickRecord{Price: trade.Price, Qty: trade.Qty, IsBuy: trade.IsBuy, Unix: trade.Unix})
+}
+
+// Trades returns the stored trades of pair in [from, to), oldest first.
+func (s *TickStore) Trades(pair event.Pair, from, to time.Time) ([]event.StockTrade, error) {
+	paths, err := s.days(pair, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []event.StockTrade
+	for _, path := range paths {
+		dayTrades, err := readTicks(path, pair, from, to)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, dayTrades...)
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Unix < trades[j].Unix })
+	return trades, nil
+}
+
+func readTicks(path string, pair event.Pair, from, to time.Time) ([]event.StockTrade, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var trades []event.StockTrade
+	fromMs, toMs := from.UnixMilli(), to.UnixMilli()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec tickRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// a torn last line from a crash shouldn't hide the rest of the day
+			continue
+		}
+		if rec.Unix < fromMs || rec.Unix >= toMs {
+			continue
+		}
+		trades = append(trades, event.StockTrade{
+			Pair:  pair,
+			Price: rec.Price,
+			Qty:   rec.Qty,
+			IsBuy: rec.IsBuy,
+			Unix:  rec.Unix,
+		})
+	}
+	return trades, scanner.Err()
+}
@@ -0,0 +1,112 @@
+package strategy
+
+import (
+	"math"
+	"strings"
+
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/paper"
+)
+
+func init() {
+	Register("sma-cross", func() Strategy { return NewSMACross(10, 30, 0.25) })
+	Register("buy-hold", func() Strategy { return &BuyAndHold{Fraction: 0.95} })
+}
+
+// SMACross goes long when the fast moving average crosses above the slow
+// one and flattens when it crosses back under. It's an example more than
+// something to trade.
+type SMACross struct {
+	Fast, Slow int
+	Fraction   float64 // of cash put into each entry
+
+	closes map[string][]float64
+	above  map[string]bool
+}
+
+func NewSMACross(fast, slow int, fraction float64) *SMACross {
+	return &SMACross{
+		Fast:     fast,
+		Slow:     slow,
+		Fraction: fraction,
+		closes:   make(map[string][]float64),
+		above:    make(map[string]bool),
+	}
+}
+
+func (s *SMACross) Name() string { return "sma-cross" }
+
+func (s *SMACross) OnTrade(b Broker, trade event.StockTrade) {}
+
+func sma(values []float64, n int) float64 {
+	sum := 0.0
+	for _, v := range values[len(values)-n:] {
+		sum += v
+	}
+	return sum / float64(n)
+}
+
+func (s *SMACross) OnBar(b Broker, bar event.Candle) {
+	symbol := strings.ToLower(bar.Pair.Symbol)
+	closes := append(s.closes[symbol], bar.Close)
+	if len(closes) > s.Slow {
+		closes = closes[len(closes)-s.Slow:]
+	}
+	s.closes[symbol] = closes
+	if len(closes) < s.Slow {
+		return
+	}
+
+	above := sma(closes, s.Fast) > sma(closes, s.Slow)
+	wasAbove, seen := s.above[symbol]
+	s.above[symbol] = above
+	if !seen || above == wasAbove {
+		return
+	}
+
+	held := heldQty(b, symbol)
+	switch {
+	case above && held == 0:
+		qty := math.Floor(b.Snapshot().Cash*s.Fraction/bar.Close*1e4) / 1e4
+		if qty > 0 {
+			b.Submit(paper.Order{Symbol: symbol, Side: paper.Buy, Type: paper.Market, TIF: paper.GTC, Qty: qty})
+		}
+	case !above && held > 0:
+		b.Submit(paper.Order{Symbol: symbol, Side: paper.Sell, Type: paper.Market, TIF: paper.GTC, Qty: held})
+	}
+}
+
+// BuyAndHold buys on the first bar of each symbol and never sells, the
+// baseline everything else gets compared against.
+type BuyAndHold struct {
+	Fraction float64
+	bought   map[string]bool
+}
+
+func (s *BuyAndHold) Name() string { return "buy-hold" }
+
+func (s *BuyAndHold) OnTrade(b Broker, trade event.StockTrade) {}
+
+func (s *BuyAndHold) OnBar(b Broker, bar event.Candle) {
+	if s.bought == nil {
+		s.bought = make(map[string]bool)
+	}
+	symbol := strings.ToLower(bar.Pair.Symbol)
+	if s.bought[symbol] {
+		return
+	}
+	s.bought[symbol] = true
+	qty := math.Floor(b.Snapshot().Cash*s.Fraction/bar.Close*1e4) / 1e4
+	if qty > 0 {
+		b.Submit(paper.Order{Symbol: symbol, Side: paper.Buy, Type: paper.Market, TIF: paper.GTC, Qty: qty})
+	}
+}
+
+func heldQty(b Broker, symbol string) float64 {
+	for _, p := range b.Snapshot().Positions {
+		if p.Symbol == symbol {
+			return p.Qty
+		}
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+// Package strategy is the interface trading strategies are written against.
+// The same strategy runs in a backtest over stored data or live against the
+// paper trading engine.
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Scrimzay/stockspider/candle"
+	"github.com/Scrimzay/stockspider/event"
+	"github.com/Scrimzay/stockspider/paper"
+)
+
+// Broker is what a strategy trades through. *paper.Engine is one.
+type Broker interface {
+	Submit(o paper.Order) (paper.Order, error)
+	Cancel(id string) error
+	Snapshot() paper.Snapshot
+	Now() time.Time
+}
+
+// Strategy reacts to market data by placing orders through b. OnBar is
+// called once a bar has closed, after every trade in it went to OnTrade.
+type Strategy interface {
+	Name() string
+	OnTrade(b Broker, trade event.StockTrade)
+	OnBar(b Broker, bar event.Candle)
+}
+
+var registry = map[string]func() Strategy{}
+
+// Register makes a strategy available by name to the backtest command and
+// to live paper trading. Call it from an init func.
+func Register(name string, factory func() Strategy) {
+	name = strings.ToLower(name)
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("strategy %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// New returns a fresh instance of a registered strategy.
+func New(name string) (Strategy, error) {
+	factory, ok := registry[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, have %s", name, strings.Join(Names(), ", "))
+	}
+	return factory(), nil
+}
+
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BarCloser turns a trade stream into closed bars. A bar is closed by the
+// first trade of a later bucket, so the last bar stays open until Flush.
+type BarCloser struct {
+	tf     candle.Timeframe
+	series map[string]*candle.Series
+}
+
+func NewBarCloser(tf candle.Timeframe) *BarCloser {
+	return &BarCloser{tf: tf, series: make(map[string]*candle.Series)}
+}
+
+// Add folds trade in and returns the bar it closed, if any.
+func (bc *BarCloser) Add(trade event.StockTrade) (event.Candle, bool) {
+	symbol := strings.ToLower(trade.Pair.Symbol)
+	s, ok := bc.series[symbol]
+	if !ok {
+		s = candle.NewSeries(trade.Pair, bc.tf, 2)
+		bc.series[symbol] = s
+	}
+
+	before := s.Bars()
+	s.AddTrade(trade)
+	after := s.Bars()
+	if len(before) == 0 || after[len(after)-1].Unix <= before[len(before)-1].Unix {
+		return event.Candle{}, false
+	}
+	return before[len(before)-1], true
+}
+
+// Flush returns the open bars, oldest symbol first, and forgets them.
+func (bc *BarCloser) Flush() []event.Candle {
+	var bars []event.Candle
+	for _, s := range bc.series {
+		if b := s.Bars(); len(b) > 0 {
+			bars = append(bars, b[len(b)-1])
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Unix != bars[j].Unix {
+			return bars[i].Unix < bars[j].Unix
+		}
+		return bars[i].Pair.Symbol < bars[j].Pair.Symbol
+	})
+	bc.series = make(map[string]*candle.Series)
+	return bars
+}
+
+// Live runs a strategy against the live feed through a paper account.
+// Feed it every trade after the paper engine has seen it.
+type Live struct {
+	strategy Strategy
+	broker   Broker
+	bars     *BarCloser
+}
+
+func NewLive(s Strategy, b Broker, tf candle.Timeframe) *Live {
+	return &Live{
+		strategy: s,
+		broker:   b,
+		bars:     NewBarCloser(tf),
+	}
+}
+
+func (l *Live) Name() string {
+	return l.strategy.Name()
+}
+
+func (l *Live) OnTrade(trade event.StockTrade) {
+	if bar, ok := l.bars.Add(trade); ok {
+		l.strategy.OnBar(l.broker, bar)
+	}
+	l.strategy.OnTrade(l.broker, trade)
+}
@@ -0,0 +1,35 @@
+package supervise
+
+import "sync"
+
+// Snapshots keeps the last saved state of actors by key so a restarted
+// receiver, which hollywood builds from scratch, can pick up where the
+// crashed one left off. Save copies, a T holding slices has to be cloned
+// by the caller. It is safe for concurrent use.
+type Snapshots[T any] struct {
+	mu    sync.Mutex
+	saved map[string]T
+}
+
+func NewSnapshots[T any]() *Snapshots[T] {
+	return &Snapshots[T]{saved: make(map[string]T)}
+}
+
+func (s *Snapshots[T]) Save(key string, v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved[key] = v
+}
+
+func (s *Snapshots[T]) Load(key string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.saved[key]
+	return v, ok
+}
+
+func (s *Snapshots[T]) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.saved, key)
+}
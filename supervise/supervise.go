@@ -0,0 +1,59 @@
+// Package supervise decides what happens when an actor panics. hollywood
+// restarts a crashed actor with a fresh receiver a limited number of
+// times, Policy sets those limits, Snapshots carries state across the
+// restart and the Supervisor watches the engine: it counts restarts,
+// tells parents when a child was given up on and logs dead letters.
+package supervise
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// Policy is how an actor is restarted after a panic
+type Policy struct {
+	MaxRestarts int           // restarts before hollywood gives up on it
+	Delay       time.Duration // wait before each restart
+
+	// Respawns is how many times the parent spawns a given up child again
+	// with a fresh restart budget, 0 leaves it dead
+	Respawns     int
+	RespawnDelay time.Duration
+}
+
+// Opts are the spawn options that apply p
+func (p Policy) Opts() []actor.OptFunc {
+	return []actor.OptFunc{
+		actor.WithMaxRestarts(p.MaxRestarts),
+		actor.WithRestartDelay(p.Delay),
+	}
+}
+
+// ChildDied tells a parent that its child crashed past its restart limit
+// and is gone
+type ChildDied struct {
+	PID *actor.PID
+}
+
+// Parent is the PID of the actor that spawned pid with SpawnChild, nil
+// for actors spawned on the engine. child ids are the parent's id with
+// "/<name>/<id>" on the end
+func Parent(pid *actor.PID) *actor.PID {
+	parts := strings.Split(pid.GetID(), "/")
+	if len(parts) <= 2 {
+		return nil
+	}
+	return actor.NewPID(pid.GetAddress(), strings.Join(parts[:len(parts)-2], "/"))
+}
+
+// Kind is the name pid was spawned under without the ids, "stat" for
+// "finnhub/1/symbol/2/stat/3"
+func Kind(pid *actor.PID) string {
+	parts := strings.Split(pid.GetID(), "/")
+	if len(parts) < 2 {
+		return pid.GetID()
+	}
+	return parts[len(parts)-2]
+}
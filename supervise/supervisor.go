@@ -0,0 +1,54 @@
+package supervise
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/Scrimzay/stockspider/metrics"
+
+	"github.com/anthdm/hollywood/actor"
+)
+
+// dead letters of one message type are logged the first time and then
+// every this many, a stopped bus subscriber can produce thousands a second
+const deadLetterLogEvery = 1000
+
+// Supervisor watches the engine's event stream for crashes and dead letters
+type Supervisor struct {
+	log         *slog.Logger
+	deadLetters map[string]int64 // by message type
+}
+
+// Start spawns the supervisor on e
+func Start(e *actor.Engine, log *slog.Logger) *actor.PID {
+	return e.Spawn(func() actor.Receiver {
+		return &Supervisor{log: log, deadLetters: make(map[string]int64)}
+	}, "supervisor", actor.WithID("supervisor"))
+}
+
+func (s *Supervisor) Receive(c *actor.Context) {
+	switch msg := c.Message().(type) {
+	case actor.Started:
+		c.Engine().Subscribe(c.PID())
+	case actor.Stopped:
+		c.Engine().Unsubscribe(c.PID())
+	case actor.ActorRestartedEvent:
+		// hollywood logs the stack itself
+		metrics.Restarted(Kind(msg.PID))
+	case actor.ActorMaxRestartsExceededEvent:
+		kind := Kind(msg.PID)
+		metrics.GaveUp(kind)
+		parent := Parent(msg.PID)
+		s.log.Error("actor crashed past its restart limit", "pid", msg.PID.GetID(), "kind", kind, "escalated", parent != nil)
+		if parent != nil {
+			c.Send(parent, ChildDied{PID: msg.PID})
+		}
+	case actor.DeadLetterEvent:
+		msgType := fmt.Sprintf("%T", msg.Message)
+		metrics.DeadLetter(msgType)
+		s.deadLetters[msgType]++
+		if n := s.deadLetters[msgType]; n == 1 || n%deadLetterLogEvery == 0 {
+			s.log.Warn("dead letter", "target", msg.Target.GetID(), "type", msgType, "count", n)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package supervise
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Scrimzay/stockspider/logging"
+
+	"github.com/anthdm/hollywood/actor"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type lost struct{}
+
+func TestDeadLettersCounted(t *testing.T) {
+	e, err := actor.NewEngine(actor.NewEngineConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid := Start(e, logging.Discard())
+	t.Cleanup(func() { e.Poison(pid).Wait() })
+
+	const msgType = "supervise.lost"
+	before := deadLetters(t, msgType)
+	gone := actor.NewPID(e.Address(), "nobody/here")
+	for i := 0; i < 3; i++ {
+		e.Send(gone, lost{})
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n := deadLetters(t, msgType)
+		if n == before+3 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dead letters for %s went %v -> %v, want +3", msgType, before, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// deadLetters reads stockspider_dead_letters_total for one message type
+func deadLetters(t *testing.T, msgType string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != "stockspider_dead_letters_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "type" && l.GetValue() == msgType {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -42,6 +42,12 @@ var Symbols = map[string]string{
 	"^DJI": "^DJI",
 	"QYLD": "QYLD",
 	"RYLD": "RYLD",
+	"VOD.L":    "VOD.L",
+	"HSBA.L":   "HSBA.L",
+	"BP.L":     "BP.L",
+	"SHOP.TO":  "SHOP.TO",
+	"RY.TO":    "RY.TO",
+	"TD.TO":    "TD.TO",
 }
 
 // Add NASDAQ 100 stocks
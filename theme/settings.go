@@ -0,0 +1,74 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Settings are how the GUI looks, saved whenever they change
+type Settings struct {
+	Theme string `json:"theme"`
+	// Font is a TTF or OTF file to draw text in, "" for raylib's own
+	Font string `json:"font,omitempty"`
+	// Zoom scales the UI on top of the monitor's DPI scale, 1 is as is
+	Zoom float32 `json:"zoom"`
+}
+
+// the zoom steps the View menu and hotkeys go through
+const (
+	MinZoom  = 0.5
+	MaxZoom  = 3
+	ZoomStep = 0.1
+)
+
+func Defaults() Settings {
+	return Settings{Theme: Dark.Name, Zoom: 1}
+}
+
+// ClampZoom keeps z between MinZoom and MaxZoom, rounded to a step
+func ClampZoom(z float32) float32 {
+	steps := int(z/ZoomStep + 0.5)
+	return min(MaxZoom, max(MinZoom, float32(steps)*ZoomStep))
+}
+
+// Load reads the settings at path, a missing file is the defaults
+func Load(path string) (Settings, error) {
+	s := Defaults()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Defaults(), fmt.Errorf("reading %s: %w", path, err)
+	}
+	if _, err := Named(s.Theme); err != nil {
+		s.Theme = Dark.Name
+	}
+	if s.Zoom == 0 {
+		s.Zoom = 1
+	}
+	s.Zoom = ClampZoom(s.Zoom)
+	return s, nil
+}
+
+func Save(path string, s Settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
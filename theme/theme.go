@@ -0,0 +1,139 @@
+// Package theme has the GUI's color palettes and the settings that pick
+// one, along with the font and how big everything is drawn. Colors are
+// plain color.RGBA, which raylib's rl.Color is, so the package doesn't
+// need raylib.
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+type Theme struct {
+	Name string
+	// Background is behind everything, Panel behind a panel's content
+	Background color.RGBA
+	Panel      color.RGBA
+	// Menu is the background of menus and popups, Highlight the row the
+	// mouse or keyboard is on
+	Menu      color.RGBA
+	Highlight color.RGBA
+	Border    color.RGBA
+
+	Text color.RGBA
+	// Muted is for labels and whatever's secondary, Subtle a step less so
+	Muted  color.RGBA
+	Subtle color.RGBA
+	// Accent is what should stand out, the ticker and last price
+	Accent color.RGBA
+
+	// Up and Down are gains and losses, buys and sells, open and closed.
+	// MildUp and MildDown are the steps between them and neutral on a
+	// rating scale
+	Up       color.RGBA
+	Down     color.RGBA
+	MildUp   color.RGBA
+	MildDown color.RGBA
+	Warning  color.RGBA
+	Info     color.RGBA
+
+	// Series tell apart things that aren't better or worse, asset classes
+	// and the kinds of corporate event
+	Series []color.RGBA
+	// Links are the colors of the link groups, in the layout's order
+	Links []color.RGBA
+}
+
+func rgb(r, g, b uint8) color.RGBA {
+	return color.RGBA{r, g, b, 255}
+}
+
+var Dark = Theme{
+	Name:       "dark",
+	Background: rgb(0, 0, 0),
+	Panel:      rgb(0, 0, 0),
+	Menu:       rgb(80, 80, 80),
+	Highlight:  rgb(130, 130, 130),
+	Border:     rgb(130, 130, 130),
+	Text:       rgb(255, 255, 255),
+	Muted:      rgb(130, 130, 130),
+	Subtle:     rgb(200, 200, 200),
+	Accent:     rgb(253, 249, 0),
+	Up:         rgb(0, 228, 48),
+	Down:       rgb(230, 41, 55),
+	MildUp:     rgb(0, 117, 44),
+	MildDown:   rgb(255, 161, 0),
+	Warning:    rgb(255, 161, 0),
+	Info:       rgb(102, 191, 255),
+	Series: []color.RGBA{
+		rgb(102, 191, 255), rgb(255, 161, 0), rgb(200, 122, 255),
+		rgb(255, 203, 0), rgb(255, 109, 194), rgb(0, 158, 47),
+	},
+	Links: []color.RGBA{rgb(230, 41, 55), rgb(0, 228, 48), rgb(102, 191, 255), rgb(253, 249, 0)},
+}
+
+var Light = Theme{
+	Name:       "light",
+	Background: rgb(245, 245, 245),
+	Panel:      rgb(255, 255, 255),
+	Menu:       rgb(225, 225, 225),
+	Highlight:  rgb(190, 205, 225),
+	Border:     rgb(150, 150, 150),
+	Text:       rgb(20, 20, 20),
+	Muted:      rgb(110, 110, 110),
+	Subtle:     rgb(70, 70, 70),
+	Accent:     rgb(170, 110, 0),
+	Up:         rgb(0, 135, 60),
+	Down:       rgb(200, 30, 40),
+	MildUp:     rgb(0, 90, 40),
+	MildDown:   rgb(210, 110, 0),
+	Warning:    rgb(200, 100, 0),
+	Info:       rgb(0, 100, 190),
+	Series: []color.RGBA{
+		rgb(0, 100, 190), rgb(210, 110, 0), rgb(130, 60, 190),
+		rgb(160, 130, 0), rgb(200, 50, 140), rgb(0, 130, 60),
+	},
+	Links: []color.RGBA{rgb(200, 30, 40), rgb(0, 135, 60), rgb(0, 100, 190), rgb(190, 150, 0)},
+}
+
+// Colorblind is dark with blue for up and orange for down instead of green
+// and red, the Okabe-Ito colors, which read the same to most kinds of
+// color blindness
+var Colorblind = Theme{
+	Name:       "colorblind",
+	Background: rgb(0, 0, 0),
+	Panel:      rgb(0, 0, 0),
+	Menu:       rgb(80, 80, 80),
+	Highlight:  rgb(130, 130, 130),
+	Border:     rgb(130, 130, 130),
+	Text:       rgb(255, 255, 255),
+	Muted:      rgb(150, 150, 150),
+	Subtle:     rgb(210, 210, 210),
+	Accent:     rgb(240, 228, 66),
+	Up:         rgb(86, 180, 233),
+	Down:       rgb(230, 159, 0),
+	MildUp:     rgb(0, 114, 178),
+	MildDown:   rgb(200, 150, 80),
+	Warning:    rgb(213, 94, 0),
+	Info:       rgb(86, 180, 233),
+	Series: []color.RGBA{
+		rgb(86, 180, 233), rgb(230, 159, 0), rgb(204, 121, 167),
+		rgb(240, 228, 66), rgb(0, 158, 115), rgb(213, 94, 0),
+	},
+	// the groups keep their names, told apart by more than red and green
+	Links: []color.RGBA{rgb(213, 94, 0), rgb(0, 158, 115), rgb(86, 180, 233), rgb(240, 228, 66)},
+}
+
+// All are the themes to pick from, in menu order
+var All = []Theme{Dark, Light, Colorblind}
+
+// Named is the theme called name
+func Named(name string) (Theme, error) {
+	for _, t := range All {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return Theme{}, fmt.Errorf("no theme %q", name)
+}